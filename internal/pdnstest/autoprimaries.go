@@ -0,0 +1,103 @@
+package pdnstest
+
+import (
+	"net"
+	"net/http"
+	"sort"
+)
+
+// Autoprimary is a primary server allowed to provision zones on the server by
+// sending NOTIFY, as exchanged with the API.
+type Autoprimary struct {
+	IP         string `json:"ip"`
+	Nameserver string `json:"nameserver"`
+	Account    string `json:"account"`
+}
+
+// Autoprimaries returns the autoprimaries of the server, ordered by IP and
+// nameserver.
+func (s *Server) Autoprimaries() []Autoprimary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	autoprimaries := append([]Autoprimary{}, s.autoprimaries...)
+	sort.Slice(autoprimaries, func(i, j int) bool {
+		if autoprimaries[i].IP != autoprimaries[j].IP {
+			return autoprimaries[i].IP < autoprimaries[j].IP
+		}
+		return autoprimaries[i].Nameserver < autoprimaries[j].Nameserver
+	})
+	return autoprimaries
+}
+
+// autoprimaryIndex returns the index of the autoprimary of the given IP and
+// nameserver, or -1 when the server has none.
+func (s *Server) autoprimaryIndex(ip net.IP, nameserver string) int {
+	for i, autoprimary := range s.autoprimaries {
+		if net.ParseIP(autoprimary.IP).Equal(ip) && autoprimary.Nameserver == nameserver {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) listAutoprimaries(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Autoprimaries())
+}
+
+func (s *Server) postAutoprimary(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	var body Autoprimary
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	ip := net.ParseIP(body.IP)
+	if ip == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Unable to convert '"+body.IP+"' to an IP address")
+		return
+	}
+	if body.Nameserver == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Nameserver is not set")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.autoprimaryIndex(ip, body.Nameserver) >= 0 {
+		writeError(w, http.StatusConflict, "Autoprimary "+body.IP+" with nameserver "+body.Nameserver+" already exists")
+		return
+	}
+	s.autoprimaries = append(s.autoprimaries, Autoprimary{IP: ip.String(), Nameserver: body.Nameserver, Account: body.Account})
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteAutoprimary(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	ip := net.ParseIP(r.PathValue("ip"))
+	if ip == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Unable to convert '"+r.PathValue("ip")+"' to an IP address")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.autoprimaryIndex(ip, r.PathValue("nameserver"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.autoprimaries = append(s.autoprimaries[:i], s.autoprimaries[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
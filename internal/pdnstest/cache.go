@@ -0,0 +1,114 @@
+package pdnstest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// cacheEntry is an answer held in the packet cache of one of the servers.
+type cacheEntry struct {
+	recursor bool
+	name     string
+	tpe      string
+}
+
+// cacheFlushResult is the body PowerDNS sends when it flushed a cache.
+type cacheFlushResult struct {
+	Count  int    `json:"count"`
+	Result string `json:"result"`
+}
+
+// CacheAnswer adds the answer to a query for name and type to the cache of
+// the authoritative server, or of the recursor when recursor is set, as
+// resolving the query would.
+func (s *Server) CacheAnswer(recursor bool, name string, tpe string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = append(s.cache, cacheEntry{recursor: recursor, name: canonicalName(name), tpe: strings.ToUpper(tpe)})
+}
+
+// CachedAnswers returns the number of answers in the cache of the
+// authoritative server, or of the recursor when recursor is set.
+func (s *Server) CachedAnswers(recursor bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, entry := range s.cache {
+		if entry.recursor == recursor {
+			count++
+		}
+	}
+	return count
+}
+
+// flushCache removes the answers of the given server matching match from the
+// cache and returns how many it removed. Must be called with s.mu held.
+func (s *Server) flushCache(recursor bool, match func(cacheEntry) bool) int {
+	kept := s.cache[:0]
+	for _, entry := range s.cache {
+		if entry.recursor == recursor && match(entry) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	count := len(s.cache) - len(kept)
+	s.cache = kept
+	return count
+}
+
+// inSubtree reports whether name is domain or a name below it.
+func inSubtree(name string, domain string) bool {
+	return domain == "." || name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// flushAuthCache flushes the answers for the domain of the query and the
+// names below it, as the authoritative server always does.
+func (s *Server) flushAuthCache(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+	domain := r.URL.Query().Get("domain")
+	if err := checkCanonical(domain); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := s.flushCache(false, func(entry cacheEntry) bool {
+		return inSubtree(entry.name, canonicalName(domain))
+	})
+	writeJSON(w, http.StatusOK, cacheFlushResult{Count: count, Result: "Flushed cache."})
+}
+
+// flushRecursorCache flushes the answers for the domain of the query, and the
+// names below it when subtree is "true", optionally limited to a type.
+func (s *Server) flushRecursorCache(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	domain := query.Get("domain")
+	if err := checkCanonical(domain); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	subtree := query.Get("subtree") == "true"
+	tpe := strings.ToUpper(query.Get("type"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := s.flushCache(true, func(entry cacheEntry) bool {
+		if tpe != "" && entry.tpe != tpe {
+			return false
+		}
+		if subtree {
+			return inSubtree(entry.name, canonicalName(domain))
+		}
+		return entry.name == canonicalName(domain)
+	})
+	writeJSON(w, http.StatusOK, cacheFlushResult{Count: count, Result: "Flushed cache."})
+}
@@ -0,0 +1,438 @@
+package pdnstest
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CryptoKey is a DNSSEC key of a zone, as exchanged with the API.
+type CryptoKey struct {
+	Type       string   `json:"type"`
+	ID         int      `json:"id"`
+	KeyType    string   `json:"keytype"`
+	Active     bool     `json:"active"`
+	Published  bool     `json:"published"`
+	DNSKey     string   `json:"dnskey"`
+	DS         []string `json:"ds,omitempty"`
+	PrivateKey string   `json:"privatekey,omitempty"`
+	Algorithm  string   `json:"algorithm"`
+	Bits       int      `json:"bits"`
+	Flags      int      `json:"flags"`
+}
+
+// cryptoKeyRequest is the body of a cryptokey creation. The algorithm may be
+// given by name or number.
+type cryptoKeyRequest struct {
+	KeyType    string          `json:"keytype"`
+	Active     bool            `json:"active"`
+	Published  *bool           `json:"published"`
+	Algorithm  json.RawMessage `json:"algorithm"`
+	Bits       int             `json:"bits"`
+	PrivateKey string          `json:"privatekey"`
+}
+
+// cryptoKeyUpdate is the body of a cryptokey PUT.
+type cryptoKeyUpdate struct {
+	Active    *bool `json:"active"`
+	Published *bool `json:"published"`
+}
+
+// dnssecAlgorithm is a DNSSEC signing algorithm the fake can generate keys for.
+type dnssecAlgorithm struct {
+	number int
+	name   string
+	bits   int // Fixed key size, 0 for RSA
+}
+
+var dnssecAlgorithms = []dnssecAlgorithm{
+	{number: 8, name: "RSASHA256"},
+	{number: 10, name: "RSASHA512"},
+	{number: 13, name: "ECDSAP256SHA256", bits: 256},
+	{number: 14, name: "ECDSAP384SHA384", bits: 384},
+	{number: 15, name: "ED25519", bits: 256},
+}
+
+// dnssecAlgorithmAliases are the short algorithm names accepted by PowerDNS.
+var dnssecAlgorithmAliases = map[string]int{
+	"rsasha256": 8,
+	"rsasha512": 10,
+	"ecdsa256":  13,
+	"ecdsa384":  14,
+	"ed25519":   15,
+}
+
+// lookupAlgorithm resolves an algorithm given by name, alias or number,
+// defaulting to ECDSAP256SHA256 like PowerDNS.
+func lookupAlgorithm(raw json.RawMessage) (dnssecAlgorithm, *apiError) {
+	value := strings.Trim(string(raw), `"`)
+	if value == "" || value == "null" {
+		value = "13"
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		number = dnssecAlgorithmAliases[strings.ToLower(value)]
+	}
+	for _, algorithm := range dnssecAlgorithms {
+		if algorithm.number == number || strings.EqualFold(algorithm.name, value) {
+			return algorithm, nil
+		}
+	}
+	return dnssecAlgorithm{}, errorf(http.StatusUnprocessableEntity, "Unknown algorithm: %s", value)
+}
+
+// cryptoKey is the state of a DNSSEC key.
+type cryptoKey struct {
+	info      CryptoKey
+	algorithm dnssecAlgorithm
+	publicKey []byte // Public key field of the DNSKEY record
+}
+
+// keyFlags returns the DNSKEY flags of a key type: the zone key bit, plus the
+// secure entry point bit for key signing keys.
+func keyFlags(keyType string) int {
+	if keyType == "zsk" {
+		return 256
+	}
+	return 257
+}
+
+// newCryptoKey generates a key, or imports privateKey when set.
+func newCryptoKey(id int, zoneName string, keyType string, algorithm dnssecAlgorithm, bits int, privateKey string) (*cryptoKey, *apiError) {
+	var public []byte
+	var err error
+	if privateKey == "" {
+		privateKey, public, bits, err = generateKey(algorithm, bits)
+	} else {
+		public, bits, err = importKey(algorithm, privateKey)
+	}
+	if err != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "%s", err)
+	}
+
+	key := &cryptoKey{algorithm: algorithm, publicKey: public}
+	key.info = CryptoKey{
+		Type:       "Cryptokey",
+		ID:         id,
+		KeyType:    keyType,
+		PrivateKey: privateKey,
+		Algorithm:  algorithm.name,
+		Bits:       bits,
+		Flags:      keyFlags(keyType),
+	}
+	key.info.DNSKey = fmt.Sprintf("%d 3 %d %s", key.info.Flags, algorithm.number, base64.StdEncoding.EncodeToString(public))
+	if keyType != "zsk" {
+		key.info.DS = key.ds(zoneName)
+	}
+	return key, nil
+}
+
+// generateKey creates a new key pair, returning the private key in the BIND
+// format PowerDNS uses and the DNSKEY public key.
+func generateKey(algorithm dnssecAlgorithm, bits int) (string, []byte, int, error) {
+	header := fmt.Sprintf("Private-key-format: v1.2\nAlgorithm: %d (%s)\n", algorithm.number, algorithm.name)
+
+	switch algorithm.number {
+	case 13, 14:
+		curve := ecdh.P256()
+		if algorithm.number == 14 {
+			curve = ecdh.P384()
+		}
+		key, err := curve.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return header + "PrivateKey: " + base64.StdEncoding.EncodeToString(key.Bytes()) + "\n", key.PublicKey().Bytes()[1:], algorithm.bits, nil
+	case 15:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return header + "PrivateKey: " + base64.StdEncoding.EncodeToString(key.Seed()) + "\n", key.Public().(ed25519.PublicKey), algorithm.bits, nil
+	default:
+		if bits == 0 {
+			bits = 2048
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		fields := []struct {
+			name  string
+			value *big.Int
+		}{
+			{"Modulus", key.N},
+			{"PublicExponent", big.NewInt(int64(key.E))},
+			{"PrivateExponent", key.D},
+			{"Prime1", key.Primes[0]},
+			{"Prime2", key.Primes[1]},
+			{"Exponent1", key.Precomputed.Dp},
+			{"Exponent2", key.Precomputed.Dq},
+			{"Coefficient", key.Precomputed.Qinv},
+		}
+		private := header
+		for _, field := range fields {
+			private += field.name + ": " + base64.StdEncoding.EncodeToString(field.value.Bytes()) + "\n"
+		}
+		return private, rsaPublicKey(key.N, key.E), bits, nil
+	}
+}
+
+// importKey derives the DNSKEY public key from a private key in BIND format.
+func importKey(algorithm dnssecAlgorithm, privateKey string) ([]byte, int, error) {
+	fields := make(map[string][]byte)
+	for _, line := range strings.Split(privateKey, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err == nil {
+			fields[strings.TrimSpace(name)] = decoded
+		}
+	}
+
+	switch algorithm.number {
+	case 13, 14:
+		curve := ecdh.P256()
+		if algorithm.number == 14 {
+			curve = ecdh.P384()
+		}
+		key, err := curve.NewPrivateKey(fields["PrivateKey"])
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid private key: %w", err)
+		}
+		return key.PublicKey().Bytes()[1:], algorithm.bits, nil
+	case 15:
+		if len(fields["PrivateKey"]) != ed25519.SeedSize {
+			return nil, 0, fmt.Errorf("invalid private key: bad seed length")
+		}
+		key := ed25519.NewKeyFromSeed(fields["PrivateKey"])
+		return key.Public().(ed25519.PublicKey), algorithm.bits, nil
+	default:
+		modulus, exponent := fields["Modulus"], fields["PublicExponent"]
+		if len(modulus) == 0 || len(exponent) == 0 {
+			return nil, 0, fmt.Errorf("invalid private key: missing modulus or exponent")
+		}
+		n := new(big.Int).SetBytes(modulus)
+		return rsaPublicKey(n, int(new(big.Int).SetBytes(exponent).Int64())), n.BitLen(), nil
+	}
+}
+
+// rsaPublicKey encodes an RSA public key for a DNSKEY record, see RFC 3110.
+func rsaPublicKey(n *big.Int, e int) []byte {
+	exponent := big.NewInt(int64(e)).Bytes()
+	var public []byte
+	if len(exponent) < 256 {
+		public = append(public, byte(len(exponent)))
+	} else {
+		public = append(public, 0, byte(len(exponent)>>8), byte(len(exponent)))
+	}
+	public = append(public, exponent...)
+	return append(public, n.Bytes()...)
+}
+
+// rdata returns the wire format of the DNSKEY record of the key.
+func (k *cryptoKey) rdata() []byte {
+	rdata := []byte{byte(k.info.Flags >> 8), byte(k.info.Flags), 3, byte(k.algorithm.number)}
+	return append(rdata, k.publicKey...)
+}
+
+// keyTag computes the key tag of the key, see RFC 4034 appendix B.
+func (k *cryptoKey) keyTag() uint16 {
+	var sum uint32
+	for i, b := range k.rdata() {
+		if i&1 == 0 {
+			sum += uint32(b) << 8
+		} else {
+			sum += uint32(b)
+		}
+	}
+	sum += sum >> 16
+	return uint16(sum)
+}
+
+// ds returns the DS records of the key for its SHA-256 and SHA-384 digests.
+func (k *cryptoKey) ds(zoneName string) []string {
+	owner := wireName(zoneName)
+	digests := []struct {
+		number int
+		hash   func() hash.Hash
+	}{
+		{2, sha256.New},
+		{4, sha512.New384},
+	}
+
+	ds := make([]string, 0, len(digests))
+	for _, digest := range digests {
+		h := digest.hash()
+		h.Write(owner)
+		h.Write(k.rdata())
+		ds = append(ds, fmt.Sprintf("%d %d %d %s", k.keyTag(), k.algorithm.number, digest.number, hex.EncodeToString(h.Sum(nil))))
+	}
+	return ds
+}
+
+// wireName returns the wire format of a domain name in canonical form.
+func wireName(name string) []byte {
+	var wire []byte
+	for _, label := range strings.Split(strings.TrimSuffix(canonicalName(name), "."), ".") {
+		if label == "" {
+			continue
+		}
+		wire = append(wire, byte(len(label)))
+		wire = append(wire, label...)
+	}
+	return append(wire, 0)
+}
+
+// view returns the key as sent by the API, with its private key when
+// withPrivateKey.
+func (k *cryptoKey) view(withPrivateKey bool) CryptoKey {
+	info := k.info
+	info.DS = append([]string(nil), k.info.DS...)
+	if !withPrivateKey {
+		info.PrivateKey = ""
+	}
+	return info
+}
+
+// secureZone adds a default combined signing key to z, like PowerDNS does for
+// zones created or updated with dnssec enabled. Must be called with s.mu held.
+func (s *Server) secureZone(z *zone) *apiError {
+	algorithm, _ := lookupAlgorithm(nil)
+	key, err := newCryptoKey(s.nextCryptoKey, z.info.Name, "csk", algorithm, 0, "")
+	if err != nil {
+		return err
+	}
+	s.nextCryptoKey++
+	key.info.Active = true
+	key.info.Published = true
+	z.cryptoKeys = append(z.cryptoKeys, key)
+	return nil
+}
+
+// CryptoKeys returns the DNSSEC keys of zone, with their private keys.
+func (s *Server) CryptoKeys(zoneName string) []CryptoKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.zones[canonicalName(zoneName)]
+	if !ok {
+		return nil
+	}
+	keys := make([]CryptoKey, len(z.cryptoKeys))
+	for i, key := range z.cryptoKeys {
+		keys[i] = key.view(true)
+	}
+	return keys
+}
+
+// lookupCryptoKey finds the key of the request path in z.
+func lookupCryptoKey(r *http.Request, z *zone) (int, *cryptoKey) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return -1, nil
+	}
+	for i, key := range z.cryptoKeys {
+		if key.info.ID == id {
+			return i, key
+		}
+	}
+	return -1, nil
+}
+
+func (s *Server) listCryptoKeys(w http.ResponseWriter, r *http.Request, z *zone) {
+	keys := make([]CryptoKey, len(z.cryptoKeys))
+	for i, key := range z.cryptoKeys {
+		keys[i] = key.view(false)
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (s *Server) postCryptoKey(w http.ResponseWriter, r *http.Request, z *zone) {
+	var body cryptoKeyRequest
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	keyType := strings.ToLower(body.KeyType)
+	if keyType != "ksk" && keyType != "zsk" && keyType != "csk" {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid keytype "+body.KeyType)
+		return
+	}
+	algorithm, apiErr := lookupAlgorithm(body.Algorithm)
+	if apiErr != nil {
+		writeAPIError(w, apiErr)
+		return
+	}
+	if algorithm.bits != 0 && body.Bits != 0 && body.Bits != algorithm.bits {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("The algorithm does not support the given bit size: %d", body.Bits))
+		return
+	}
+
+	key, apiErr := newCryptoKey(s.nextCryptoKey, z.info.Name, keyType, algorithm, body.Bits, body.PrivateKey)
+	if apiErr != nil {
+		writeAPIError(w, apiErr)
+		return
+	}
+	s.nextCryptoKey++
+	key.info.Active = body.Active
+	key.info.Published = body.Published == nil || *body.Published
+	z.cryptoKeys = append(z.cryptoKeys, key)
+
+	writeJSON(w, http.StatusCreated, key.view(true))
+}
+
+func (s *Server) getCryptoKey(w http.ResponseWriter, r *http.Request, z *zone) {
+	_, key := lookupCryptoKey(r, z)
+	if key == nil {
+		writeError(w, http.StatusNotFound, "Could not find cryptokey with id "+r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, key.view(true))
+}
+
+func (s *Server) putCryptoKey(w http.ResponseWriter, r *http.Request, z *zone) {
+	var body cryptoKeyUpdate
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	_, key := lookupCryptoKey(r, z)
+	if key == nil {
+		writeError(w, http.StatusNotFound, "Could not find cryptokey with id "+r.PathValue("id"))
+		return
+	}
+
+	if body.Active != nil {
+		key.info.Active = *body.Active
+	}
+	if body.Published != nil {
+		key.info.Published = *body.Published
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteCryptoKey(w http.ResponseWriter, r *http.Request, z *zone) {
+	i, key := lookupCryptoKey(r, z)
+	if key == nil {
+		writeError(w, http.StatusNotFound, "Could not find cryptokey with id "+r.PathValue("id"))
+		return
+	}
+	z.cryptoKeys = append(z.cryptoKeys[:i], z.cryptoKeys[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,98 @@
+package pdnstest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MessageNoRecordEditing is the error reported by backends that can't edit
+// records through the API, such as LMDB in some setups.
+const MessageNoRecordEditing = "Hosting backend does not support editing records."
+
+// Fault makes the servers misbehave on the requests it matches.
+type Fault struct {
+	Recursor bool          // Match requests to the recursor instead of the authoritative server
+	Method   string        // Request method to match, any when empty
+	Path     string        // Substring of the request path to match, any when empty
+	Delay    time.Duration // Delay before the request is handled
+	Status   int           // Status to respond with instead of handling the request, 0 to handle it
+	Message  string        // Error message sent with Status, its status text when empty
+	Times    int           // Number of matching requests affected, 0 for all of them
+}
+
+// Inject adds f to the faults of the servers. Faults are matched in the order
+// they were injected, the first matching one applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults and lets records be edited again.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+	s.recordEditsOff = make(map[string]bool)
+}
+
+// RejectRecordEdits makes rrset PATCHes with one of the given change types
+// fail the way LMDB backends do, with MessageNoRecordEditing. All change types
+// are rejected when none is given.
+func (s *Server) RejectRecordEdits(changeTypes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(changeTypes) == 0 {
+		changeTypes = []string{changeTypeReplace, changeTypeDelete}
+	}
+	for _, changeType := range changeTypes {
+		s.recordEditsOff[strings.ToUpper(changeType)] = true
+	}
+}
+
+// matchFault returns the first fault matching r, consuming one of its uses.
+// Must be called with s.mu held.
+func (s *Server) matchFault(recursor bool, r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Recursor != recursor ||
+			(f.Method != "" && !strings.EqualFold(f.Method, r.Method)) ||
+			!strings.Contains(r.URL.Path, f.Path) {
+			continue
+		}
+
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// apply delays r and writes the faulty response, if any. It reports whether r
+// should still be handled.
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return false
+		}
+	}
+
+	if f.Status == 0 {
+		return true
+	}
+	message := f.Message
+	if message == "" {
+		message = http.StatusText(f.Status)
+	}
+	writeError(w, f.Status, message)
+	return false
+}
@@ -0,0 +1,144 @@
+package pdnstest
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Metadata is the list of values of one metadata kind of a zone.
+type Metadata struct {
+	Type     string   `json:"type"`
+	Kind     string   `json:"kind"`
+	Metadata []string `json:"metadata"`
+}
+
+// metadataKinds are the known metadata kinds, mapped to whether the API may
+// change them. Kinds prefixed with "X-" are always allowed.
+var metadataKinds = map[string]bool{
+	"ALLOW-AXFR-FROM":          true,
+	"ALLOW-DNSUPDATE-FROM":     true,
+	"ALSO-NOTIFY":              true,
+	"API-RECTIFY":              false,
+	"AXFR-MASTER-TSIG":         false,
+	"AXFR-SOURCE":              true,
+	"ENABLE-LUA-RECORDS":       true,
+	"FORWARD-DNSUPDATE":        true,
+	"GSS-ACCEPTOR-PRINCIPAL":   true,
+	"GSS-ALLOW-AXFR-PRINCIPAL": true,
+	"IXFR":                     true,
+	"LUA-AXFR-SCRIPT":          false,
+	"NOTIFY-DNSUPDATE":         true,
+	"NSEC3NARROW":              false,
+	"NSEC3PARAM":               false,
+	"PRESIGNED":                false,
+	"PUBLISH-CDNSKEY":          true,
+	"PUBLISH-CDS":              true,
+	"SIGNALING-ZONE":           true,
+	"SLAVE-RENOTIFY":           true,
+	"SOA-EDIT":                 true,
+	"SOA-EDIT-DNSUPDATE":       true,
+	"TSIG-ALLOW-AXFR":          false,
+	"TSIG-ALLOW-DNSUPDATE":     true,
+}
+
+// checkMetadataKind rejects unknown kinds, and kinds the API may not change
+// unless readOnly.
+func checkMetadataKind(kind string, readOnly bool) *apiError {
+	if strings.HasPrefix(kind, "X-") {
+		return nil
+	}
+	writable, known := metadataKinds[kind]
+	if !known || (!readOnly && !writable) {
+		return errorf(http.StatusUnprocessableEntity, "Unsupported metadata kind '%s'", kind)
+	}
+	return nil
+}
+
+// ZoneMetadata returns the values of a metadata kind of zone.
+func (s *Server) ZoneMetadata(zoneName string, kind string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.zones[canonicalName(zoneName)]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), z.metadata[strings.ToUpper(kind)]...)
+}
+
+func metadataView(kind string, values []string) Metadata {
+	return Metadata{Type: "Metadata", Kind: kind, Metadata: append([]string{}, values...)}
+}
+
+func (s *Server) listMetadata(w http.ResponseWriter, r *http.Request, z *zone) {
+	metadata := []Metadata{}
+	for kind, values := range z.metadata {
+		metadata = append(metadata, metadataView(kind, values))
+	}
+	sort.Slice(metadata, func(i, j int) bool { return metadata[i].Kind < metadata[j].Kind })
+	writeJSON(w, http.StatusOK, metadata)
+}
+
+func (s *Server) postMetadata(w http.ResponseWriter, r *http.Request, z *zone) {
+	var body Metadata
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	kind := strings.ToUpper(body.Kind)
+	if err := checkMetadataKind(kind, false); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	// New values are added to the existing ones
+	values := z.metadata[kind]
+	for _, value := range body.Metadata {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	z.metadata[kind] = values
+	writeJSON(w, http.StatusCreated, metadataView(kind, values))
+}
+
+func (s *Server) getMetadata(w http.ResponseWriter, r *http.Request, z *zone) {
+	kind := strings.ToUpper(r.PathValue("kind"))
+	if err := checkMetadataKind(kind, true); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, metadataView(kind, z.metadata[kind]))
+}
+
+func (s *Server) putMetadata(w http.ResponseWriter, r *http.Request, z *zone) {
+	var body Metadata
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	kind := strings.ToUpper(r.PathValue("kind"))
+	if err := checkMetadataKind(kind, false); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	if len(body.Metadata) == 0 {
+		delete(z.metadata, kind)
+	} else {
+		z.metadata[kind] = append([]string{}, body.Metadata...)
+	}
+	writeJSON(w, http.StatusOK, metadataView(kind, z.metadata[kind]))
+}
+
+func (s *Server) deleteMetadata(w http.ResponseWriter, r *http.Request, z *zone) {
+	kind := strings.ToUpper(r.PathValue("kind"))
+	if err := checkMetadataKind(kind, false); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	delete(z.metadata, kind)
+	w.WriteHeader(http.StatusNoContent)
+}
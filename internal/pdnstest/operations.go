@@ -0,0 +1,62 @@
+package pdnstest
+
+import (
+	"net/http"
+)
+
+// HoldTransfers makes the server queue the transfers requested through
+// axfr-retrieve instead of completing them at once, as a slow primary would.
+// Releasing the hold completes the queued transfers.
+func (s *Server) HoldTransfers(hold bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transfersHeld = hold
+	if hold {
+		return
+	}
+	for _, z := range s.zones {
+		if z.transferPending {
+			z.transfer()
+		}
+	}
+}
+
+// operationResult is the body PowerDNS sends when an operation on a zone or
+// on the server succeeds.
+type operationResult struct {
+	Result string `json:"result"`
+}
+
+func (s *Server) rectifyZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	if z.info.Presigned {
+		writeError(w, http.StatusUnprocessableEntity, "Zone '"+z.info.Name+"' is pre-signed, not rectifying.")
+		return
+	}
+	writeJSON(w, http.StatusOK, operationResult{Result: "Rectified"})
+}
+
+func (s *Server) notifyZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	z.info.NotifiedSerial = z.info.Serial
+	writeJSON(w, http.StatusOK, operationResult{Result: "Notification queued"})
+}
+
+func (s *Server) axfrRetrieveZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	if len(z.info.Masters) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Domain '"+z.info.Name+"' is not a secondary domain (or has no primary defined)")
+		return
+	}
+
+	z.transferPending = true
+	if !s.transfersHeld {
+		z.transfer()
+	}
+	writeJSON(w, http.StatusOK, operationResult{Result: "Added retrieval request for '" + z.info.Name + "' from primary " + z.info.Masters[0]})
+}
+
+// transfer completes a transfer of z from its primary, which always has a
+// newer version of the zone.
+func (z *zone) transfer() {
+	z.transferPending = false
+	z.bumpSerial()
+}
@@ -0,0 +1,189 @@
+package pdnstest
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RecursorZone is a zone of the recursor, as exchanged with the API.
+type RecursorZone struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	URL              string   `json:"url"`
+	Kind             string   `json:"kind"`
+	Servers          []string `json:"servers"`
+	RecursionDesired bool     `json:"recursion_desired"`
+	RRSets           []RRSet  `json:"rrsets,omitempty"`
+}
+
+// recursorZoneKinds maps the lower case recursor zone kinds to their
+// canonical spelling.
+var recursorZoneKinds = map[string]string{
+	"native":    "Native",
+	"forwarded": "Forwarded",
+}
+
+// RecursorZone returns the recursor zone of the given name.
+func (s *Server) RecursorZone(name string) (RecursorZone, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.recursorZones[canonicalName(name)]
+	if !ok {
+		return RecursorZone{}, false
+	}
+	return copyRecursorZone(*z), true
+}
+
+func copyRecursorZone(z RecursorZone) RecursorZone {
+	z.Servers = append([]string{}, z.Servers...)
+	z.RRSets = append([]RRSet(nil), z.RRSets...)
+	return z
+}
+
+// checkRecursorZone validates a zone sent to the recursor and normalizes it
+// the way the recursor does, adding the default port to servers.
+func (s *Server) checkRecursorZone(z *RecursorZone) *apiError {
+	if err := checkCanonical(z.Name); err != nil {
+		return err
+	}
+	kind, ok := recursorZoneKinds[strings.ToLower(z.Kind)]
+	if !ok {
+		return errorf(http.StatusUnprocessableEntity, "Invalid zone kind '%s'", z.Kind)
+	}
+	if kind == "Forwarded" && len(z.Servers) == 0 {
+		return errorf(http.StatusUnprocessableEntity, "Need at least one upstream server when forwarding")
+	}
+
+	name := canonicalName(z.Name)
+	z.ID = name
+	z.Name = name
+	z.Type = "Zone"
+	z.URL = fmt.Sprintf("/api/v1/servers/%s/zones/%s", s.serverID, name)
+	z.Kind = kind
+	servers := make([]string, len(z.Servers))
+	for i, server := range z.Servers {
+		if _, _, err := net.SplitHostPort(server); err == nil {
+			servers[i] = server
+			continue
+		}
+		if net.ParseIP(server) == nil {
+			return errorf(http.StatusUnprocessableEntity, "Unable to convert '%s' to a server address", server)
+		}
+		servers[i] = net.JoinHostPort(server, "53")
+	}
+	z.Servers = servers
+	return nil
+}
+
+// recursorHandler routes the requests to the recursor.
+func (s *Server) recursorHandler() http.Handler {
+	mux := http.NewServeMux()
+	s.handleServers(mux, "recursor", s.recursorVersion)
+
+	zones := "/api/v1/servers/{server}/zones"
+	zone := zones + "/{zone}"
+	mux.HandleFunc("GET "+zones, s.listRecursorZones)
+	mux.HandleFunc("POST "+zones, s.postRecursorZone)
+	mux.HandleFunc("GET "+zone, s.withRecursorZone(s.getRecursorZone))
+	mux.HandleFunc("PUT "+zone, s.withRecursorZone(s.putRecursorZone))
+	mux.HandleFunc("DELETE "+zone, s.withRecursorZone(s.deleteRecursorZone))
+
+	mux.HandleFunc("PUT /api/v1/servers/{server}/cache/flush", s.flushRecursorCache)
+
+	return s.serve(true, mux)
+}
+
+// recursorZoneHandler handles a request on an existing recursor zone, with
+// s.mu held.
+type recursorZoneHandler func(w http.ResponseWriter, r *http.Request, z *RecursorZone)
+
+// withRecursorZone looks up the recursor zone of the request path before
+// calling handle.
+func (s *Server) withRecursorZone(handle recursorZoneHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.checkServer(w, r) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		z, ok := s.recursorZones[zoneID(r.PathValue("zone"))]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		handle(w, r, z)
+	}
+}
+
+func (s *Server) listRecursorZones(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zones := []RecursorZone{}
+	for _, z := range s.recursorZones {
+		zones = append(zones, copyRecursorZone(*z))
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	writeJSON(w, http.StatusOK, zones)
+}
+
+func (s *Server) postRecursorZone(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	var z RecursorZone
+	if err := decodeBody(r, &z); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if err := s.checkRecursorZone(&z); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.recursorZones[z.Name]; exists {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Zone '%s' already exists", z.Name))
+		return
+	}
+	s.recursorZones[z.Name] = &z
+	writeJSON(w, http.StatusCreated, copyRecursorZone(z))
+}
+
+func (s *Server) getRecursorZone(w http.ResponseWriter, r *http.Request, z *RecursorZone) {
+	writeJSON(w, http.StatusOK, copyRecursorZone(*z))
+}
+
+func (s *Server) putRecursorZone(w http.ResponseWriter, r *http.Request, z *RecursorZone) {
+	var updated RecursorZone
+	if err := decodeBody(r, &updated); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	updated.Name = z.Name
+	if err := s.checkRecursorZone(&updated); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	*z = updated
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteRecursorZone(w http.ResponseWriter, r *http.Request, z *RecursorZone) {
+	delete(s.recursorZones, z.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
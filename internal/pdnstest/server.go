@@ -0,0 +1,341 @@
+// Package pdnstest provides an in-process fake of the PowerDNS authoritative
+// server and recursor HTTP APIs, keeping its state in memory, to exercise the
+// provider without running PowerDNS.
+package pdnstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Defaults of the fake servers.
+const (
+	DefaultAPIKey          = "secret"
+	DefaultServerID        = "localhost"
+	DefaultVersion         = "4.9.0"
+	DefaultRecursorVersion = "5.1.0"
+)
+
+// Server fakes a PowerDNS authoritative server and a PowerDNS recursor, each
+// listening on its own address.
+type Server struct {
+	URL         string // Base URL of the authoritative server
+	RecursorURL string // Base URL of the recursor
+
+	apiKey          string
+	serverID        string
+	version         string
+	recursorVersion string
+	authAddr        string
+	recursorAddr    string
+
+	auth     *httptest.Server
+	recursor *httptest.Server
+
+	mu             sync.Mutex
+	zones          map[string]*zone
+	recursorZones  map[string]*RecursorZone
+	tsigKeys       map[string]*TSIGKey
+	autoprimaries  []Autoprimary
+	cache          []cacheEntry
+	faults         []*Fault
+	requests       []Request
+	nextCryptoKey  int
+	recordEditsOff map[string]bool
+	transfersHeld  bool
+}
+
+// Option customizes a Server.
+type Option func(*Server)
+
+// WithAPIKey sets the API key both servers expect in the X-API-Key header.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithServerID sets the server ID used in the API paths of both servers.
+func WithServerID(serverID string) Option {
+	return func(s *Server) {
+		s.serverID = serverID
+	}
+}
+
+// WithVersion sets the version reported by the authoritative server.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// WithRecursorVersion sets the version reported by the recursor.
+func WithRecursorVersion(version string) Option {
+	return func(s *Server) {
+		s.recursorVersion = version
+	}
+}
+
+// WithAddrs makes the servers listen on fixed addresses, e.g.
+// "127.0.0.1:8081", instead of random local ports.
+func WithAddrs(authAddr string, recursorAddr string) Option {
+	return func(s *Server) {
+		s.authAddr = authAddr
+		s.recursorAddr = recursorAddr
+	}
+}
+
+// Start starts a fake authoritative server and recursor. They must be stopped
+// with Close.
+func Start(opts ...Option) (*Server, error) {
+	s := &Server{
+		apiKey:          DefaultAPIKey,
+		serverID:        DefaultServerID,
+		version:         DefaultVersion,
+		recursorVersion: DefaultRecursorVersion,
+		zones:           make(map[string]*zone),
+		recursorZones:   make(map[string]*RecursorZone),
+		tsigKeys:        make(map[string]*TSIGKey),
+		nextCryptoKey:   1,
+		recordEditsOff:  make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	var err error
+	s.auth, err = s.listen(s.authAddr, s.authHandler())
+	if err != nil {
+		return nil, fmt.Errorf("error starting the authoritative server: %w", err)
+	}
+	s.recursor, err = s.listen(s.recursorAddr, s.recursorHandler())
+	if err != nil {
+		s.auth.Close()
+		return nil, fmt.Errorf("error starting the recursor: %w", err)
+	}
+
+	s.URL = s.auth.URL
+	s.RecursorURL = s.recursor.URL
+	return s, nil
+}
+
+// New starts a fake authoritative server and recursor, stopped when t ends.
+func New(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	s, err := Start(opts...)
+	if err != nil {
+		t.Fatalf("pdnstest: %s", err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close stops both servers.
+func (s *Server) Close() {
+	s.auth.Close()
+	s.recursor.Close()
+}
+
+// APIKey returns the API key expected by both servers.
+func (s *Server) APIKey() string {
+	return s.apiKey
+}
+
+func (s *Server) listen(addr string, handler http.Handler) (*httptest.Server, error) {
+	if addr == "" {
+		return httptest.NewServer(handler), nil
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	server := httptest.NewUnstartedServer(handler)
+	_ = server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	return server, nil
+}
+
+// Request is a request received by one of the servers.
+type Request struct {
+	Recursor bool // Received by the recursor rather than the authoritative server
+	Method   string
+	Path     string
+	RawQuery string
+	Body     []byte
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests forgets the requests received so far.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// serve wraps the routes of one server with request logging, authentication
+// and fault injection.
+func (s *Server) serve(recursor bool, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Unable to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Recursor: recursor,
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			RawQuery: r.URL.RawQuery,
+			Body:     body,
+		})
+		fault := s.matchFault(recursor, r)
+		s.mu.Unlock()
+
+		if fault != nil && !fault.apply(w, r) {
+			return
+		}
+
+		if r.Header.Get("X-API-Key") != s.apiKey {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serverInfo is the description of a server returned by /servers.
+type serverInfo struct {
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	DaemonType string `json:"daemon_type"`
+	Version    string `json:"version"`
+	URL        string `json:"url"`
+	ConfigURL  string `json:"config_url"`
+	ZonesURL   string `json:"zones_url"`
+}
+
+func (s *Server) serverInfo(daemonType string, version string) serverInfo {
+	url := "/api/v1/servers/" + s.serverID
+	return serverInfo{
+		Type:       "Server",
+		ID:         s.serverID,
+		DaemonType: daemonType,
+		Version:    version,
+		URL:        url,
+		ConfigURL:  url + "/config{/config_setting}",
+		ZonesURL:   url + "/zones{/zone}",
+	}
+}
+
+// handleServers registers the discovery endpoints shared by both servers.
+func (s *Server) handleServers(mux *http.ServeMux, daemonType string, version string) {
+	mux.HandleFunc("GET /api", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{{"url": "/api/v1", "version": 1}})
+	})
+	mux.HandleFunc("GET /api/v1/servers", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []serverInfo{s.serverInfo(daemonType, version)})
+	})
+	mux.HandleFunc("GET /api/v1/servers/{server}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.checkServer(w, r) {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.serverInfo(daemonType, version))
+	})
+}
+
+// checkServer writes a 404 when the request targets an unknown server ID.
+func (s *Server) checkServer(w http.ResponseWriter, r *http.Request) bool {
+	if r.PathValue("server") != s.serverID {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return false
+	}
+	return true
+}
+
+// apiError is an error reported to the client with an HTTP status.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...interface{}) *apiError {
+	return &apiError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// errorResponse is the body PowerDNS sends along with an error status.
+type errorResponse struct {
+	Error  string   `json:"error"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeAPIError(w http.ResponseWriter, err *apiError) {
+	writeError(w, err.status, err.message)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// decodeBody decodes the JSON body of r into v, reporting malformed bodies
+// the way PowerDNS does.
+func decodeBody(r *http.Request, v interface{}) *apiError {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "JSON Parse Error: %s", err)
+	}
+	return nil
+}
+
+// canonicalName returns the key under which name is stored: lower case, with
+// a trailing dot.
+func canonicalName(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+// zoneID decodes a zone ID from an API path into a zone name, PowerDNS
+// escaping '/' as "=2F".
+func zoneID(raw string) string {
+	return canonicalName(strings.ReplaceAll(raw, "=2F", "/"))
+}
+
+// checkCanonical rejects names without a trailing dot.
+func checkCanonical(name string) *apiError {
+	if name == "" || !strings.HasSuffix(name, ".") {
+		return errorf(http.StatusUnprocessableEntity, "DNS Name '%s' is not canonical", name)
+	}
+	return nil
+}
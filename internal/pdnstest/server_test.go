@@ -0,0 +1,610 @@
+package pdnstest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// call sends a request to the fake and decodes the response body into out,
+// when given.
+func call(t *testing.T, s *Server, recursor bool, method string, path string, body interface{}, out interface{}) int {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(encoded)
+	}
+
+	base := s.URL
+	if recursor {
+		base = s.RecursorURL
+	}
+	req, err := http.NewRequest(method, base+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", s.APIKey())
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+const zonesPath = "/api/v1/servers/localhost/zones"
+
+func createTestZone(t *testing.T, s *Server) {
+	t.Helper()
+	_, err := s.CreateZone(Zone{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	s := New(t)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/api/v1/servers", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "wrong")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var servers []serverInfo
+	assert.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, "/api/v1/servers", nil, &servers))
+	require.Len(t, servers, 1)
+	assert.Equal(t, "authoritative", servers[0].DaemonType)
+	assert.Equal(t, DefaultVersion, servers[0].Version)
+
+	var recursor serverInfo
+	assert.Equal(t, http.StatusOK, call(t, s, true, http.MethodGet, "/api/v1/servers/localhost", nil, &recursor))
+	assert.Equal(t, "recursor", recursor.DaemonType)
+	assert.Equal(t, DefaultRecursorVersion, recursor.Version)
+}
+
+func TestServer_Zones(t *testing.T) {
+	s := New(t)
+
+	var created Zone
+	status := call(t, s, false, http.MethodPost, zonesPath, Zone{Name: "Example.com.", Kind: "native", Nameservers: []string{"ns1.example.com."}}, &created)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "example.com.", created.Name)
+	assert.Equal(t, "Native", created.Kind)
+	assert.Equal(t, int64(1), created.Serial)
+	require.Len(t, created.RRSets, 2)
+	assert.Equal(t, "NS", created.RRSets[0].Type)
+	assert.Equal(t, "SOA", created.RRSets[1].Type)
+
+	var apiErr errorResponse
+	assert.Equal(t, http.StatusConflict, call(t, s, false, http.MethodPost, zonesPath, Zone{Name: "example.com.", Kind: "Native"}, &apiErr))
+	assert.Equal(t, "Domain 'example.com.' already exists", apiErr.Error)
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, zonesPath, Zone{Name: "example.org", Kind: "Native"}, nil))
+
+	var zones []Zone
+	assert.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, zonesPath, nil, &zones))
+	require.Len(t, zones, 1)
+	assert.Empty(t, zones[0].RRSets)
+
+	update := map[string]interface{}{"kind": "Master", "account": "admin"}
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonesPath+"/example.com", update, nil))
+	zone, ok := s.Zone("example.com")
+	require.True(t, ok)
+	assert.Equal(t, "Master", zone.Kind)
+	assert.Equal(t, "admin", zone.Account)
+	assert.Equal(t, "DEFAULT", zone.SOAEditAPI)
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodDelete, zonesPath+"/example.com.", nil, nil))
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodGet, zonesPath+"/example.com.", nil, nil))
+}
+
+func TestServer_PatchRRSets(t *testing.T) {
+	tests := []struct {
+		name          string
+		rrSets        []map[string]interface{}
+		expectedError string
+		check         func(t *testing.T, s *Server)
+	}{
+		{
+			name: "replace",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com.", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "192.0.2.1"}, {Content: "192.0.2.2", Disabled: true}}},
+			},
+			check: func(t *testing.T, s *Server) {
+				rrSet, ok := s.RRSet("example.com.", "www.example.com.", "A")
+				require.True(t, ok)
+				assert.Equal(t, 300, rrSet.TTL)
+				assert.Equal(t, []Record{{Content: "192.0.2.1"}, {Content: "192.0.2.2", Disabled: true}}, rrSet.Records)
+			},
+		},
+		{
+			name: "canonical content",
+			rrSets: []map[string]interface{}{
+				{"name": "example.com.", "type": "MX", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "10 Mail.example.com."}, {Content: "0 ."}}},
+				{"name": "www.example.com.", "type": "AAAA", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "2001:db8::1"}}},
+			},
+			check: func(t *testing.T, s *Server) {
+				rrSet, ok := s.RRSet("example.com.", "example.com.", "MX")
+				require.True(t, ok)
+				assert.Equal(t, []Record{{Content: "10 Mail.example.com."}, {Content: "0 ."}}, rrSet.Records)
+			},
+		},
+		{
+			name: "delete",
+			rrSets: []map[string]interface{}{
+				{"name": "example.com.", "type": "NS", "changetype": "DELETE"},
+			},
+			check: func(t *testing.T, s *Server) {
+				_, ok := s.RRSet("example.com.", "example.com.", "NS")
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "comments kept when only records change",
+			rrSets: []map[string]interface{}{
+				{"name": "mail.example.com.", "type": "A", "ttl": 60, "changetype": "REPLACE", "comments": []Comment{{Content: "mail server"}}},
+				{"name": "txt.example.com.", "type": "TXT", "ttl": 60, "changetype": "REPLACE", "records": []Record{{Content: `"hello"`}}},
+			},
+			check: func(t *testing.T, s *Server) {
+				rrSet, ok := s.RRSet("example.com.", "mail.example.com.", "A")
+				require.True(t, ok)
+				assert.Empty(t, rrSet.Records)
+				assert.Equal(t, "mail server", rrSet.Comments[0].Content)
+			},
+		},
+		{
+			name: "comments dated",
+			rrSets: []map[string]interface{}{
+				{"name": "mail.example.com.", "type": "A", "ttl": 60, "changetype": "REPLACE", "comments": []Comment{{Content: "mail server"}, {Content: "old", ModifiedAt: 1000}}},
+			},
+			check: func(t *testing.T, s *Server) {
+				rrSet, ok := s.RRSet("example.com.", "mail.example.com.", "A")
+				require.True(t, ok)
+				assert.NotZero(t, rrSet.Comments[0].ModifiedAt)
+				assert.Equal(t, int64(1000), rrSet.Comments[1].ModifiedAt)
+			},
+		},
+		{
+			name: "out of zone",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.org.", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "192.0.2.1"}}},
+			},
+			expectedError: "RRset www.example.org. IN A: Name is out of zone",
+		},
+		{
+			name: "not canonical",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "192.0.2.1"}}},
+			},
+			expectedError: "DNS Name 'www.example.com' is not canonical",
+		},
+		{
+			name: "duplicate rrset",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com.", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "192.0.2.1"}}},
+				{"name": "WWW.example.com.", "type": "A", "changetype": "DELETE"},
+			},
+			expectedError: "Duplicate RRset www.example.com. IN A with changetype: DELETE",
+		},
+		{
+			name: "invalid address",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com.", "type": "AAAA", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "192.0.2.1"}}},
+			},
+			expectedError: "Parsing record content",
+		},
+		{
+			name: "uncompressed address",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com.", "type": "AAAA", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "2001:DB8:0::1"}}},
+			},
+			expectedError: "Record www.example.com./AAAA '2001:DB8:0::1': Not in expected format (parsed as '2001:db8::1')",
+		},
+		{
+			name: "relative host name",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com.", "type": "CNAME", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "target.example.com"}}},
+			},
+			expectedError: "Record www.example.com./CNAME 'target.example.com': Not in expected format (parsed as 'target.example.com.')",
+		},
+		{
+			name: "relative MX exchange",
+			rrSets: []map[string]interface{}{
+				{"name": "example.com.", "type": "MX", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "010 mail.example.com"}}},
+			},
+			expectedError: "Record example.com./MX '010 mail.example.com': Not in expected format (parsed as '10 mail.example.com.')",
+		},
+		{
+			name: "relative SVCB target",
+			rrSets: []map[string]interface{}{
+				{"name": "example.com.", "type": "HTTPS", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "1 svc.example.com alpn=h2"}}},
+			},
+			expectedError: "Not in expected format (parsed as '1 svc.example.com. alpn=h2')",
+		},
+		{
+			name: "invalid SRV",
+			rrSets: []map[string]interface{}{
+				{"name": "_sip._tcp.example.com.", "type": "SRV", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "10 60 sip.example.com."}}},
+			},
+			expectedError: "Parsing record content",
+		},
+		{
+			name: "cname conflict",
+			rrSets: []map[string]interface{}{
+				{"name": "www.example.com.", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "192.0.2.1"}}},
+				{"name": "www.example.com.", "type": "CNAME", "ttl": 300, "changetype": "REPLACE", "records": []Record{{Content: "example.com."}}},
+			},
+			expectedError: "Conflicts with pre-existing RRset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(t)
+			createTestZone(t, s)
+
+			var apiErr errorResponse
+			status := call(t, s, false, http.MethodPatch, zonesPath+"/example.com.", map[string]interface{}{"rrsets": tt.rrSets}, &apiErr)
+			zone, _ := s.Zone("example.com.")
+			if tt.expectedError != "" {
+				assert.Equal(t, http.StatusUnprocessableEntity, status)
+				assert.Contains(t, apiErr.Error, tt.expectedError)
+				assert.Equal(t, int64(1), zone.Serial)
+				assert.Len(t, zone.RRSets, 2)
+				return
+			}
+			assert.Equal(t, http.StatusNoContent, status)
+			assert.Equal(t, int64(2), zone.Serial)
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestServer_FilterRRSets(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+
+	var zone Zone
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, zonesPath+"/example.com.?rrset_name=example.com.&rrset_type=SOA", nil, &zone))
+	require.Len(t, zone.RRSets, 1)
+	assert.Equal(t, "SOA", zone.RRSets[0].Type)
+	assert.Equal(t, "a.misconfigured.dns.server.invalid. hostmaster.example.com. 1 10800 3600 604800 3600", zone.RRSets[0].Records[0].Content)
+}
+
+func TestServer_Metadata(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+	metadataPath := zonesPath + "/example.com./metadata"
+
+	var metadata Metadata
+	require.Equal(t, http.StatusCreated, call(t, s, false, http.MethodPost, metadataPath, Metadata{Kind: "ALSO-NOTIFY", Metadata: []string{"192.0.2.1"}}, &metadata))
+	require.Equal(t, http.StatusCreated, call(t, s, false, http.MethodPost, metadataPath, Metadata{Kind: "ALSO-NOTIFY", Metadata: []string{"192.0.2.2"}}, &metadata))
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, metadata.Metadata)
+
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, metadataPath+"/X-Custom", Metadata{Metadata: []string{"value"}}, &metadata))
+	assert.Equal(t, "X-CUSTOM", metadata.Kind)
+
+	var all []Metadata
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, metadataPath, nil, &all))
+	assert.Len(t, all, 2)
+
+	var apiErr errorResponse
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, metadataPath+"/NSEC3PARAM", Metadata{Metadata: []string{"1 0 0 -"}}, &apiErr))
+	assert.Equal(t, "Unsupported metadata kind 'NSEC3PARAM'", apiErr.Error)
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, metadataPath, Metadata{Kind: "UNKNOWN"}, nil))
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodDelete, metadataPath+"/ALSO-NOTIFY", nil, nil))
+	assert.Empty(t, s.ZoneMetadata("example.com.", "ALSO-NOTIFY"))
+	assert.Equal(t, []string{"value"}, s.ZoneMetadata("example.com.", "x-custom"))
+}
+
+func TestServer_CryptoKeys(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+	keysPath := zonesPath + "/example.com./cryptokeys"
+
+	// Key from the ED25519 example of RFC 8080, section 6.1
+	var imported CryptoKey
+	privateKey := "Private-key-format: v1.2\nAlgorithm: 15 (ED25519)\nPrivateKey: ODIyNjAzODQ2MjgwODAxMjI2NDUxOTAyMDQxNDIyNjI=\n"
+	status := call(t, s, false, http.MethodPost, keysPath, map[string]interface{}{"keytype": "ksk", "active": true, "algorithm": "ED25519", "privatekey": privateKey}, &imported)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "257 3 15 l02Woi0iS8Aa25FQkUd9RMzZHJpBoRQwAQEX1SxZJA4=", imported.DNSKey)
+	assert.Contains(t, imported.DS, "3613 15 2 3aa5ab37efce57f737fc1627013fee07bdf241bd10f3b1964ab55c78e79a304b")
+	assert.True(t, imported.Published)
+
+	var generated CryptoKey
+	status = call(t, s, false, http.MethodPost, keysPath, map[string]interface{}{"keytype": "zsk", "algorithm": 13}, &generated)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "ECDSAP256SHA256", generated.Algorithm)
+	assert.Equal(t, 256, generated.Flags)
+	assert.Empty(t, generated.DS)
+	assert.NotEmpty(t, generated.PrivateKey)
+
+	var keys []CryptoKey
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, keysPath, nil, &keys))
+	require.Len(t, keys, 2)
+	assert.Empty(t, keys[0].PrivateKey)
+
+	zone, _ := s.Zone("example.com.")
+	assert.True(t, zone.DNSSEC)
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, keysPath+"/2", map[string]bool{"active": true}, nil))
+	assert.True(t, s.CryptoKeys("example.com.")[1].Active)
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodDelete, keysPath+"/1", nil, nil))
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodGet, keysPath+"/1", nil, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, keysPath, map[string]interface{}{"keytype": "ksk", "algorithm": "ecdsa256", "bits": 1024}, nil))
+}
+
+func TestServer_SecureZone(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonesPath+"/example.com.", map[string]bool{"dnssec": true}, nil))
+	keys := s.CryptoKeys("example.com.")
+	require.Len(t, keys, 1)
+	assert.Equal(t, "csk", keys[0].KeyType)
+	assert.Len(t, keys[0].DS, 2)
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonesPath+"/example.com.", map[string]bool{"dnssec": false}, nil))
+	assert.Empty(t, s.CryptoKeys("example.com."))
+}
+
+func TestServer_NSEC3Param(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+	zonePath := zonesPath + "/example.com."
+
+	nsec3 := map[string]interface{}{"nsec3param": "1 0 0 -", "nsec3narrow": true}
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, zonePath, nsec3, nil))
+
+	nsec3["dnssec"] = true
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonePath, nsec3, nil))
+	zone, ok := s.Zone("example.com.")
+	require.True(t, ok)
+	assert.Equal(t, "1 0 0 -", zone.NSEC3Param)
+	assert.True(t, zone.NSEC3Narrow)
+
+	// nsec3narrow alone is ignored
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonePath, map[string]bool{"nsec3narrow": false}, nil))
+	zone, _ = s.Zone("example.com.")
+	assert.True(t, zone.NSEC3Narrow)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, zonePath, map[string]string{"nsec3param": "2 0 0 -"}, nil))
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonePath, map[string]string{"nsec3param": ""}, nil))
+	zone, _ = s.Zone("example.com.")
+	assert.Empty(t, zone.NSEC3Param)
+	assert.False(t, zone.NSEC3Narrow)
+	assert.True(t, zone.DNSSEC)
+}
+
+func TestServer_TSIGKeys(t *testing.T) {
+	s := New(t)
+	keysPath := "/api/v1/servers/localhost/tsigkeys"
+
+	var generated TSIGKey
+	require.Equal(t, http.StatusCreated, call(t, s, false, http.MethodPost, keysPath, map[string]string{"name": "axfr", "algorithm": "hmac-sha256"}, &generated))
+	assert.Equal(t, "axfr.", generated.ID)
+	assert.Equal(t, "axfr", generated.Name)
+	secret, err := base64.StdEncoding.DecodeString(generated.Key)
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	var imported TSIGKey
+	body := map[string]string{"name": "update", "algorithm": "hmac-sha512.", "key": "c2VjcmV0"}
+	require.Equal(t, http.StatusCreated, call(t, s, false, http.MethodPost, keysPath, body, &imported))
+	assert.Equal(t, "hmac-sha512", imported.Algorithm)
+	assert.Equal(t, "c2VjcmV0", imported.Key)
+	assert.Equal(t, http.StatusConflict, call(t, s, false, http.MethodPost, keysPath, body, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, keysPath, map[string]string{"name": "other", "algorithm": "hmac-sha3"}, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, keysPath, map[string]string{"name": "other", "algorithm": "hmac-md5", "key": "not base64"}, nil))
+
+	var keys []TSIGKey
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, keysPath, nil, &keys))
+	require.Len(t, keys, 2)
+	assert.Empty(t, keys[0].Key)
+
+	var updated TSIGKey
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, keysPath+"/axfr.", map[string]string{"algorithm": "hmac-sha1"}, &updated))
+	assert.Equal(t, "hmac-sha1", updated.Algorithm)
+	assert.Equal(t, generated.Key, updated.Key)
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodDelete, keysPath+"/axfr.", nil, nil))
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodGet, keysPath+"/axfr.", nil, nil))
+	assert.Len(t, s.TSIGKeys(), 1)
+}
+
+func TestServer_ZoneTSIGKeys(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+	zonePath := zonesPath + "/example.com."
+	keysPath := "/api/v1/servers/localhost/tsigkeys"
+	require.Equal(t, http.StatusCreated, call(t, s, false, http.MethodPost, keysPath, map[string]string{"name": "axfr", "algorithm": "hmac-sha256"}, nil))
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonePath, map[string][]string{"master_tsig_key_ids": {"axfr"}}, nil))
+	zone, _ := s.Zone("example.com.")
+	assert.Equal(t, []string{"axfr."}, zone.MasterTSIGKeyIDs)
+	assert.Empty(t, zone.SlaveTSIGKeyIDs)
+
+	// Unknown keys fail the whole update
+	update := map[string][]string{"master_tsig_key_ids": {}, "slave_tsig_key_ids": {"missing."}}
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, zonePath, update, nil))
+	zone, _ = s.Zone("example.com.")
+	assert.Equal(t, []string{"axfr."}, zone.MasterTSIGKeyIDs)
+
+	status := call(t, s, false, http.MethodPost, zonesPath, Zone{Name: "example.org.", Kind: "Slave", Masters: []string{"192.0.2.1"}, SlaveTSIGKeyIDs: []string{"missing."}}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+}
+
+func TestServer_Autoprimaries(t *testing.T) {
+	s := New(t)
+	autoprimariesPath := "/api/v1/servers/localhost/autoprimaries"
+
+	body := Autoprimary{IP: "2001:db8:0::1", Nameserver: "ns1.example.com.", Account: "dns"}
+	assert.Equal(t, http.StatusCreated, call(t, s, false, http.MethodPost, autoprimariesPath, body, nil))
+	assert.Equal(t, http.StatusConflict, call(t, s, false, http.MethodPost, autoprimariesPath, body, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, autoprimariesPath, Autoprimary{IP: "primary", Nameserver: "ns1.example.com."}, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPost, autoprimariesPath, Autoprimary{IP: "192.0.2.1"}, nil))
+
+	var autoprimaries []Autoprimary
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, autoprimariesPath, nil, &autoprimaries))
+	assert.Equal(t, []Autoprimary{{IP: "2001:db8::1", Nameserver: "ns1.example.com.", Account: "dns"}}, autoprimaries)
+
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodDelete, autoprimariesPath+"/2001:db8::1/ns2.example.com.", nil, nil))
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodDelete, autoprimariesPath+"/2001:db8::1/ns1.example.com.", nil, nil))
+	assert.Empty(t, s.Autoprimaries())
+}
+
+func TestServer_RectifyZone(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+	rectifyPath := zonesPath + "/example.com./rectify"
+
+	var result operationResult
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, rectifyPath, nil, &result))
+	assert.Equal(t, "Rectified", result.Result)
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, false, http.MethodPut, zonesPath+"/example.com.", map[string]bool{"presigned": true}, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, rectifyPath, nil, nil))
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodPut, zonesPath+"/missing.example./rectify", nil, nil))
+}
+
+func TestServer_NotifyZone(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+
+	var result operationResult
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, zonesPath+"/example.com./notify", nil, &result))
+	assert.Equal(t, "Notification queued", result.Result)
+	zone, ok := s.Zone("example.com.")
+	require.True(t, ok)
+	assert.Equal(t, zone.Serial, zone.NotifiedSerial)
+
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodPut, zonesPath+"/missing.example./notify", nil, nil))
+}
+
+func TestServer_AXFRRetrieveZone(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+	_, err := s.CreateZone(Zone{Name: "secondary.example.", Kind: "Slave", Masters: []string{"192.0.2.1"}})
+	require.NoError(t, err)
+	retrievePath := zonesPath + "/secondary.example./axfr-retrieve"
+
+	var result operationResult
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, retrievePath, nil, &result))
+	assert.Equal(t, "Added retrieval request for 'secondary.example.' from primary 192.0.2.1", result.Result)
+	zone, _ := s.Zone("secondary.example.")
+	assert.Equal(t, int64(1), zone.Serial)
+
+	// Held transfers complete once released
+	s.HoldTransfers(true)
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, retrievePath, nil, nil))
+	zone, _ = s.Zone("secondary.example.")
+	assert.Equal(t, int64(1), zone.Serial)
+	s.HoldTransfers(false)
+	zone, _ = s.Zone("secondary.example.")
+	assert.Equal(t, int64(2), zone.Serial)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, zonesPath+"/example.com./axfr-retrieve", nil, nil))
+}
+
+func TestServer_FlushCache(t *testing.T) {
+	s := New(t)
+	flushPath := "/api/v1/servers/localhost/cache/flush?domain="
+	s.CacheAnswer(false, "example.com.", "SOA")
+	s.CacheAnswer(false, "www.example.com.", "A")
+	s.CacheAnswer(false, "example.org.", "A")
+	s.CacheAnswer(true, "www.example.com.", "A")
+	s.CacheAnswer(true, "www.example.com.", "AAAA")
+	s.CacheAnswer(true, "mail.example.com.", "A")
+
+	// The authoritative server always flushes the names below the domain
+	var result cacheFlushResult
+	require.Equal(t, http.StatusOK, call(t, s, false, http.MethodPut, flushPath+"example.com.", nil, &result))
+	assert.Equal(t, cacheFlushResult{Count: 2, Result: "Flushed cache."}, result)
+	assert.Equal(t, 1, s.CachedAnswers(false))
+	assert.Equal(t, 3, s.CachedAnswers(true))
+
+	require.Equal(t, http.StatusOK, call(t, s, true, http.MethodPut, flushPath+"example.com.", nil, &result))
+	assert.Equal(t, 0, result.Count)
+	require.Equal(t, http.StatusOK, call(t, s, true, http.MethodPut, flushPath+"www.example.com.&type=aaaa", nil, &result))
+	assert.Equal(t, 1, result.Count)
+	require.Equal(t, http.StatusOK, call(t, s, true, http.MethodPut, flushPath+"example.com.&subtree=true", nil, &result))
+	assert.Equal(t, 2, result.Count)
+	assert.Equal(t, 0, s.CachedAnswers(true))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, false, http.MethodPut, flushPath+"example.com", nil, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, true, http.MethodPut, flushPath, nil, nil))
+}
+
+func TestServer_RecursorZones(t *testing.T) {
+	s := New(t)
+
+	var created RecursorZone
+	status := call(t, s, true, http.MethodPost, zonesPath, RecursorZone{Name: "example.com.", Kind: "Forwarded", Servers: []string{"192.0.2.1", "[2001:db8::1]:5300"}}, &created)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, []string{"192.0.2.1:53", "[2001:db8::1]:5300"}, created.Servers)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, true, http.MethodPost, zonesPath, RecursorZone{Name: "example.com.", Kind: "Forwarded", Servers: []string{"192.0.2.1"}}, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, call(t, s, true, http.MethodPost, zonesPath, RecursorZone{Name: "example.org.", Kind: "Forwarded"}, nil))
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, true, http.MethodPut, zonesPath+"/example.com.", RecursorZone{Kind: "Forwarded", Servers: []string{"192.0.2.2"}, RecursionDesired: true}, nil))
+	zone, ok := s.RecursorZone("example.com")
+	require.True(t, ok)
+	assert.Equal(t, []string{"192.0.2.2:53"}, zone.Servers)
+	assert.True(t, zone.RecursionDesired)
+
+	// Recursor and authoritative zones are independent
+	assert.Equal(t, http.StatusNotFound, call(t, s, false, http.MethodGet, zonesPath+"/example.com.", nil, nil))
+
+	assert.Equal(t, http.StatusNoContent, call(t, s, true, http.MethodDelete, zonesPath+"/example.com.", nil, nil))
+	_, ok = s.RecursorZone("example.com")
+	assert.False(t, ok)
+}
+
+func TestServer_Faults(t *testing.T) {
+	s := New(t)
+	createTestZone(t, s)
+
+	s.Inject(Fault{Method: http.MethodGet, Path: "/zones/", Status: http.StatusServiceUnavailable, Times: 2})
+	assert.Equal(t, http.StatusServiceUnavailable, call(t, s, false, http.MethodGet, zonesPath+"/example.com.", nil, nil))
+	assert.Equal(t, http.StatusOK, call(t, s, true, http.MethodGet, zonesPath, nil, nil), "recursor not affected")
+	assert.Equal(t, http.StatusServiceUnavailable, call(t, s, false, http.MethodGet, zonesPath+"/example.com.", nil, nil))
+	assert.Equal(t, http.StatusOK, call(t, s, false, http.MethodGet, zonesPath+"/example.com.", nil, nil))
+
+	s.Inject(Fault{Recursor: true, Delay: 50 * time.Millisecond})
+	start := time.Now()
+	assert.Equal(t, http.StatusOK, call(t, s, true, http.MethodGet, zonesPath, nil, nil))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	s.ClearFaults()
+
+	s.RejectRecordEdits("DELETE")
+	patch := func(changeType string) (int, errorResponse) {
+		var apiErr errorResponse
+		status := call(t, s, false, http.MethodPatch, zonesPath+"/example.com.", map[string]interface{}{
+			"rrsets": []map[string]interface{}{{"name": "www.example.com.", "type": "A", "ttl": 60, "changetype": changeType, "records": []Record{{Content: "192.0.2.1"}}}},
+		}, &apiErr)
+		return status, apiErr
+	}
+	status, _ := patch("REPLACE")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, apiErr := patch("DELETE")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, MessageNoRecordEditing, apiErr.Error)
+
+	requests := s.Requests()
+	require.NotEmpty(t, requests)
+	last := requests[len(requests)-1]
+	assert.Equal(t, http.MethodPatch, last.Method)
+	assert.Contains(t, string(last.Body), "DELETE")
+}
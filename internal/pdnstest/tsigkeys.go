@@ -0,0 +1,203 @@
+package pdnstest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// TSIGKey is a TSIG key of the authoritative server, as exchanged with the
+// API.
+type TSIGKey struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Algorithm string `json:"algorithm"`
+	Key       string `json:"key,omitempty"`
+}
+
+// tsigAlgorithms maps the TSIG algorithms PowerDNS accepts to the size in
+// bytes of the keys it generates for them.
+var tsigAlgorithms = map[string]int{
+	"hmac-md5":    16,
+	"hmac-sha1":   20,
+	"hmac-sha224": 28,
+	"hmac-sha256": 32,
+	"hmac-sha384": 48,
+	"hmac-sha512": 64,
+}
+
+// TSIGKeys returns the TSIG keys of the server, with their secrets, ordered
+// by name.
+func (s *Server) TSIGKeys() []TSIGKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]TSIGKey, 0, len(s.tsigKeys))
+	for _, key := range s.tsigKeys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// tsigKeyID returns the ID of the key of the given name: the name with a
+// trailing dot, as PowerDNS returns it.
+func tsigKeyID(name string) string {
+	return canonicalName(strings.ReplaceAll(name, "=2F", "/"))
+}
+
+// setTSIGKey validates algorithm and secret, the latter being generated when
+// empty, and stores them in key.
+func setTSIGKey(key *TSIGKey, algorithm string, secret string) *apiError {
+	algorithm = strings.TrimSuffix(strings.ToLower(algorithm), ".")
+	if algorithm == "hmac-md5.sig-alg.reg.int" {
+		algorithm = "hmac-md5"
+	}
+	size, ok := tsigAlgorithms[algorithm]
+	if !ok {
+		return errorf(http.StatusUnprocessableEntity, "Unknown TSIG algorithm: %s", algorithm)
+	}
+
+	if secret == "" {
+		raw := make([]byte, size)
+		if _, err := rand.Read(raw); err != nil {
+			return errorf(http.StatusInternalServerError, "Unable to generate a TSIG secret: %s", err)
+		}
+		secret = base64.StdEncoding.EncodeToString(raw)
+	} else if _, err := base64.StdEncoding.DecodeString(secret); err != nil {
+		return errorf(http.StatusUnprocessableEntity, "Could not decode secret %s for TSIG key %s", secret, key.Name)
+	}
+
+	key.Algorithm = algorithm
+	key.Key = secret
+	return nil
+}
+
+// resolveTSIGKeyIDs returns the IDs of the TSIG keys a zone refers to by ID
+// or name, rejecting the keys the server doesn't have.
+func (s *Server) resolveTSIGKeyIDs(ids []string) ([]string, *apiError) {
+	resolved := make([]string, 0, len(ids))
+	for _, id := range ids {
+		key, ok := s.tsigKeys[tsigKeyID(id)]
+		if !ok {
+			return nil, errorf(http.StatusUnprocessableEntity, "A TSIG key with the name '%s' does not exist", id)
+		}
+		resolved = append(resolved, key.ID)
+	}
+	return resolved, nil
+}
+
+// tsigKeyHandler handles a request on an existing TSIG key, with s.mu held.
+type tsigKeyHandler func(w http.ResponseWriter, r *http.Request, key *TSIGKey)
+
+// withTSIGKey looks up the TSIG key of the request path before calling
+// handle.
+func (s *Server) withTSIGKey(handle tsigKeyHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.checkServer(w, r) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		key, ok := s.tsigKeys[tsigKeyID(r.PathValue("id"))]
+		if !ok {
+			writeError(w, http.StatusNotFound, "TSIG key with name '"+r.PathValue("id")+"' not found")
+			return
+		}
+		handle(w, r, key)
+	}
+}
+
+func (s *Server) listTSIGKeys(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	keys := s.TSIGKeys()
+	for i := range keys {
+		keys[i].Key = ""
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (s *Server) postTSIGKey(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	var body TSIGKey
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "TSIG key name is missing")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := tsigKeyID(body.Name)
+	if _, exists := s.tsigKeys[id]; exists {
+		writeError(w, http.StatusConflict, "A TSIG key with the name '"+body.Name+"' already exists")
+		return
+	}
+	key := &TSIGKey{ID: id, Name: strings.TrimSuffix(strings.ToLower(body.Name), "."), Type: "TSIGKey"}
+	if err := setTSIGKey(key, body.Algorithm, body.Key); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	s.tsigKeys[id] = key
+	writeJSON(w, http.StatusCreated, *key)
+}
+
+func (s *Server) getTSIGKey(w http.ResponseWriter, r *http.Request, key *TSIGKey) {
+	writeJSON(w, http.StatusOK, *key)
+}
+
+func (s *Server) putTSIGKey(w http.ResponseWriter, r *http.Request, key *TSIGKey) {
+	var body TSIGKey
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	updated := *key
+	algorithm := key.Algorithm
+	if body.Algorithm != "" {
+		algorithm = body.Algorithm
+	}
+	secret := key.Key
+	if body.Key != "" {
+		secret = body.Key
+	}
+	if err := setTSIGKey(&updated, algorithm, secret); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	// Renaming moves the key to its new ID
+	if body.Name != "" && tsigKeyID(body.Name) != key.ID {
+		id := tsigKeyID(body.Name)
+		if _, exists := s.tsigKeys[id]; exists {
+			writeError(w, http.StatusConflict, "A TSIG key with the name '"+body.Name+"' already exists")
+			return
+		}
+		delete(s.tsigKeys, key.ID)
+		updated.ID = id
+		updated.Name = strings.TrimSuffix(strings.ToLower(body.Name), ".")
+	}
+	s.tsigKeys[updated.ID] = &updated
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) deleteTSIGKey(w http.ResponseWriter, r *http.Request, key *TSIGKey) {
+	delete(s.tsigKeys, key.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,739 @@
+package pdnstest
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change types of rrset PATCHes.
+const (
+	changeTypeReplace = "REPLACE"
+	changeTypeDelete  = "DELETE"
+)
+
+// Zone is a zone of the authoritative server, as exchanged with the API.
+type Zone struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	URL              string   `json:"url"`
+	Kind             string   `json:"kind"`
+	Serial           int64    `json:"serial"`
+	NotifiedSerial   int64    `json:"notified_serial"`
+	EditedSerial     int64    `json:"edited_serial"`
+	Masters          []string `json:"masters"`
+	DNSSEC           bool     `json:"dnssec"`
+	NSEC3Param       string   `json:"nsec3param"`
+	NSEC3Narrow      bool     `json:"nsec3narrow"`
+	Presigned        bool     `json:"presigned"`
+	SOAEdit          string   `json:"soa_edit"`
+	SOAEditAPI       string   `json:"soa_edit_api"`
+	APIRectify       bool     `json:"api_rectify"`
+	Account          string   `json:"account"`
+	Catalog          string   `json:"catalog"`
+	MasterTSIGKeyIDs []string `json:"master_tsig_key_ids"`
+	SlaveTSIGKeyIDs  []string `json:"slave_tsig_key_ids"`
+	Nameservers      []string `json:"nameservers,omitempty"` // Only used on creation
+	RRSets           []RRSet  `json:"rrsets,omitempty"`
+}
+
+// RRSet is a set of records sharing a name and a type.
+type RRSet struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	TTL        int       `json:"ttl"`
+	ChangeType string    `json:"changetype,omitempty"`
+	Records    []Record  `json:"records"`
+	Comments   []Comment `json:"comments"`
+}
+
+// Record is a single record of an RRSet.
+type Record struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Comment is a comment attached to an RRSet.
+type Comment struct {
+	Content    string `json:"content"`
+	Account    string `json:"account"`
+	ModifiedAt int64  `json:"modified_at"`
+}
+
+// rrSetChange is an rrset of a PATCH. Records and comments are only changed
+// when present.
+type rrSetChange struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	TTL        int        `json:"ttl"`
+	ChangeType string     `json:"changetype"`
+	Records    *[]Record  `json:"records"`
+	Comments   *[]Comment `json:"comments"`
+}
+
+// zoneUpdate holds the zone attributes a PUT may change.
+type zoneUpdate struct {
+	Kind             *string   `json:"kind"`
+	Masters          *[]string `json:"masters"`
+	Account          *string   `json:"account"`
+	SOAEdit          *string   `json:"soa_edit"`
+	SOAEditAPI       *string   `json:"soa_edit_api"`
+	APIRectify       *bool     `json:"api_rectify"`
+	DNSSEC           *bool     `json:"dnssec"`
+	NSEC3Param       *string   `json:"nsec3param"`
+	NSEC3Narrow      *bool     `json:"nsec3narrow"`
+	Presigned        *bool     `json:"presigned"`
+	Catalog          *string   `json:"catalog"`
+	MasterTSIGKeyIDs *[]string `json:"master_tsig_key_ids"`
+	SlaveTSIGKeyIDs  *[]string `json:"slave_tsig_key_ids"`
+}
+
+// zoneKinds maps the lower case zone kinds to their canonical spelling.
+var zoneKinds = map[string]string{
+	"native":   "Native",
+	"master":   "Master",
+	"slave":    "Slave",
+	"producer": "Producer",
+	"consumer": "Consumer",
+}
+
+// zone is the state of a zone of the authoritative server.
+type zone struct {
+	info       Zone
+	rrSets     map[rrSetKey]*RRSet
+	metadata   map[string][]string
+	cryptoKeys []*cryptoKey
+
+	transferPending bool // An axfr-retrieve is waiting for HoldTransfers(false)
+}
+
+type rrSetKey struct {
+	name string
+	tpe  string
+}
+
+// secondary reports whether the zone is transferred from a primary, in which
+// case it has no records of its own until the first transfer.
+func (z *zone) secondary() bool {
+	return z.info.Kind == "Slave" || z.info.Kind == "Consumer"
+}
+
+// view returns the zone as sent by the API, with its rrsets when withRRSets.
+func (z *zone) view(withRRSets bool, filter func(RRSet) bool) Zone {
+	info := z.info
+	info.Masters = append([]string{}, z.info.Masters...)
+	info.MasterTSIGKeyIDs = append([]string{}, z.info.MasterTSIGKeyIDs...)
+	info.SlaveTSIGKeyIDs = append([]string{}, z.info.SlaveTSIGKeyIDs...)
+	info.DNSSEC = len(z.cryptoKeys) > 0
+	info.RRSets = nil
+	if !withRRSets {
+		return info
+	}
+
+	info.RRSets = []RRSet{}
+	for _, rrSet := range z.rrSets {
+		if filter == nil || filter(*rrSet) {
+			info.RRSets = append(info.RRSets, copyRRSet(*rrSet))
+		}
+	}
+	sort.Slice(info.RRSets, func(i, j int) bool {
+		if info.RRSets[i].Name != info.RRSets[j].Name {
+			return info.RRSets[i].Name < info.RRSets[j].Name
+		}
+		return info.RRSets[i].Type < info.RRSets[j].Type
+	})
+	return info
+}
+
+func copyRRSet(rrSet RRSet) RRSet {
+	rrSet.Records = append([]Record{}, rrSet.Records...)
+	rrSet.Comments = append([]Comment{}, rrSet.Comments...)
+	return rrSet
+}
+
+// bumpSerial increases the serial of the zone after a change, keeping the SOA
+// record in sync.
+func (z *zone) bumpSerial() {
+	z.info.Serial++
+	z.info.EditedSerial = z.info.Serial
+
+	soa := z.rrSets[rrSetKey{name: z.info.Name, tpe: "SOA"}]
+	if soa == nil || len(soa.Records) == 0 {
+		return
+	}
+	fields := strings.Fields(soa.Records[0].Content)
+	if len(fields) == 7 {
+		fields[2] = strconv.FormatInt(z.info.Serial, 10)
+		soa.Records[0].Content = strings.Join(fields, " ")
+	}
+}
+
+// CreateZone creates a zone the way a POST to the zones endpoint does.
+func (s *Server) CreateZone(info Zone) (Zone, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, err := s.createZone(info)
+	if err != nil {
+		return Zone{}, err
+	}
+	return z.view(true, nil), nil
+}
+
+// Zone returns the zone of the given name with its rrsets.
+func (s *Server) Zone(name string) (Zone, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.zones[canonicalName(name)]
+	if !ok {
+		return Zone{}, false
+	}
+	return z.view(true, nil), true
+}
+
+// RRSet returns the rrset of the given name and type in zone.
+func (s *Server) RRSet(zoneName string, name string, tpe string) (RRSet, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.zones[canonicalName(zoneName)]
+	if !ok {
+		return RRSet{}, false
+	}
+	rrSet, ok := z.rrSets[rrSetKey{name: canonicalName(name), tpe: strings.ToUpper(tpe)}]
+	if !ok {
+		return RRSet{}, false
+	}
+	return copyRRSet(*rrSet), true
+}
+
+// createZone validates and stores a new zone. Must be called with s.mu held.
+func (s *Server) createZone(info Zone) (*zone, *apiError) {
+	if err := checkCanonical(info.Name); err != nil {
+		return nil, err
+	}
+	name := canonicalName(info.Name)
+	if _, exists := s.zones[name]; exists {
+		return nil, errorf(http.StatusConflict, "Domain '%s' already exists", name)
+	}
+
+	kind, ok := zoneKinds[strings.ToLower(info.Kind)]
+	if !ok {
+		return nil, errorf(http.StatusUnprocessableEntity, "Invalid zone kind '%s'", info.Kind)
+	}
+	for _, nameserver := range info.Nameservers {
+		if err := checkCanonical(nameserver); err != nil {
+			return nil, err
+		}
+	}
+	masterTSIGKeyIDs, err := s.resolveTSIGKeyIDs(info.MasterTSIGKeyIDs)
+	if err != nil {
+		return nil, err
+	}
+	slaveTSIGKeyIDs, err := s.resolveTSIGKeyIDs(info.SlaveTSIGKeyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	z := &zone{
+		info:     info,
+		rrSets:   make(map[rrSetKey]*RRSet),
+		metadata: make(map[string][]string),
+	}
+	z.info.ID = strings.ReplaceAll(name, "/", "=2F")
+	z.info.Name = name
+	z.info.Type = "Zone"
+	z.info.URL = fmt.Sprintf("/api/v1/servers/%s/zones/%s", s.serverID, z.info.ID)
+	z.info.Kind = kind
+	z.info.Nameservers = nil
+	z.info.RRSets = nil
+	z.info.MasterTSIGKeyIDs = masterTSIGKeyIDs
+	z.info.SlaveTSIGKeyIDs = slaveTSIGKeyIDs
+	z.info.Serial = 0
+	if z.info.SOAEditAPI == "" {
+		z.info.SOAEditAPI = "DEFAULT"
+	}
+
+	if !z.secondary() {
+		initial := make([]rrSetChange, 0, len(info.RRSets)+2)
+		for _, rrSet := range info.RRSets {
+			records, comments := rrSet.Records, rrSet.Comments
+			initial = append(initial, rrSetChange{Name: rrSet.Name, Type: rrSet.Type, TTL: rrSet.TTL, ChangeType: changeTypeReplace, Records: &records, Comments: &comments})
+		}
+		if !hasRRSet(info.RRSets, name, "SOA") {
+			soa := []Record{{Content: fmt.Sprintf("a.misconfigured.dns.server.invalid. hostmaster.%s 0 10800 3600 604800 3600", name)}}
+			initial = append(initial, rrSetChange{Name: name, Type: "SOA", TTL: 3600, ChangeType: changeTypeReplace, Records: &soa})
+		}
+		if len(info.Nameservers) > 0 {
+			ns := make([]Record, len(info.Nameservers))
+			for i, nameserver := range info.Nameservers {
+				ns[i] = Record{Content: nameserver}
+			}
+			initial = append(initial, rrSetChange{Name: name, Type: "NS", TTL: 3600, ChangeType: changeTypeReplace, Records: &ns})
+		}
+		if err := z.patch(initial, nil); err != nil {
+			return nil, err
+		}
+		z.bumpSerial()
+	}
+
+	if info.DNSSEC {
+		if err := s.secureZone(z); err != nil {
+			return nil, err
+		}
+	}
+	z.info.NSEC3Param = ""
+	z.info.NSEC3Narrow = false
+	if err := z.setNSEC3Param(info.NSEC3Param, info.NSEC3Narrow); err != nil {
+		return nil, err
+	}
+
+	s.zones[name] = z
+	return z, nil
+}
+
+func hasRRSet(rrSets []RRSet, name string, tpe string) bool {
+	for _, rrSet := range rrSets {
+		if canonicalName(rrSet.Name) == name && strings.EqualFold(rrSet.Type, tpe) {
+			return true
+		}
+	}
+	return false
+}
+
+// patch applies rrset changes atomically, rejecting change types listed in
+// editsOff like LMDB backends do.
+func (z *zone) patch(changes []rrSetChange, editsOff map[string]bool) *apiError {
+	updated := make(map[rrSetKey]*RRSet, len(z.rrSets))
+	for key, rrSet := range z.rrSets {
+		updated[key] = rrSet
+	}
+
+	seen := make(map[rrSetKey]bool, len(changes))
+	for _, change := range changes {
+		if err := checkCanonical(change.Name); err != nil {
+			return err
+		}
+		key := rrSetKey{name: canonicalName(change.Name), tpe: strings.ToUpper(change.Type)}
+		label := fmt.Sprintf("RRset %s IN %s", key.name, key.tpe)
+		if key.tpe == "" {
+			return errorf(http.StatusUnprocessableEntity, "RRset %s: Type is missing", key.name)
+		}
+		if !inZone(key.name, z.info.Name) {
+			return errorf(http.StatusUnprocessableEntity, "%s: Name is out of zone", label)
+		}
+		changeType := strings.ToUpper(change.ChangeType)
+		if seen[key] {
+			return errorf(http.StatusUnprocessableEntity, "Duplicate %s with changetype: %s", label, changeType)
+		}
+		seen[key] = true
+		if editsOff[changeType] {
+			return errorf(http.StatusUnprocessableEntity, "%s", MessageNoRecordEditing)
+		}
+
+		switch changeType {
+		case changeTypeDelete:
+			delete(updated, key)
+		case changeTypeReplace:
+			if change.Records == nil && change.Comments == nil {
+				return errorf(http.StatusUnprocessableEntity, "%s: Neither records nor comments given", label)
+			}
+			rrSet := &RRSet{Name: key.name, Type: key.tpe, TTL: change.TTL, Records: []Record{}, Comments: []Comment{}}
+			if existing := updated[key]; existing != nil {
+				rrSet.Records, rrSet.Comments = existing.Records, existing.Comments
+			}
+			if change.Records != nil {
+				if err := checkRecords(key, *change.Records); err != nil {
+					return err
+				}
+				rrSet.Records = append([]Record{}, *change.Records...)
+			}
+			if change.Comments != nil {
+				rrSet.Comments = append([]Comment{}, *change.Comments...)
+				// PowerDNS dates the comments sent without a date
+				for i := range rrSet.Comments {
+					if rrSet.Comments[i].ModifiedAt == 0 {
+						rrSet.Comments[i].ModifiedAt = time.Now().Unix()
+					}
+				}
+			}
+			if len(rrSet.Records) == 0 && len(rrSet.Comments) == 0 {
+				delete(updated, key)
+			} else {
+				updated[key] = rrSet
+			}
+		default:
+			return errorf(http.StatusUnprocessableEntity, "Changetype '%s' not understood", change.ChangeType)
+		}
+	}
+
+	if err := checkCNAMEConflicts(updated, seen); err != nil {
+		return err
+	}
+
+	z.rrSets = updated
+	return nil
+}
+
+// inZone reports whether name is the apex of zoneName or below it.
+func inZone(name string, zoneName string) bool {
+	return name == zoneName || strings.HasSuffix(name, "."+zoneName)
+}
+
+// checkRecords rejects duplicate records, and records whose content doesn't
+// parse or isn't in the canonical form PowerDNS stores it in.
+func checkRecords(key rrSetKey, records []Record) *apiError {
+	contents := make(map[string]bool, len(records))
+	for _, record := range records {
+		if contents[record.Content] {
+			return errorf(http.StatusUnprocessableEntity, "Duplicate record in RRset %s IN %s with content \"%s\"", key.name, key.tpe, record.Content)
+		}
+		contents[record.Content] = true
+
+		canonical, ok := canonicalContent(key.tpe, record.Content)
+		if !ok {
+			return errorf(http.StatusUnprocessableEntity, "Record %s/%s '%s': Parsing record content (try 'pdnsutil check-zone'): unable to parse record content", key.name, key.tpe, record.Content)
+		}
+		if canonical != record.Content {
+			return errorf(http.StatusUnprocessableEntity, "Record %s/%s '%s': Not in expected format (parsed as '%s')", key.name, key.tpe, record.Content, canonical)
+		}
+	}
+	return nil
+}
+
+// contentHostNames gives, for the record types whose content holds a host
+// name, the number of fields before it.
+var contentHostNames = map[string]int{
+	"ALIAS": 0,
+	"CNAME": 0,
+	"DNAME": 0,
+	"NS":    0,
+	"PTR":   0,
+	"MX":    1,
+	"SRV":   3,
+	"SVCB":  1,
+	"HTTPS": 1,
+}
+
+// canonicalContent returns content the way PowerDNS renders it once parsed:
+// addresses in their canonical form, numbers without leading zeros and host
+// names absolute. The content of the other types is only checked not to be
+// empty.
+func canonicalContent(tpe string, content string) (string, bool) {
+	switch tpe {
+	case "A", "AAAA":
+		addr, err := netip.ParseAddr(content)
+		if err != nil || addr.Is4() != (tpe == "A") || addr.Zone() != "" {
+			return "", false
+		}
+		return addr.String(), true
+	}
+
+	position, ok := contentHostNames[tpe]
+	if !ok {
+		return content, content != ""
+	}
+	fields := strings.Fields(content)
+	if len(fields) <= position || (position == 0 && len(fields) != 1) {
+		return "", false
+	}
+	for i := 0; i < position; i++ {
+		number, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return "", false
+		}
+		fields[i] = strconv.FormatUint(number, 10)
+	}
+	if !strings.HasSuffix(fields[position], ".") {
+		fields[position] += "."
+	}
+	return strings.Join(fields, " "), true
+}
+
+// checkCNAMEConflicts rejects names holding a CNAME next to other data, for
+// the names touched by a change.
+func checkCNAMEConflicts(rrSets map[rrSetKey]*RRSet, changed map[rrSetKey]bool) *apiError {
+	for key := range changed {
+		if rrSets[key] == nil {
+			continue
+		}
+		_, hasCNAME := rrSets[rrSetKey{name: key.name, tpe: "CNAME"}]
+		if !hasCNAME {
+			continue
+		}
+		for other := range rrSets {
+			if other.name == key.name && other.tpe != "CNAME" && other.tpe != "RRSIG" && other.tpe != "NSEC" {
+				return errorf(http.StatusUnprocessableEntity, "RRset %s IN %s: Conflicts with pre-existing RRset", key.name, key.tpe)
+			}
+		}
+	}
+	return nil
+}
+
+// updateZone applies a PUT to the zone attributes.
+func (s *Server) updateZone(z *zone, update zoneUpdate) *apiError {
+	// Unknown TSIG keys fail the update before anything changes
+	var masterTSIGKeyIDs, slaveTSIGKeyIDs []string
+	if update.MasterTSIGKeyIDs != nil {
+		var err *apiError
+		if masterTSIGKeyIDs, err = s.resolveTSIGKeyIDs(*update.MasterTSIGKeyIDs); err != nil {
+			return err
+		}
+	}
+	if update.SlaveTSIGKeyIDs != nil {
+		var err *apiError
+		if slaveTSIGKeyIDs, err = s.resolveTSIGKeyIDs(*update.SlaveTSIGKeyIDs); err != nil {
+			return err
+		}
+	}
+
+	if update.Kind != nil {
+		kind, ok := zoneKinds[strings.ToLower(*update.Kind)]
+		if !ok {
+			return errorf(http.StatusUnprocessableEntity, "Invalid zone kind '%s'", *update.Kind)
+		}
+		z.info.Kind = kind
+	}
+	if update.Masters != nil {
+		z.info.Masters = append([]string{}, *update.Masters...)
+	}
+	if update.Account != nil {
+		z.info.Account = *update.Account
+	}
+	if update.SOAEdit != nil {
+		z.info.SOAEdit = *update.SOAEdit
+	}
+	if update.SOAEditAPI != nil {
+		z.info.SOAEditAPI = *update.SOAEditAPI
+	}
+	if update.APIRectify != nil {
+		z.info.APIRectify = *update.APIRectify
+	}
+	if update.Presigned != nil {
+		z.info.Presigned = *update.Presigned
+	}
+	if update.Catalog != nil {
+		z.info.Catalog = *update.Catalog
+	}
+	if update.MasterTSIGKeyIDs != nil {
+		z.info.MasterTSIGKeyIDs = masterTSIGKeyIDs
+	}
+	if update.SlaveTSIGKeyIDs != nil {
+		z.info.SlaveTSIGKeyIDs = slaveTSIGKeyIDs
+	}
+	if update.DNSSEC != nil {
+		if *update.DNSSEC && len(z.cryptoKeys) == 0 {
+			if err := s.secureZone(z); err != nil {
+				return err
+			}
+		}
+		if !*update.DNSSEC {
+			z.cryptoKeys = nil
+			z.info.NSEC3Param = ""
+			z.info.NSEC3Narrow = false
+		}
+	}
+	// Like PowerDNS, nsec3narrow is only read along with nsec3param
+	if update.NSEC3Param != nil {
+		narrow := update.NSEC3Narrow != nil && *update.NSEC3Narrow
+		if err := z.setNSEC3Param(*update.NSEC3Param, narrow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setNSEC3Param switches z to NSEC3 with the given parameters, or back to
+// NSEC when nsec3Param is empty.
+func (z *zone) setNSEC3Param(nsec3Param string, narrow bool) *apiError {
+	if nsec3Param == "" {
+		z.info.NSEC3Param = ""
+		z.info.NSEC3Narrow = false
+		return nil
+	}
+	if len(z.cryptoKeys) == 0 {
+		return errorf(http.StatusUnprocessableEntity, "NSEC3PARAMs provided for zone '%s', but zone is not DNSSEC secured.", z.info.Name)
+	}
+	fields := strings.Fields(nsec3Param)
+	if len(fields) != 4 || fields[0] != "1" {
+		return errorf(http.StatusUnprocessableEntity, "NSEC3PARAMs provided for zone '%s' are invalid. Invalid NSEC3PARAM '%s'", z.info.Name, nsec3Param)
+	}
+	z.info.NSEC3Param = strings.ToLower(strings.Join(fields, " "))
+	z.info.NSEC3Narrow = narrow
+	return nil
+}
+
+// authHandler routes the requests to the authoritative server.
+func (s *Server) authHandler() http.Handler {
+	mux := http.NewServeMux()
+	s.handleServers(mux, "authoritative", s.version)
+
+	zones := "/api/v1/servers/{server}/zones"
+	zone := zones + "/{zone}"
+	mux.HandleFunc("GET "+zones, s.listZones)
+	mux.HandleFunc("POST "+zones, s.postZone)
+	mux.HandleFunc("GET "+zone, s.withZone(s.getZone))
+	mux.HandleFunc("PUT "+zone, s.withZone(s.putZone))
+	mux.HandleFunc("PATCH "+zone, s.withZone(s.patchZone))
+	mux.HandleFunc("DELETE "+zone, s.withZone(s.deleteZone))
+	mux.HandleFunc("PUT "+zone+"/rectify", s.withZone(s.rectifyZone))
+	mux.HandleFunc("PUT "+zone+"/notify", s.withZone(s.notifyZone))
+	mux.HandleFunc("PUT "+zone+"/axfr-retrieve", s.withZone(s.axfrRetrieveZone))
+
+	mux.HandleFunc("GET "+zone+"/metadata", s.withZone(s.listMetadata))
+	mux.HandleFunc("POST "+zone+"/metadata", s.withZone(s.postMetadata))
+	mux.HandleFunc("GET "+zone+"/metadata/{kind}", s.withZone(s.getMetadata))
+	mux.HandleFunc("PUT "+zone+"/metadata/{kind}", s.withZone(s.putMetadata))
+	mux.HandleFunc("DELETE "+zone+"/metadata/{kind}", s.withZone(s.deleteMetadata))
+
+	mux.HandleFunc("GET "+zone+"/cryptokeys", s.withZone(s.listCryptoKeys))
+	mux.HandleFunc("POST "+zone+"/cryptokeys", s.withZone(s.postCryptoKey))
+	mux.HandleFunc("GET "+zone+"/cryptokeys/{id}", s.withZone(s.getCryptoKey))
+	mux.HandleFunc("PUT "+zone+"/cryptokeys/{id}", s.withZone(s.putCryptoKey))
+	mux.HandleFunc("DELETE "+zone+"/cryptokeys/{id}", s.withZone(s.deleteCryptoKey))
+
+	tsigKeys := "/api/v1/servers/{server}/tsigkeys"
+	tsigKey := tsigKeys + "/{id}"
+	mux.HandleFunc("GET "+tsigKeys, s.listTSIGKeys)
+	mux.HandleFunc("POST "+tsigKeys, s.postTSIGKey)
+	mux.HandleFunc("GET "+tsigKey, s.withTSIGKey(s.getTSIGKey))
+	mux.HandleFunc("PUT "+tsigKey, s.withTSIGKey(s.putTSIGKey))
+	mux.HandleFunc("DELETE "+tsigKey, s.withTSIGKey(s.deleteTSIGKey))
+
+	autoprimaries := "/api/v1/servers/{server}/autoprimaries"
+	mux.HandleFunc("GET "+autoprimaries, s.listAutoprimaries)
+	mux.HandleFunc("POST "+autoprimaries, s.postAutoprimary)
+	mux.HandleFunc("DELETE "+autoprimaries+"/{ip}/{nameserver}", s.deleteAutoprimary)
+
+	mux.HandleFunc("PUT /api/v1/servers/{server}/cache/flush", s.flushAuthCache)
+
+	return s.serve(false, mux)
+}
+
+// zoneHandler handles a request on an existing zone, with s.mu held.
+type zoneHandler func(w http.ResponseWriter, r *http.Request, z *zone)
+
+// withZone looks up the zone of the request path before calling handle.
+func (s *Server) withZone(handle zoneHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.checkServer(w, r) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		z, ok := s.zones[zoneID(r.PathValue("zone"))]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		handle(w, r, z)
+	}
+}
+
+func (s *Server) listZones(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter := r.URL.Query().Get("zone")
+	zones := []Zone{}
+	for name, z := range s.zones {
+		if filter == "" || name == canonicalName(filter) {
+			zones = append(zones, z.view(false, nil))
+		}
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	writeJSON(w, http.StatusOK, zones)
+}
+
+func (s *Server) postZone(w http.ResponseWriter, r *http.Request) {
+	if !s.checkServer(w, r) {
+		return
+	}
+
+	var info Zone
+	if err := decodeBody(r, &info); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, err := s.createZone(info)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, z.view(true, nil))
+}
+
+func (s *Server) getZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	query := r.URL.Query()
+	if query.Get("rrsets") == "false" {
+		writeJSON(w, http.StatusOK, z.view(false, nil))
+		return
+	}
+
+	name, tpe := query.Get("rrset_name"), query.Get("rrset_type")
+	if tpe != "" && name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "rrset_type requires rrset_name to be set")
+		return
+	}
+	var filter func(RRSet) bool
+	if name != "" {
+		filter = func(rrSet RRSet) bool {
+			return rrSet.Name == canonicalName(name) && (tpe == "" || rrSet.Type == strings.ToUpper(tpe))
+		}
+	}
+	writeJSON(w, http.StatusOK, z.view(true, filter))
+}
+
+func (s *Server) putZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	var update zoneUpdate
+	if err := decodeBody(r, &update); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if err := s.updateZone(z, update); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) patchZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	var body struct {
+		RRSets []rrSetChange `json:"rrsets"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if z.secondary() {
+		writeError(w, http.StatusUnprocessableEntity, "Modifying RRsets in Slave zones is prohibited")
+		return
+	}
+	if err := z.patch(body.RRSets, s.recordEditsOff); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	z.bumpSerial()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteZone(w http.ResponseWriter, r *http.Request, z *zone) {
+	delete(s.zones, z.info.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ action.Action = &CacheFlushAction{}
+var _ action.ActionWithConfigure = &CacheFlushAction{}
+
+// CacheFlushAction defines the action implementation.
+type CacheFlushAction struct {
+	client *Client
+}
+
+// CacheFlushActionModel describes the action data model.
+type CacheFlushActionModel struct {
+	Domain DNSNameValue `tfsdk:"domain"`
+}
+
+func (a *CacheFlushAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cache_flush"
+}
+
+func (a *CacheFlushAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Flushes the packet cache of the authoritative server for a domain and the names below it.",
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				MarkdownDescription: "The name to flush, along with the names below it. The trailing dot is optional.",
+				CustomType:          DNSNameType{},
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (a *CacheFlushAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type", "Expected *Client")
+		return
+	}
+	a.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_cache_flush")
+}
+
+func (a *CacheFlushAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data CacheFlushActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain := data.Domain.FQDN()
+	ctx = tflog.SetField(ctx, "domain", domain)
+	tflog.Debug(ctx, "Flushing PowerDNS packet cache")
+
+	result, err := a.client.FlushCache(ctx, domain)
+	if err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to flush cache", fmt.Errorf("error flushing PowerDNS packet cache for %s: %w", domain, err), path.Root("domain"), nil)
+		return
+	}
+
+	tflog.Info(ctx, "Flushed PowerDNS packet cache", map[string]any{"count": result.Count})
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("%s: flushed %d cache entries", domain, result.Count)})
+}
+
+func NewCacheFlushAction() action.Action {
+	return &CacheFlushAction{}
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheFlush_Action(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, resource.TestCase{
+		// Actions were introduced in Terraform 1.14
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.14.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					server.CacheAnswer(false, "www.flush.sysa.abc.", "A")
+					server.CacheAnswer(false, "mail.flush.sysa.abc.", "A")
+				},
+				Config: testFakeServerConfig(server, testAccPDNSCacheFlushConfig),
+				Check: func(*terraform.State) error {
+					if cached := server.CachedAnswers(false); cached != 1 {
+						return fmt.Errorf("expected 1 cached answer left, got %d", cached)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+const testAccPDNSCacheFlushConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+action "powerdns_cache_flush" "test" {
+	config {
+		domain = "www.flush.sysa.abc"
+	}
+}
+
+resource "powerdns_zone" "test" {
+	name        = "flush.sysa.abc."
+	kind        = "Native"
+	nameservers = ["ns1.sysa.abc.", "ns2.sysa.abc."]
+}
+
+resource "powerdns_record" "test" {
+	zone    = powerdns_zone.test.name
+	name    = "www.flush.sysa.abc."
+	type    = "A"
+	ttl     = 60
+	records = ["192.0.2.1"]
+
+	lifecycle {
+		action_trigger {
+			events  = [after_create, after_update]
+			actions = [action.powerdns_cache_flush.test]
+		}
+	}
+}`
+
+func TestCacheFlush_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	server.CacheAnswer(false, "example.com.", "SOA")
+	server.CacheAnswer(false, "www.example.com.", "A")
+	server.CacheAnswer(false, "example.org.", "A")
+
+	result, err := client.FlushCache(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, CacheFlushResult{Count: 2, Result: "Flushed cache."}, result)
+	assert.Equal(t, 1, server.CachedAnswers(false))
+
+	_, err = client.FlushCache(ctx, "example.org")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+}
+
+func TestCacheFlush_Invoke(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	server.CacheAnswer(false, "www.example.com.", "A")
+	server.CacheAnswer(false, "example.org.", "A")
+
+	a := &CacheFlushAction{client: client}
+	schemaResp := &action.SchemaResponse{}
+	a.Schema(ctx, action.SchemaRequest{}, schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	config := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+		"domain": tftypes.NewValue(tftypes.String, "WWW.example.com"),
+	})
+
+	// The domain is sent with its trailing dot, which the server requires
+	var progress []string
+	resp := &action.InvokeResponse{SendProgress: func(event action.InvokeProgressEvent) { progress = append(progress, event.Message) }}
+	a.Invoke(ctx, action.InvokeRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}, resp)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	assert.Equal(t, []string{"WWW.example.com.: flushed 1 cache entries"}, progress)
+	assert.Equal(t, 1, server.CachedAnswers(false))
+}
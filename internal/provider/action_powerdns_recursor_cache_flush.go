@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ action.Action = &RecursorCacheFlushAction{}
+var _ action.ActionWithConfigure = &RecursorCacheFlushAction{}
+
+// RecursorCacheFlushAction defines the action implementation.
+type RecursorCacheFlushAction struct {
+	client *Client
+}
+
+// RecursorCacheFlushActionModel describes the action data model.
+type RecursorCacheFlushActionModel struct {
+	Domain  DNSNameValue `tfsdk:"domain"`
+	Subtree types.Bool   `tfsdk:"subtree"`
+	Type    types.String `tfsdk:"type"`
+}
+
+func (a *RecursorCacheFlushAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_recursor_cache_flush"
+}
+
+func (a *RecursorCacheFlushAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Flushes the record, negative and packet caches of the recursor for a domain.",
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				MarkdownDescription: "The name to flush. The trailing dot is optional.",
+				CustomType:          DNSNameType{},
+				Required:            true,
+			},
+			"subtree": schema.BoolAttribute{
+				MarkdownDescription: "Whether to also flush the names below the domain. Defaults to `false`.",
+				Optional:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Only flush the records of this type, such as `A`. All types are flushed when unset.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (a *RecursorCacheFlushAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type", "Expected *Client")
+		return
+	}
+	a.client = client
+
+	requireRecursorServer(&resp.Diagnostics, client, "powerdns_recursor_cache_flush")
+}
+
+func (a *RecursorCacheFlushAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data RecursorCacheFlushActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain := data.Domain.FQDN()
+	ctx = tflog.SetField(ctx, "domain", domain)
+	tflog.Debug(ctx, "Flushing PowerDNS recursor caches", map[string]any{
+		"subtree": data.Subtree.ValueBool(),
+		"type":    data.Type.ValueString(),
+	})
+
+	result, err := a.client.FlushRecursorCache(ctx, domain, data.Subtree.ValueBool(), data.Type.ValueString())
+	if err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to flush recursor cache", fmt.Errorf("error flushing PowerDNS recursor caches for %s: %w", domain, err), path.Root("domain"), nil)
+		return
+	}
+
+	tflog.Info(ctx, "Flushed PowerDNS recursor caches", map[string]any{"count": result.Count})
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("%s: flushed %d cache entries", domain, result.Count)})
+}
+
+func NewRecursorCacheFlushAction() action.Action {
+	return &RecursorCacheFlushAction{}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecursorCacheFlush_Action(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, resource.TestCase{
+		// Actions were introduced in Terraform 1.14
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.14.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					server.CacheAnswer(true, "flush.example.com.", "SOA")
+					server.CacheAnswer(true, "www.flush.example.com.", "A")
+					server.CacheAnswer(true, "example.org.", "A")
+				},
+				Config: testFakeServerConfig(server, testAccPDNSRecursorCacheFlushConfig),
+				Check: func(*terraform.State) error {
+					if cached := server.CachedAnswers(true); cached != 1 {
+						return fmt.Errorf("expected 1 cached answer left, got %d", cached)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+const testAccPDNSRecursorCacheFlushConfig = `
+provider "powerdns" {
+  server_url          = "http://localhost:8081"
+  recursor_server_url = "http://localhost:8082"
+  api_key             = "secret"
+}
+
+action "powerdns_recursor_cache_flush" "test" {
+  config {
+    domain  = "flush.example.com"
+    subtree = true
+  }
+}
+
+resource "powerdns_recursor_forward_zone" "test" {
+  zone    = "flush.example.com."
+  servers = ["192.0.2.53"]
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create, after_update]
+      actions = [action.powerdns_recursor_cache_flush.test]
+    }
+  }
+}
+`
+
+func TestRecursorCacheFlush_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	server.CacheAnswer(true, "www.example.com.", "A")
+	server.CacheAnswer(true, "www.example.com.", "AAAA")
+	server.CacheAnswer(true, "mail.example.com.", "A")
+
+	tests := []struct {
+		name          string
+		domain        string
+		subtree       bool
+		tpe           string
+		expectedCount int64
+	}{
+		{name: "exact name", domain: "example.com.", expectedCount: 0},
+		{name: "type", domain: "www.example.com.", tpe: "AAAA", expectedCount: 1},
+		{name: "subtree", domain: "example.com.", subtree: true, expectedCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.FlushRecursorCache(ctx, tt.domain, tt.subtree, tt.tpe)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, result.Count)
+		})
+	}
+	assert.Equal(t, 0, server.CachedAnswers(true))
+}
+
+func TestClient_RecursorCacheFlushEndpoint(t *testing.T) {
+	client := &Client{RecursorServerID: "localhost"}
+	assert.Equal(t, "/servers/localhost/cache/flush?domain=example.com.&subtree=false", client.recursorCacheFlushEndpoint("example.com.", false, ""))
+	assert.Equal(t, "/servers/localhost/cache/flush?domain=example.com.&subtree=true&type=AAAA", client.recursorCacheFlushEndpoint("example.com.", true, "AAAA"))
+}
+
+func TestRecursorCacheFlush_Invoke(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	server.CacheAnswer(true, "www.example.com.", "A")
+	server.CacheAnswer(true, "example.org.", "A")
+
+	a := &RecursorCacheFlushAction{client: client}
+	schemaResp := &action.SchemaResponse{}
+	a.Schema(ctx, action.SchemaRequest{}, schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	config := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+		"domain":  tftypes.NewValue(tftypes.String, "example.com"),
+		"subtree": tftypes.NewValue(tftypes.Bool, true),
+		"type":    tftypes.NewValue(tftypes.String, nil),
+	})
+
+	// The domain is sent with its trailing dot, which the server requires
+	resp := &action.InvokeResponse{SendProgress: func(action.InvokeProgressEvent) {}}
+	a.Invoke(ctx, action.InvokeRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}, resp)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	assert.Equal(t, 1, server.CachedAnswers(true))
+}
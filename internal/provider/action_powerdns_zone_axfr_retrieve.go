@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ action.Action = &ZoneAXFRRetrieveAction{}
+var _ action.ActionWithConfigure = &ZoneAXFRRetrieveAction{}
+var _ action.ActionWithValidateConfig = &ZoneAXFRRetrieveAction{}
+
+// defaultZoneTransferTimeout is how long the action waits for a transfer to
+// complete when wait_timeout is unset.
+const defaultZoneTransferTimeout = 5 * time.Minute
+
+// zoneTransferPollInterval is how often the action checks whether a transfer
+// completed.
+const zoneTransferPollInterval = 2 * time.Second
+
+// ZoneAXFRRetrieveAction defines the action implementation.
+type ZoneAXFRRetrieveAction struct {
+	client *Client
+}
+
+// ZoneAXFRRetrieveActionModel describes the action data model.
+type ZoneAXFRRetrieveActionModel struct {
+	Zone        types.String `tfsdk:"zone"`
+	Wait        types.Bool   `tfsdk:"wait"`
+	WaitTimeout types.String `tfsdk:"wait_timeout"`
+}
+
+func (a *ZoneAXFRRetrieveAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_axfr_retrieve"
+}
+
+func (a *ZoneAXFRRetrieveAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Makes a secondary zone transfer from its primary now, instead of waiting for its refresh timer.",
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "The name of the secondary zone to transfer",
+				Required:            true,
+			},
+			"wait": schema.BoolAttribute{
+				MarkdownDescription: "Whether to wait for the serial of the zone to advance, the transfer completing asynchronously. Defaults to `false`.",
+				Optional:            true,
+			},
+			"wait_timeout": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("How long to wait for the transfer as a duration, e.g. `30s` or `5m`. Only valid with `wait`. Defaults to `%s`.", defaultZoneTransferTimeout),
+				Optional:            true,
+				Validators: []validator.String{
+					DurationValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (a *ZoneAXFRRetrieveAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type", "Expected *Client")
+		return
+	}
+	a.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone_axfr_retrieve")
+}
+
+func (a *ZoneAXFRRetrieveAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var data ZoneAXFRRetrieveActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Wait.IsUnknown() || data.WaitTimeout.IsNull() {
+		return
+	}
+
+	if !data.Wait.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("wait_timeout"), "Invalid configuration", "'wait_timeout' can only be set when 'wait' is true")
+	}
+}
+
+func (a *ZoneAXFRRetrieveAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data ZoneAXFRRetrieveActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneName := data.Zone.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+
+	wait := data.Wait.ValueBool()
+	timeout := defaultZoneTransferTimeout
+	if !data.WaitTimeout.IsNull() {
+		parsed, err := time.ParseDuration(data.WaitTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("wait_timeout"), "Invalid timeout", fmt.Sprintf("Unable to parse wait_timeout %q: %s", data.WaitTimeout.ValueString(), err))
+			return
+		}
+		timeout = parsed
+	}
+
+	// The serial before the transfer tells when it completed
+	var serial int64
+	if wait {
+		var err error
+		if serial, err = a.client.ZoneSerial(ctx, zoneName); err != nil {
+			addZoneTransferError(&resp.Diagnostics, zoneName, err)
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "Retrieving PowerDNS zone from its primary")
+	result, err := a.client.RetrieveZone(ctx, zoneName)
+	if err != nil {
+		addZoneTransferError(&resp.Diagnostics, zoneName, err)
+		return
+	}
+	tflog.Info(ctx, "Queued transfer of PowerDNS zone", map[string]any{"result": result})
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("%s: %s", zoneName, result)})
+
+	if !wait {
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	transferred, err := a.client.WaitForZoneSerial(waitCtx, zoneName, serial, zoneTransferPollInterval)
+	if err != nil {
+		// PowerDNS skips the transfer of a zone already up to date with its
+		// primary, which can't be told apart from a failed transfer
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("wait_timeout"),
+				"Zone transfer not observed",
+				fmt.Sprintf("The serial of zone %s didn't advance past %d within %s. The zone may already be up to date with its primary, or the transfer may have failed, see the logs of the server.", zoneName, serial, timeout),
+			)
+			return
+		}
+		addZoneTransferError(&resp.Diagnostics, zoneName, err)
+		return
+	}
+
+	tflog.Info(ctx, "Transferred PowerDNS zone", map[string]any{"serial": transferred})
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("%s: transferred serial %d", zoneName, transferred)})
+}
+
+// addZoneTransferError reports an error of a request about the zone to
+// transfer.
+func addZoneTransferError(diags *diag.Diagnostics, zoneName string, err error) {
+	if errors.Is(err, ErrNotFound) {
+		diags.AddAttributeError(path.Root("zone"), "Zone not found", fmt.Sprintf("zone %s not found", zoneName))
+		return
+	}
+	addAPIErrorDiagnostic(diags, "Failed to retrieve zone", fmt.Errorf("error retrieving PowerDNS zone %s from its primary: %w", zoneName, err), path.Root("zone"), nil)
+}
+
+func NewZoneAXFRRetrieveAction() action.Action {
+	return &ZoneAXFRRetrieveAction{}
+}
@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/coocood/freecache"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneAXFRRetrieve_Action(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, resource.TestCase{
+		// Actions were introduced in Terraform 1.14
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.14.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneAXFRRetrieveConfig),
+				// The fake primary always has a newer version of the zone
+				Check: func(*terraform.State) error {
+					zone, ok := server.Zone("axfr.sysa.abc.")
+					if !ok {
+						return fmt.Errorf("zone axfr.sysa.abc. not found")
+					}
+					if zone.Serial == 0 {
+						return fmt.Errorf("expected the zone to be transferred")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// The primary of the zone doesn't exist, so the action doesn't wait for the
+// transfer.
+const testAccPDNSZoneAXFRRetrieveConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+action "powerdns_zone_axfr_retrieve" "test" {
+	config {
+		zone = "axfr.sysa.abc."
+	}
+}
+
+resource "powerdns_zone" "test" {
+	name    = "axfr.sysa.abc."
+	kind    = "Slave"
+	masters = ["192.0.2.1"]
+
+	lifecycle {
+		action_trigger {
+			events  = [after_create]
+			actions = [action.powerdns_zone_axfr_retrieve.test]
+		}
+	}
+}`
+
+func TestZoneAXFRRetrieve_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Slave", Masters: []string{"192.0.2.1"}})
+	require.NoError(t, err)
+
+	result, err := client.RetrieveZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "Added retrieval request for 'example.com.' from primary 192.0.2.1", result)
+	server.ResetRequests()
+	serial, err := client.WaitForZoneSerial(ctx, "example.com.", 0, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), serial)
+	// Polling leaves the rrsets of the zone out
+	requests := server.Requests()
+	require.NotEmpty(t, requests)
+	assert.Equal(t, "rrset_name=example.com.&rrset_type=SOA", requests[len(requests)-1].RawQuery)
+
+	// A transfer that doesn't complete in time
+	server.HoldTransfers(true)
+	_, err = client.RetrieveZone(ctx, "example.com.")
+	require.NoError(t, err)
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForZoneSerial(waitCtx, "example.com.", serial, time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// A transfer completing while waiting
+	time.AfterFunc(10*time.Millisecond, func() { server.HoldTransfers(false) })
+	serial, err = client.WaitForZoneSerial(ctx, "example.com.", serial, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial)
+
+	_, err = client.CreateZone(ctx, ZoneInfo{Name: "example.org.", Kind: "Native", Nameservers: []string{"ns1.example.org."}})
+	require.NoError(t, err)
+	_, err = client.RetrieveZone(ctx, "example.org.")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+}
+
+func TestZoneAXFRRetrieve_CacheInvalidated(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	client.CacheEnable = true
+	client.Cache = freecache.NewCache(1024 * 1024)
+	client.CacheTTL = 60
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Slave", Masters: []string{"192.0.2.1"}})
+	require.NoError(t, err)
+	zoneFetches := func() int {
+		fetches := 0
+		for _, request := range server.Requests() {
+			if request.Method == http.MethodGet && request.Path == "/api/v1/servers/localhost/zones/example.com." && request.RawQuery == "" {
+				fetches++
+			}
+		}
+		return fetches
+	}
+
+	_, err = client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	_, err = client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	require.Equal(t, 1, zoneFetches())
+
+	// The zone cached while the transfer is pending is dropped once the
+	// transfer is observed
+	server.HoldTransfers(true)
+	_, err = client.RetrieveZone(ctx, "example.com.")
+	require.NoError(t, err)
+	_, err = client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, 2, zoneFetches(), "read after axfr-retrieve must not be served from the cache")
+
+	server.HoldTransfers(false)
+	_, err = client.WaitForZoneSerial(ctx, "example.com.", 0, time.Millisecond)
+	require.NoError(t, err)
+	_, err = client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, 3, zoneFetches(), "read after the transfer must not be served from the cache")
+}
+
+func TestZoneAXFRRetrieve_ActionWait(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, resource.TestCase{
+		// Actions were introduced in Terraform 1.14
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.14.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testFakeServerConfig(server, testAccPDNSZoneAXFRRetrieveWaitConfig("false", "1s")),
+				ExpectError: regexp.MustCompile("'wait_timeout' can only be set when 'wait' is true"),
+			},
+			// A transfer that is never observed, as when the zone is already
+			// up to date, only gets a warning
+			{
+				PreConfig: func() { server.HoldTransfers(true) },
+				Config:    testFakeServerConfig(server, testAccPDNSZoneAXFRRetrieveWaitConfig("true", "1s")),
+				Check: func(*terraform.State) error {
+					if zone, ok := server.Zone("axfr.sysa.abc."); !ok || zone.Serial != 0 {
+						return fmt.Errorf("expected zone axfr.sysa.abc. not to be transferred")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccPDNSZoneAXFRRetrieveWaitConfig(wait string, waitTimeout string) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+action "powerdns_zone_axfr_retrieve" "test" {
+	config {
+		zone         = "axfr.sysa.abc."
+		wait         = %s
+		wait_timeout = %q
+	}
+}
+
+resource "powerdns_zone" "test" {
+	name    = "axfr.sysa.abc."
+	kind    = "Slave"
+	masters = ["192.0.2.1"]
+
+	lifecycle {
+		action_trigger {
+			events  = [after_create]
+			actions = [action.powerdns_zone_axfr_retrieve.test]
+		}
+	}
+}`, wait, waitTimeout)
+}
+
+func TestZoneAXFRRetrieve_SerialAfter(t *testing.T) {
+	tests := []struct {
+		a        int64
+		b        int64
+		expected bool
+	}{
+		{a: 1, b: 0, expected: true},
+		{a: 0, b: 0, expected: false},
+		{a: 0, b: 1, expected: false},
+		{a: 2147483647, b: 0, expected: true},
+		// Zones never transferred have a serial of 0, which any other
+		// serial comes after
+		{a: 2147483648, b: 0, expected: true},
+		{a: 2026101601, b: 0, expected: true},
+		{a: 4294967295, b: 0, expected: true},
+		// Serials wrap around
+		{a: 0, b: 4294967295, expected: true},
+		{a: 5, b: 4294967290, expected: true},
+		{a: 2147483649, b: 1, expected: false},
+		{a: 1, b: 2147483649, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%d", tt.a, tt.b), func(t *testing.T) {
+			assert.Equal(t, tt.expected, serialAfter(tt.a, tt.b))
+		})
+	}
+}
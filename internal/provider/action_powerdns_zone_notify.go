@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ action.Action = &ZoneNotifyAction{}
+var _ action.ActionWithConfigure = &ZoneNotifyAction{}
+
+// ZoneNotifyAction defines the action implementation.
+type ZoneNotifyAction struct {
+	client *Client
+}
+
+// ZoneNotifyActionModel describes the action data model.
+type ZoneNotifyActionModel struct {
+	Zone types.String `tfsdk:"zone"`
+}
+
+func (a *ZoneNotifyAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_notify"
+}
+
+func (a *ZoneNotifyAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sends a DNS NOTIFY of a zone to its secondaries, prompting them to transfer it.",
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "The name of the zone whose secondaries to notify",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (a *ZoneNotifyAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type", "Expected *Client")
+		return
+	}
+	a.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone_notify")
+}
+
+func (a *ZoneNotifyAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data ZoneNotifyActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneName := data.Zone.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	tflog.Debug(ctx, "Notifying secondaries of PowerDNS zone")
+
+	result, err := a.client.NotifyZone(ctx, zoneName)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			resp.Diagnostics.AddAttributeError(path.Root("zone"), "Zone not found", fmt.Sprintf("zone %s not found", zoneName))
+			return
+		}
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to notify zone", fmt.Errorf("error notifying PowerDNS zone %s: %w", zoneName, err), path.Root("zone"), nil)
+		return
+	}
+
+	tflog.Info(ctx, "Notified secondaries of PowerDNS zone", map[string]any{"result": result})
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("%s: %s", zoneName, result)})
+}
+
+func NewZoneNotifyAction() action.Action {
+	return &ZoneNotifyAction{}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneNotify_Action(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, resource.TestCase{
+		// Actions were introduced in Terraform 1.14
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.14.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneNotifyConfig),
+				Check: func(*terraform.State) error {
+					zone, ok := server.Zone("notify.sysa.abc.")
+					if !ok {
+						return fmt.Errorf("zone notify.sysa.abc. not found")
+					}
+					if zone.NotifiedSerial == 0 || zone.NotifiedSerial != zone.Serial {
+						return fmt.Errorf("expected serial %d to be notified, got %d", zone.Serial, zone.NotifiedSerial)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+const testAccPDNSZoneNotifyConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+action "powerdns_zone_notify" "test" {
+	config {
+		zone = "notify.sysa.abc."
+	}
+}
+
+resource "powerdns_zone" "test" {
+	name        = "notify.sysa.abc."
+	kind        = "Master"
+	nameservers = ["ns1.sysa.abc.", "ns2.sysa.abc."]
+
+	lifecycle {
+		action_trigger {
+			events  = [after_create, after_update]
+			actions = [action.powerdns_zone_notify.test]
+		}
+	}
+}`
+
+func TestZoneNotify_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Master", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	result, err := client.NotifyZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "Notification queued", result)
+	zone, ok := server.Zone("example.com.")
+	require.True(t, ok)
+	assert.Equal(t, zone.Serial, zone.NotifiedSerial)
+
+	_, err = client.NotifyZone(ctx, "missing.com.")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
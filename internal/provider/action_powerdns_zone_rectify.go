@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ action.Action = &ZoneRectifyAction{}
+var _ action.ActionWithConfigure = &ZoneRectifyAction{}
+
+// ZoneRectifyAction defines the action implementation.
+type ZoneRectifyAction struct {
+	client *Client
+}
+
+// ZoneRectifyActionModel describes the action data model.
+type ZoneRectifyActionModel struct {
+	Zone types.String `tfsdk:"zone"`
+}
+
+func (a *ZoneRectifyAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_rectify"
+}
+
+func (a *ZoneRectifyAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Rectifies a zone, computing the ordering and the authoritative flags of its records DNSSEC relies on.",
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "The name of the zone to rectify",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (a *ZoneRectifyAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type", "Expected *Client")
+		return
+	}
+	a.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone_rectify")
+}
+
+func (a *ZoneRectifyAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data ZoneRectifyActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneName := data.Zone.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	tflog.Debug(ctx, "Rectifying PowerDNS zone")
+
+	result, err := a.client.RectifyZone(ctx, zoneName)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			resp.Diagnostics.AddAttributeError(path.Root("zone"), "Zone not found", fmt.Sprintf("zone %s not found", zoneName))
+			return
+		}
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to rectify zone", fmt.Errorf("error rectifying PowerDNS zone %s: %w", zoneName, err), path.Root("zone"), nil)
+		return
+	}
+
+	tflog.Info(ctx, "Rectified PowerDNS zone", map[string]any{"result": result})
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("%s: %s", zoneName, result)})
+}
+
+func NewZoneRectifyAction() action.Action {
+	return &ZoneRectifyAction{}
+}
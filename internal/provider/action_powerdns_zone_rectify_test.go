@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneRectify_Action(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, resource.TestCase{
+		// Actions were introduced in Terraform 1.14
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.14.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneRectifyConfig),
+				// Once after creating the zone, once after creating the record
+				Check: func(*terraform.State) error {
+					rectified := 0
+					for _, request := range server.Requests() {
+						if request.Method == http.MethodPut && strings.HasSuffix(request.Path, "/zones/rectify.sysa.abc./rectify") {
+							rectified++
+						}
+					}
+					if rectified != 2 {
+						return fmt.Errorf("expected the zone to be rectified twice, got %d", rectified)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+const testAccPDNSZoneRectifyConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+action "powerdns_zone_rectify" "test" {
+	config {
+		zone = "rectify.sysa.abc."
+	}
+}
+
+resource "powerdns_zone" "test" {
+	name        = "rectify.sysa.abc."
+	kind        = "Native"
+	nameservers = ["ns1.sysa.abc.", "ns2.sysa.abc."]
+
+	lifecycle {
+		action_trigger {
+			events  = [after_create, after_update]
+			actions = [action.powerdns_zone_rectify.test]
+		}
+	}
+}
+
+resource "powerdns_record" "test" {
+	zone    = powerdns_zone.test.name
+	name    = "www.rectify.sysa.abc."
+	type    = "A"
+	ttl     = 60
+	records = ["192.0.2.1"]
+
+	lifecycle {
+		action_trigger {
+			events  = [after_create, after_update]
+			actions = [action.powerdns_zone_rectify.test]
+		}
+	}
+}`
+
+func TestZoneRectify_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	result, err := client.RectifyZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "Rectified", result)
+
+	// Presigned zones keep the ordering of their master
+	presigned := true
+	require.NoError(t, client.UpdateZone(ctx, "example.com.", ZoneInfoUpd{Name: "example.com.", Kind: "Native", Presigned: &presigned}))
+	_, err = client.RectifyZone(ctx, "example.com.")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+
+	_, err = client.RectifyZone(ctx, "missing.com.")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
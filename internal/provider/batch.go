@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxBatchSize bounds the number of rrsets sent in a single PATCH.
+const maxBatchSize = 1000
+
+// WithRRSetBatching enables batching of rrset changes: REPLACE and DELETE
+// changes to the same zone submitted within window are sent as one PATCH,
+// bumping the SOA serial and notifying secondaries once. Zero disables it.
+func WithRRSetBatching(window time.Duration) ClientOption {
+	return func(client *Client) {
+		client.BatchWindow = window
+	}
+}
+
+// batchedChange is an rrset change waiting for its batch to be sent.
+type batchedChange struct {
+	rrSet ResourceRecordSet
+	done  chan error
+}
+
+// rrSetBatch collects the changes to one zone.
+type rrSetBatch struct {
+	ctx     context.Context
+	zone    string
+	changes []*batchedChange
+	timer   *time.Timer
+
+	previous *rrSetBatch   // Batch of the same zone to send before this one
+	sent     chan struct{} // Closed once the batch has been sent
+}
+
+// has reports whether the batch already changes the rrset of rrSet. PowerDNS
+// rejects a PATCH changing the same rrset twice.
+func (b *rrSetBatch) has(rrSet ResourceRecordSet) bool {
+	for _, change := range b.changes {
+		if strings.EqualFold(change.rrSet.Name, rrSet.Name) && strings.EqualFold(change.rrSet.Type, rrSet.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// rrSetBatcher coalesces rrset changes per zone.
+type rrSetBatcher struct {
+	client *Client
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*rrSetBatch
+	last    map[string]*rrSetBatch // Latest batch of each zone, until it is sent
+}
+
+func newRRSetBatcher(client *Client, window time.Duration) *rrSetBatcher {
+	return &rrSetBatcher{
+		client:  client,
+		window:  window,
+		pending: make(map[string]*rrSetBatch),
+		last:    make(map[string]*rrSetBatch),
+	}
+}
+
+// submit adds rrSet to the pending batch of zone and waits for the outcome of
+// that batch. If ctx ends first, the change may still be applied later.
+func (b *rrSetBatcher) submit(ctx context.Context, zone string, rrSet ResourceRecordSet) error {
+	change := &batchedChange{rrSet: rrSet, done: make(chan error, 1)}
+	key := zoneCacheKey(zone)
+
+	b.mu.Lock()
+	batch := b.pending[key]
+	if batch != nil && batch.has(rrSet) {
+		b.detach(key, batch)
+		batch = nil
+	}
+	if batch == nil {
+		// The batch outlives whichever caller happens to open it. Sending
+		// it is bounded by patchTimeout instead.
+		batch = &rrSetBatch{ctx: context.WithoutCancel(ctx), zone: zone, previous: b.last[key], sent: make(chan struct{})}
+		batch.timer = time.AfterFunc(b.window, func() { b.expire(key, batch) })
+		b.pending[key] = batch
+		b.last[key] = batch
+	}
+	batch.changes = append(batch.changes, change)
+	if len(batch.changes) >= maxBatchSize {
+		b.detach(key, batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-change.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// detach removes batch from the pending ones and sends it right away. Must be
+// called with b.mu held.
+func (b *rrSetBatcher) detach(key string, batch *rrSetBatch) {
+	delete(b.pending, key)
+	batch.timer.Stop()
+	go b.send(key, batch)
+}
+
+// expire sends batch once its window is over, unless it was already detached.
+func (b *rrSetBatcher) expire(key string, batch *rrSetBatch) {
+	b.mu.Lock()
+	if b.pending[key] != batch {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, key)
+	b.mu.Unlock()
+
+	b.send(key, batch)
+}
+
+// send applies the changes of batch and reports the outcome to each caller.
+// The batches of a zone are sent in order, one at a time, so that a change
+// split off from an earlier batch is never overtaken by a later one.
+func (b *rrSetBatcher) send(key string, batch *rrSetBatch) {
+	defer func() {
+		close(batch.sent)
+
+		b.mu.Lock()
+		if b.last[key] == batch {
+			delete(b.last, key)
+		}
+		b.mu.Unlock()
+	}()
+
+	// The previous batch is itself bounded by patchTimeout once sent
+	if batch.previous != nil {
+		<-batch.previous.sent
+	}
+
+	rrSets := make([]ResourceRecordSet, len(batch.changes))
+	for i, change := range batch.changes {
+		rrSets[i] = change.rrSet
+	}
+
+	tflog.Debug(batch.ctx, "Sending batched rrset changes", map[string]interface{}{
+		"zone":   batch.zone,
+		"rrsets": len(rrSets),
+	})
+
+	ctx, cancel := context.WithTimeout(batch.ctx, b.client.patchTimeout())
+	defer cancel()
+	err := b.client.patchRRSets(ctx, batch.zone, rrSets)
+
+	// A single invalid rrset fails the whole PATCH. Send the changes one by
+	// one, so that only the caller at fault gets an error.
+	var apiErr *APIError
+	if err != nil && len(batch.changes) > 1 && errors.As(err, &apiErr) && apiErr.IsValidation() {
+		tflog.Warn(batch.ctx, "Batched rrset changes rejected, sending them individually", map[string]interface{}{
+			"zone":  batch.zone,
+			"error": err.Error(),
+		})
+		for _, change := range batch.changes {
+			change.done <- b.patchAlone(batch, change)
+		}
+		return
+	}
+
+	for _, change := range batch.changes {
+		change.done <- err
+	}
+}
+
+// patchAlone sends change on its own, with as much time as any other PATCH.
+func (b *rrSetBatcher) patchAlone(batch *rrSetBatch, change *batchedChange) error {
+	ctx, cancel := context.WithTimeout(batch.ctx, b.client.patchTimeout())
+	defer cancel()
+	return b.client.patchRRSets(ctx, batch.zone, []ResourceRecordSet{change.rrSet})
+}
+
+// patchTimeout returns the longest a PATCH may take with all its retries, the
+// per-request timeout defaulting to defaultRequestTimeout when there is none.
+func (client *Client) patchTimeout() time.Duration {
+	requestTimeout := client.RequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = defaultRequestTimeout
+	}
+	attempts := time.Duration(client.MaxRetries + 1)
+	return attempts*requestTimeout + (attempts-1)*client.RetryMaxWait
+}
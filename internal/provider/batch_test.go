@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchTestServer records the rrsets of every PATCH it receives, rejecting
+// those changing an rrset named "invalid.example.com.".
+type batchTestServer struct {
+	mu      sync.Mutex
+	patches [][]ResourceRecordSet
+}
+
+func (s *batchTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name": "example.com.", "rrsets": []}`))
+		return
+	}
+
+	var patch zonePatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.patches = append(s.patches, patch.RecordSets)
+	s.mu.Unlock()
+
+	for _, rrSet := range patch.RecordSets {
+		if rrSet.Name == "invalid.example.com." {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error": "RRset invalid.example.com. IN A: Conflicts with pre-existing RRset"}`))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *batchTestServer) patchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, len(s.patches))
+	for i, patch := range s.patches {
+		sizes[i] = len(patch)
+	}
+	return sizes
+}
+
+func newBatchTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := newRetryTestClient(server.URL, 0)
+	client.batcher = newRRSetBatcher(client, 50*time.Millisecond)
+	return client
+}
+
+func replaceConcurrently(client *Client, names ...string) []error {
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.ReplaceRecordSet(context.Background(), "example.com.", ResourceRecordSet{
+				Name:    name,
+				Type:    "A",
+				TTL:     300,
+				Records: []Record{{Content: "192.0.2.1"}},
+			})
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestBatch_ChangesCoalescedPerZone(t *testing.T) {
+	server := &batchTestServer{}
+	client := newBatchTestClient(t, server)
+
+	names := make([]string, 20)
+	for i := range names {
+		names[i] = fmt.Sprintf("host%d.example.com.", i)
+	}
+	for _, err := range replaceConcurrently(client, names...) {
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []int{20}, server.patchSizes())
+}
+
+func TestBatch_InvalidChangeReportedToItsCaller(t *testing.T) {
+	server := &batchTestServer{}
+	client := newBatchTestClient(t, server)
+
+	errs := replaceConcurrently(client, "a.example.com.", "invalid.example.com.", "b.example.com.")
+
+	require.NoError(t, errs[0])
+	require.Error(t, errs[1])
+	assert.Contains(t, errs[1].Error(), "Conflicts with pre-existing RRset")
+	require.NoError(t, errs[2])
+
+	// The rejected batch, then each change on its own
+	assert.Equal(t, []int{3, 1, 1, 1}, server.patchSizes())
+}
+
+func TestBatch_SameRRSetSplitsBatch(t *testing.T) {
+	server := &batchTestServer{}
+	client := newBatchTestClient(t, server)
+
+	errs := replaceConcurrently(client, "www.example.com.", "WWW.example.com.")
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []int{1, 1}, server.patchSizes())
+}
+
+func TestBatch_DeleteAndReplaceShareBatch(t *testing.T) {
+	server := &batchTestServer{}
+	client := newBatchTestClient(t, server)
+
+	var wg sync.WaitGroup
+	var replaceErr, deleteErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, replaceErr = client.ReplaceRecordSet(context.Background(), "example.com.", ResourceRecordSet{
+			Name:    "new.example.com.",
+			Type:    "A",
+			TTL:     300,
+			Records: []Record{{Content: "192.0.2.1"}},
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		deleteErr = client.DeleteRecordSet(context.Background(), "Example.com", "old.example.com.", "A")
+	}()
+	wg.Wait()
+
+	require.NoError(t, replaceErr)
+	require.NoError(t, deleteErr)
+	require.Equal(t, []int{2}, server.patchSizes())
+
+	changeTypes := []string{server.patches[0][0].ChangeType, server.patches[0][1].ChangeType}
+	assert.ElementsMatch(t, []string{"REPLACE", "DELETE"}, changeTypes)
+}
+
+func TestBatch_CallerContextCancelled(t *testing.T) {
+	server := &batchTestServer{}
+	client := newBatchTestClient(t, server)
+	client.batcher.window = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}},
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBatch_SplitBatchesSentInOrder(t *testing.T) {
+	server := &batchTestServer{}
+	var inFlight, maxInFlight atomic.Int32
+	client := newBatchTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			if n > maxInFlight.Load() {
+				maxInFlight.Store(n)
+			}
+			// Outlast the window of the next batch
+			time.Sleep(100 * time.Millisecond)
+		}
+		server.ServeHTTP(w, r)
+	}))
+
+	errs := replaceConcurrently(client, "www.example.com.", "WWW.example.com.", "mail.example.com.")
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, server.patchSizes(), 2)
+	assert.Equal(t, int32(1), maxInFlight.Load())
+}
+
+func TestBatch_PatchTimeout(t *testing.T) {
+	client := newRetryTestClient("http://127.0.0.1", 2)
+	client.RequestTimeout = 10 * time.Second
+	client.RetryMaxWait = time.Second
+	assert.Equal(t, 32*time.Second, client.patchTimeout())
+
+	client.RequestTimeout = 0
+	assert.Equal(t, 3*defaultRequestTimeout+2*time.Second, client.patchTimeout())
+}
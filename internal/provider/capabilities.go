@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// capability is a PowerDNS feature that only some server versions provide.
+type capability struct {
+	name       string           // Human readable name used in diagnostics
+	minVersion *version.Version // First version providing the feature
+	recursor   bool             // Provided by the recursor rather than the authoritative server
+}
+
+// Capability matrix of the PowerDNS authoritative server and recursor.
+var (
+	capabilityLuaRecords       = capability{name: "LUA records", minVersion: version.Must(version.NewVersion("4.2.0"))}
+	capabilityTSIGKeys         = capability{name: "Managing TSIG keys through the API", minVersion: version.Must(version.NewVersion("4.2.0"))}
+	capabilityCatalogZones     = capability{name: "Catalog zones", minVersion: version.Must(version.NewVersion("4.7.0"))}
+	capabilityProducerConsumer = capability{name: "Producer and Consumer zone kinds", minVersion: version.Must(version.NewVersion("4.7.0"))}
+	capabilityAutoprimaries    = capability{name: "Managing autoprimaries through the API", minVersion: version.Must(version.NewVersion("4.7.0"))}
+	capabilityRRSetFilter      = capability{name: "Filtering rrsets on zone retrieval", minVersion: version.Must(version.NewVersion("4.8.0"))}
+	capabilityViews            = capability{name: "Views", minVersion: version.Must(version.NewVersion("5.0.0"))}
+	capabilitySlaveRenotify    = capability{name: "The SLAVE-RENOTIFY metadata kind", minVersion: version.Must(version.NewVersion("4.3.0"))}
+	capabilitySignalingZone    = capability{name: "The SIGNALING-ZONE metadata kind", minVersion: version.Must(version.NewVersion("5.0.0"))}
+
+	capabilityRecursorZones = capability{name: "Managing recursor zones through the API", minVersion: version.Must(version.NewVersion("4.0.0")), recursor: true}
+)
+
+// serverVersionPattern matches the release part of versions reported by
+// PowerDNS, e.g. "4.9.1", "4.8.0-rc1" or "4.7.3-1ubuntu1".
+var serverVersionPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?`)
+
+// parseServerVersion parses a version reported by PowerDNS, ignoring
+// pre-release and packaging suffixes. It returns nil for versions that can't be
+// compared, such as development builds reporting 0.0.
+func parseServerVersion(raw string) *version.Version {
+	release := serverVersionPattern.FindString(raw)
+	if release == "" {
+		return nil
+	}
+	parsed, err := version.NewVersion(release)
+	if err != nil || parsed.Segments()[0] == 0 {
+		return nil
+	}
+	return parsed
+}
+
+// supports reports whether the server providing c is recent enough. known is
+// false when its version couldn't be determined, in which case the feature is
+// assumed to be available and the server has the last word.
+func (client *Client) supports(c capability) (supported bool, known bool) {
+	raw := client.ServerVersion
+	if c.recursor {
+		raw = client.RecursorServerVersion
+	}
+	current := parseServerVersion(raw)
+	if current == nil {
+		return true, false
+	}
+	return current.GreaterThanOrEqual(c.minVersion), true
+}
+
+// requireCapability adds an error on attribute p when the server is known to
+// lack c, so that plans fail before PowerDNS rejects the change at apply.
+func (client *Client) requireCapability(diags *diag.Diagnostics, c capability, p path.Path) {
+	if supported, _ := client.supports(c); supported {
+		return
+	}
+
+	product, current := "PowerDNS", client.ServerVersion
+	if c.recursor {
+		product, current = "PowerDNS Recursor", client.RecursorServerVersion
+	}
+	diags.AddAttributeError(
+		p,
+		"Unsupported PowerDNS version",
+		fmt.Sprintf("%s requires %s >= %s, the server runs %s.", c.name, product, c.minVersion.Original(), current),
+	)
+}
+
+// fetchRRSet retrieves a zone holding at least the rrset of the given name and
+// type, bypassing the cache. Servers supporting it only return that rrset.
+func (client *Client) fetchRRSet(ctx context.Context, zone string, name string, tpe string) (*ZoneInfo, error) {
+	if supported, known := client.supports(capabilityRRSetFilter); !supported || !known {
+		return client.fetchZoneInfo(ctx, zone)
+	}
+
+	query := url.Values{}
+	query.Set("rrset_name", name)
+	query.Set("rrset_type", tpe)
+
+	zoneInfo := new(ZoneInfo)
+	if err := client.doRequest(ctx, methodGet, client.zoneEndpoint(zone)+"?"+query.Encode(), nil, http.StatusOK, zoneInfo); err != nil {
+		return nil, err
+	}
+	tflog.Trace(ctx, "Fetched filtered rrset", map[string]interface{}{
+		"zone": zone,
+		"name": name,
+		"type": tpe,
+	})
+	return zoneInfo, nil
+}
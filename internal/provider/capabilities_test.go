@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities_ParseServerVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "release", raw: "4.9.1", expected: "4.9.1"},
+		{name: "release candidate", raw: "4.8.0-rc1", expected: "4.8.0"},
+		{name: "distribution package", raw: "4.7.3-1ubuntu1", expected: "4.7.3"},
+		{name: "two segments", raw: "5.0", expected: "5.0.0"},
+		{name: "development build", raw: "0.0.20230101.gabcdef"},
+		{name: "empty", raw: ""},
+		{name: "garbage", raw: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := parseServerVersion(tt.raw)
+			if tt.expected == "" {
+				assert.Nil(t, parsed)
+				return
+			}
+			require.NotNil(t, parsed)
+			assert.Equal(t, tt.expected, parsed.String())
+		})
+	}
+}
+
+func TestCapabilities_Supports(t *testing.T) {
+	tests := []struct {
+		name              string
+		serverVersion     string
+		recursorVersion   string
+		capability        capability
+		expectedSupported bool
+		expectedKnown     bool
+	}{
+		{name: "recent server", serverVersion: "4.9.0", capability: capabilityProducerConsumer, expectedSupported: true, expectedKnown: true},
+		{name: "old server", serverVersion: "4.6.4", capability: capabilityAutoprimaries, expectedSupported: false, expectedKnown: true},
+		{name: "pre-release of the first version", serverVersion: "4.8.0-beta1", capability: capabilityRRSetFilter, expectedSupported: true, expectedKnown: true},
+		{name: "signaling zone on 4.9", serverVersion: "4.9.4", capability: capabilitySignalingZone, expectedSupported: false, expectedKnown: true},
+		{name: "catalog zones on 4.6", serverVersion: "4.6.4", capability: capabilityCatalogZones, expectedSupported: false, expectedKnown: true},
+		{name: "catalog zones on 4.7", serverVersion: "4.7.0", capability: capabilityCatalogZones, expectedSupported: true, expectedKnown: true},
+		{name: "views on 4.9", serverVersion: "4.9.4", capability: capabilityViews, expectedSupported: false, expectedKnown: true},
+		{name: "views on 5.0", serverVersion: "5.0.0", capability: capabilityViews, expectedSupported: true, expectedKnown: true},
+		{name: "lua records", serverVersion: "4.1.14", capability: capabilityLuaRecords, expectedSupported: false, expectedKnown: true},
+		{name: "unknown version", serverVersion: "", capability: capabilitySignalingZone, expectedSupported: true, expectedKnown: false},
+		{name: "recursor version used for recursor capabilities", serverVersion: "3.4.0", recursorVersion: "5.1.0", capability: capabilityRecursorZones, expectedSupported: true, expectedKnown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{ServerVersion: tt.serverVersion, RecursorServerVersion: tt.recursorVersion}
+			supported, known := client.supports(tt.capability)
+			assert.Equal(t, tt.expectedSupported, supported)
+			assert.Equal(t, tt.expectedKnown, known)
+		})
+	}
+}
+
+func TestCapabilities_RequireCapability(t *testing.T) {
+	var diags diag.Diagnostics
+	client := &Client{ServerVersion: "4.6.0"}
+
+	client.requireCapability(&diags, capabilityProducerConsumer, path.Root("kind"))
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Producer and Consumer zone kinds requires PowerDNS >= 4.7.0, the server runs 4.6.0.", diags[0].Detail())
+
+	diags = nil
+	client.ServerVersion = "4.7.0"
+	client.requireCapability(&diags, capabilityProducerConsumer, path.Root("kind"))
+	assert.Empty(t, diags)
+
+	client.ServerVersion = "4.9.4"
+	client.requireCapability(&diags, capabilityViews, path.Root("view"))
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Views requires PowerDNS >= 5.0.0, the server runs 4.9.4.", diags[0].Detail())
+}
+
+func TestCapabilities_RecursorServerVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/servers/localhost", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": "localhost", "daemon_type": "recursor", "version": "5.1.2"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), "", server.URL, "secret", nil, false, "", 0, WithRetryPolicy(0, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "5.1.2", client.RecursorServerVersion)
+	assert.Empty(t, client.ServerVersion)
+}
+
+func TestCapabilities_FetchRRSetFiltered(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name": "example.com.", "rrsets": []}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 0)
+
+	client.ServerVersion = "4.7.0"
+	_, err := client.fetchRRSet(context.Background(), "example.com.", "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Empty(t, query)
+
+	client.ServerVersion = "4.8.0"
+	_, err = client.fetchRRSet(context.Background(), "example.com.", "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Equal(t, "rrset_name=www.example.com.&rrset_type=A", query)
+}
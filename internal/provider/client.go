@@ -12,6 +12,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	freecache "github.com/coocood/freecache"
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
@@ -27,25 +29,38 @@ var DefaultCacheSize int
 
 // Client is a PowerDNS client representation.
 type Client struct {
-	ServerURL         string // Location of PowerDNS authoritative server to use
-	RecursorServerURL string // Location of PowerDNS recursor server to use
-	ServerVersion     string
-	APIKey            string // REST API Static authentication key
-	APIVersion        int    // API version to use
-	HTTP              *http.Client
-	CacheEnable       bool // Enable/Disable cache for REST API requests
-	Cache             *freecache.Cache
-	CacheTTL          int
+	ServerURL             string // Location of PowerDNS authoritative server to use
+	RecursorServerURL     string // Location of PowerDNS recursor server to use
+	ServerVersion         string // Version reported by the authoritative server, if known
+	RecursorServerVersion string // Version reported by the recursor, if known
+	APIKey                string // REST API Static authentication key
+	APIVersion            int    // API version to use
+	HTTP                  *http.Client
+	CacheEnable           bool // Enable/Disable cache for REST API requests
+	Cache                 *freecache.Cache
+	CacheTTL              int
+	MaxRetries            int           // Maximum number of retries for transient API failures
+	RetryMinWait          time.Duration // Minimum wait between retries
+	RetryMaxWait          time.Duration // Maximum wait between retries
+	RequestTimeout        time.Duration // Timeout of a single HTTP request, 0 for none
+	ServerID              string        // ID of the authoritative server in API paths
+	RecursorServerID      string        // ID of the recursor server in API paths
+	MaxConcurrentRequests int           // Maximum number of requests in flight, 0 for no limit
+	RequestsPerSecond     float64       // Maximum number of requests started per second, 0 for no limit
+	BatchWindow           time.Duration // Window in which rrset changes to a zone are batched, 0 to disable
+
+	zoneFetches  zoneFetchGroup // Zone retrievals in flight, shared by concurrent readers
+	apiVersionMu sync.Mutex     // Guards the detection of APIVersion
+	slots        chan struct{}  // Semaphore bounding requests in flight
+	limiter      *rateLimiter   // Spaces requests out to RequestsPerSecond
+	batcher      *rrSetBatcher  // Batches rrset changes when BatchWindow is set
 }
 
 // NewClient returns a new PowerDNS client.
-func NewClient(ctx context.Context, serverURL string, recursorServerURL string, apiKey string, configTLS *tls.Config, cacheEnable bool, cacheSizeMB string, cacheTTL int) (*Client, error) {
+func NewClient(ctx context.Context, serverURL string, recursorServerURL string, apiKey string, configTLS *tls.Config, cacheEnable bool, cacheSizeMB string, cacheTTL int, opts ...ClientOption) (*Client, error) {
 	// Input validation
-	if serverURL == "" {
-		return nil, fmt.Errorf("serverURL cannot be empty")
-	}
-	if recursorServerURL == "" {
-		return nil, fmt.Errorf("recursorServerURL cannot be empty")
+	if serverURL == "" && recursorServerURL == "" {
+		return nil, fmt.Errorf("at least one of serverURL and recursorServerURL must be set")
 	}
 	if apiKey == "" {
 		return nil, fmt.Errorf("apiKey cannot be empty")
@@ -54,15 +69,20 @@ func NewClient(ctx context.Context, serverURL string, recursorServerURL string,
 		return nil, fmt.Errorf("cacheTTL cannot be negative")
 	}
 
-	// Sanitize URLs
-	cleanURL, err := sanitizeURL(serverURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sanitize server URL: %w", err)
+	// Sanitize URLs, an empty one leaves that server unconfigured
+	var cleanURL, cleanRecursorURL string
+	var err error
+	if serverURL != "" {
+		cleanURL, err = sanitizeURL(serverURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sanitize server URL: %w", err)
+		}
 	}
-
-	cleanRecursorURL, err := sanitizeURL(recursorServerURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sanitize recursor server URL: %w", err)
+	if recursorServerURL != "" {
+		cleanRecursorURL, err = sanitizeURL(recursorServerURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sanitize recursor server URL: %w", err)
+		}
 	}
 
 	// Setup HTTP client
@@ -91,18 +111,77 @@ func NewClient(ctx context.Context, serverURL string, recursorServerURL string,
 		CacheEnable:       cacheEnable,
 		Cache:             cache,
 		CacheTTL:          cacheTTL,
+		ServerID:          defaultServerName,
+		RecursorServerID:  defaultServerName,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if client.MaxRetries < 0 {
+		return nil, fmt.Errorf("maxRetries cannot be negative")
+	}
+	if client.RetryMinWait < 0 || client.RetryMaxWait < 0 {
+		return nil, fmt.Errorf("retry wait times cannot be negative")
+	}
+	if client.RetryMaxWait > 0 && client.RetryMinWait > client.RetryMaxWait {
+		return nil, fmt.Errorf("retryMinWait cannot be greater than retryMaxWait")
+	}
+	if client.RequestTimeout < 0 {
+		return nil, fmt.Errorf("requestTimeout cannot be negative")
+	}
+	if client.MaxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("maxConcurrentRequests cannot be negative")
+	}
+	if client.RequestsPerSecond < 0 {
+		return nil, fmt.Errorf("requestsPerSecond cannot be negative")
+	}
+	if client.MaxConcurrentRequests > 0 {
+		client.slots = make(chan struct{}, client.MaxConcurrentRequests)
+	}
+	if client.RequestsPerSecond > 0 {
+		client.limiter = newRateLimiter(client.RequestsPerSecond)
+	}
+	if client.BatchWindow < 0 {
+		return nil, fmt.Errorf("batchWindow cannot be negative")
+	}
+	if client.BatchWindow > 0 {
+		client.batcher = newRRSetBatcher(client, client.BatchWindow)
+	}
+	if strings.Contains(client.ServerID, "/") || strings.Contains(client.RecursorServerID, "/") {
+		return nil, fmt.Errorf("server IDs cannot contain '/'")
 	}
 
 	// Set server version (optional)
-	if err := client.setServerVersion(ctx); err != nil {
-		tflog.Warn(ctx, "Failed to set server version, continuing without it", map[string]interface{}{
-			"error": err.Error(),
-		})
+	if client.HasServer() {
+		if err := client.setServerVersion(ctx); err != nil {
+			tflog.Warn(ctx, "Failed to set server version, continuing without it", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	if client.HasRecursorServer() {
+		if err := client.setRecursorServerVersion(ctx); err != nil {
+			tflog.Warn(ctx, "Failed to set recursor server version, continuing without it", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
 	}
 
 	return client, nil
 }
 
+// HasServer reports whether the authoritative server is configured.
+func (client *Client) HasServer() bool {
+	return client.ServerURL != ""
+}
+
+// HasRecursorServer reports whether the recursor server is configured.
+func (client *Client) HasRecursorServer() bool {
+	return client.RecursorServerURL != ""
+}
+
 // parseCacheSizeMB parses cache size in MB and returns bytes.
 func parseCacheSizeMB(cacheSizeMB string) (int, error) {
 	size, err := strconv.Atoi(cacheSizeMB)
@@ -166,17 +245,18 @@ func sanitizeURL(URL string) (string, error) {
 
 // Creates a new request with necessary headers.
 func (client *Client) newRequest(ctx context.Context, method string, endpoint string, body []byte) (*http.Request, error) {
-	var err error
-	if client.APIVersion < 0 {
-		client.APIVersion, err = client.detectAPIVersion(ctx)
+	if !client.HasServer() {
+		return nil, ErrServerNotConfigured
 	}
+
+	version, err := client.apiVersion(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var urlStr string
-	if client.APIVersion > 0 {
-		urlStr = client.ServerURL + "/api/v" + strconv.Itoa(client.APIVersion) + endpoint
+	if version > 0 {
+		urlStr = client.ServerURL + "/api/v" + strconv.Itoa(version) + endpoint
 	} else {
 		urlStr = client.ServerURL + endpoint
 	}
@@ -190,7 +270,7 @@ func (client *Client) newRequest(ctx context.Context, method string, endpoint st
 		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest(method, u.String(), bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("error during creation of request: %s", err)
 	}
@@ -207,6 +287,10 @@ func (client *Client) newRequest(ctx context.Context, method string, endpoint st
 
 // Creates a new request for recursor API.
 func (client *Client) newRequestRecursor(ctx context.Context, method string, endpoint string, body []byte) (*http.Request, error) {
+	if !client.HasRecursorServer() {
+		return nil, ErrRecursorServerNotConfigured
+	}
+
 	var urlStr = client.RecursorServerURL + apiVersion + endpoint
 
 	u, err := url.Parse(urlStr)
@@ -219,7 +303,7 @@ func (client *Client) newRequestRecursor(ctx context.Context, method string, end
 		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest(method, u.String(), bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("error during creation of request: %s", err)
 	}
@@ -262,7 +346,11 @@ type ZoneInfo struct {
 	Name               string              `json:"name"`
 	URL                string              `json:"url"`
 	Kind               string              `json:"kind"`
-	DNSSec             bool                `json:"dnsssec"`
+	DNSSec             bool                `json:"dnssec"`
+	Nsec3Param         string              `json:"nsec3param,omitempty"`
+	Nsec3Narrow        bool                `json:"nsec3narrow"`
+	Presigned          bool                `json:"presigned"`
+	APIRectify         *bool               `json:"api_rectify,omitempty"` // Server default when unset
 	Serial             int64               `json:"serial"`
 	Records            []Record            `json:"records,omitempty"`
 	ResourceRecordSets []ResourceRecordSet `json:"rrsets,omitempty"`
@@ -270,14 +358,24 @@ type ZoneInfo struct {
 	Nameservers        []string            `json:"nameservers,omitempty"`
 	Masters            []string            `json:"masters,omitempty"`
 	SoaEditAPI         string              `json:"soa_edit_api"`
+	MasterTSIGKeyIDs   []string            `json:"master_tsig_key_ids,omitempty"`
+	SlaveTSIGKeyIDs    []string            `json:"slave_tsig_key_ids,omitempty"`
 }
 
-// ZoneInfoUpd is a limited subset for supported updates.
+// ZoneInfoUpd is a limited subset for supported updates. Settings left nil
+// are not changed by the update.
 type ZoneInfoUpd struct {
-	Name       string `json:"name"`
-	Kind       string `json:"kind"`
-	SoaEditAPI string `json:"soa_edit_api,omitempty"`
-	Account    string `json:"account"`
+	Name             string    `json:"name"`
+	Kind             string    `json:"kind"`
+	SoaEditAPI       string    `json:"soa_edit_api,omitempty"`
+	Account          string    `json:"account"`
+	DNSSec           *bool     `json:"dnssec,omitempty"`
+	Nsec3Param       *string   `json:"nsec3param,omitempty"`
+	Nsec3Narrow      *bool     `json:"nsec3narrow,omitempty"`
+	Presigned        *bool     `json:"presigned,omitempty"`
+	APIRectify       *bool     `json:"api_rectify,omitempty"`
+	MasterTSIGKeyIDs *[]string `json:"master_tsig_key_ids,omitempty"`
+	SlaveTSIGKeyIDs  *[]string `json:"slave_tsig_key_ids,omitempty"`
 }
 
 // Record represents a PowerDNS record object.
@@ -292,11 +390,73 @@ type Record struct {
 
 // ResourceRecordSet represents a PowerDNS RRSet object.
 type ResourceRecordSet struct {
-	Name       string   `json:"name"`
-	Type       string   `json:"type"`
-	ChangeType string   `json:"changetype"`
-	TTL        int      `json:"ttl"` // For API v1
-	Records    []Record `json:"records,omitempty"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	ChangeType string     `json:"changetype"`
+	TTL        int        `json:"ttl"` // For API v1
+	Records    []Record   `json:"records,omitempty"`
+	Comments   *[]Comment `json:"comments,omitempty"` // Left unchanged by a REPLACE when nil
+}
+
+// Comment represents a comment attached to a PowerDNS RRSet.
+type Comment struct {
+	Content    string `json:"content"`
+	Account    string `json:"account"`
+	ModifiedAt int64  `json:"modified_at,omitempty"` // Set by the server when omitted
+}
+
+// CryptoKey represents a DNSSEC key of a zone.
+type CryptoKey struct {
+	ID         int64    `json:"id,omitempty"`
+	KeyType    string   `json:"keytype"`
+	Active     bool     `json:"active"`
+	Published  *bool    `json:"published,omitempty"`
+	DNSKey     string   `json:"dnskey,omitempty"`
+	DS         []string `json:"ds,omitempty"`
+	PrivateKey string   `json:"privatekey,omitempty"`
+	Algorithm  string   `json:"algorithm,omitempty"`
+	Bits       int64    `json:"bits,omitempty"`
+	Flags      int64    `json:"flags,omitempty"`
+}
+
+// CryptoKeyUpd holds the attributes of a DNSSEC key that can be changed in place.
+type CryptoKeyUpd struct {
+	Active    bool `json:"active"`
+	Published bool `json:"published"`
+}
+
+// TSIGKey represents a PowerDNS TSIG key. The key is omitted when listing keys.
+type TSIGKey struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm"`
+	Key       string `json:"key,omitempty"`
+}
+
+// Autoprimary represents a PowerDNS autoprimary, a primary server allowed to
+// provision zones on this server by sending NOTIFY.
+type Autoprimary struct {
+	IP         string `json:"ip"`
+	Nameserver string `json:"nameserver"`
+	Account    string `json:"account"`
+}
+
+// ZoneMetadata holds the values of one metadata kind of a zone.
+type ZoneMetadata struct {
+	Kind     string   `json:"kind"`
+	Metadata []string `json:"metadata"`
+}
+
+// operationResult is the body PowerDNS sends when an operation on a zone or on
+// the server succeeds.
+type operationResult struct {
+	Result string `json:"result"`
+}
+
+// CacheFlushResult is the outcome of a cache flush.
+type CacheFlushResult struct {
+	Count  int64  `json:"count"`  // Number of entries flushed
+	Result string `json:"result"` // Message of the server
 }
 
 type zonePatchRequest struct {
@@ -304,7 +464,8 @@ type zonePatchRequest struct {
 }
 
 type errorResponse struct {
-	ErrorMsg string `json:"error"`
+	ErrorMsg string   `json:"error"`
+	Errors   []string `json:"errors,omitempty"`
 }
 
 type serverInfo struct {
@@ -325,11 +486,6 @@ const (
 	defaultServerName = "localhost"
 	apiVersion        = "/api/v1"
 
-	// HTTP endpoints.
-	zonesEndpoint         = "/servers/" + defaultServerName + "/zones"
-	serverEndpoint        = "/servers/" + defaultServerName
-	recursorZonesEndpoint = "/servers/" + defaultServerName + "/zones"
-
 	// JSON content types.
 	contentTypeJSON = "application/json"
 
@@ -342,10 +498,128 @@ const (
 	methodOptions = "OPTIONS"
 )
 
-// Sentinel error for "not found" scenarios.
+// WithServerIDs sets the server IDs used in API paths, for setups where the
+// authoritative or recursor server isn't exposed as "localhost".
+func WithServerIDs(serverID, recursorServerID string) ClientOption {
+	return func(client *Client) {
+		if serverID != "" {
+			client.ServerID = serverID
+		}
+		if recursorServerID != "" {
+			client.RecursorServerID = recursorServerID
+		}
+	}
+}
+
+// serverEndpoint returns the path of the authoritative server, e.g. /servers/localhost.
+func (client *Client) serverEndpoint() string {
+	if client.ServerID == "" {
+		return "/servers/" + defaultServerName
+	}
+	return "/servers/" + url.PathEscape(client.ServerID)
+}
+
+// zonesEndpoint returns the path of the zones collection of the authoritative server.
+func (client *Client) zonesEndpoint() string {
+	return client.serverEndpoint() + "/zones"
+}
+
+// zoneEndpoint returns the path of a single zone of the authoritative server.
+func (client *Client) zoneEndpoint(zone string) string {
+	return fmt.Sprintf("%s/%s", client.zonesEndpoint(), zone)
+}
+
+// cryptoKeysEndpoint returns the path of the DNSSEC keys of a zone.
+func (client *Client) cryptoKeysEndpoint(zone string) string {
+	return client.zoneEndpoint(zone) + "/cryptokeys"
+}
+
+// cryptoKeyEndpoint returns the path of a single DNSSEC key of a zone.
+func (client *Client) cryptoKeyEndpoint(zone string, id int64) string {
+	return fmt.Sprintf("%s/%d", client.cryptoKeysEndpoint(zone), id)
+}
+
+// metadataEndpoint returns the path of a single metadata kind of a zone.
+func (client *Client) metadataEndpoint(zone string, kind string) string {
+	return fmt.Sprintf("%s/metadata/%s", client.zoneEndpoint(zone), url.PathEscape(kind))
+}
+
+// zoneOperationEndpoint returns the path of an operation on a zone, such as
+// rectify.
+func (client *Client) zoneOperationEndpoint(zone string, operation string) string {
+	return fmt.Sprintf("%s/%s", client.zoneEndpoint(zone), operation)
+}
+
+// tsigKeysEndpoint returns the path of the TSIG keys of the authoritative
+// server.
+func (client *Client) tsigKeysEndpoint() string {
+	return client.serverEndpoint() + "/tsigkeys"
+}
+
+// tsigKeyEndpoint returns the path of a single TSIG key.
+func (client *Client) tsigKeyEndpoint(id string) string {
+	return fmt.Sprintf("%s/%s", client.tsigKeysEndpoint(), url.PathEscape(id))
+}
+
+// autoprimariesEndpoint returns the path of the autoprimaries of the
+// authoritative server.
+func (client *Client) autoprimariesEndpoint() string {
+	return client.serverEndpoint() + "/autoprimaries"
+}
+
+// autoprimaryEndpoint returns the path of a single autoprimary.
+func (client *Client) autoprimaryEndpoint(ip string, nameserver string) string {
+	return fmt.Sprintf("%s/%s/%s", client.autoprimariesEndpoint(), url.PathEscape(ip), url.PathEscape(nameserver))
+}
+
+// recursorServerEndpoint returns the path of the recursor server.
+func (client *Client) recursorServerEndpoint() string {
+	if client.RecursorServerID == "" {
+		return "/servers/" + defaultServerName
+	}
+	return "/servers/" + url.PathEscape(client.RecursorServerID)
+}
+
+// cacheFlushEndpoint returns the path flushing the cache of the authoritative
+// server for domain and the names below it.
+func (client *Client) cacheFlushEndpoint(domain string) string {
+	return client.serverEndpoint() + "/cache/flush?" + url.Values{"domain": {domain}}.Encode()
+}
+
+// recursorCacheFlushEndpoint returns the path flushing the caches of the
+// recursor server for domain, and the names below it when subtree is set,
+// limited to the records of type tpe unless it is empty.
+func (client *Client) recursorCacheFlushEndpoint(domain string, subtree bool, tpe string) string {
+	query := url.Values{"domain": {domain}, "subtree": {strconv.FormatBool(subtree)}}
+	if tpe != "" {
+		query.Set("type", tpe)
+	}
+	return client.recursorServerEndpoint() + "/cache/flush?" + query.Encode()
+}
+
+// recursorZonesEndpoint returns the path of the zones collection of the recursor server.
+func (client *Client) recursorZonesEndpoint() string {
+	return client.recursorServerEndpoint() + "/zones"
+}
+
+// recursorZoneEndpoint returns the path of a single zone of the recursor server.
+func (client *Client) recursorZoneEndpoint(zone string) string {
+	return fmt.Sprintf("%s/%s", client.recursorZonesEndpoint(), zone)
+}
+
+// Sentinel errors.
 var (
-	// ErrNotFound is returned when a resource is not found.
+	// ErrNotFound matches, through errors.Is, any APIError reporting that the
+	// requested object doesn't exist.
 	ErrNotFound = errors.New("not found")
+
+	// ErrServerNotConfigured is returned by authoritative server calls when
+	// the provider has no server_url.
+	ErrServerNotConfigured = errors.New("the PowerDNS authoritative server is not configured, set server_url")
+
+	// ErrRecursorServerNotConfigured is returned by recursor calls when the
+	// provider has no recursor_server_url.
+	ErrRecursorServerNotConfigured = errors.New("the PowerDNS recursor server is not configured, set recursor_server_url")
 )
 
 // Helper function to close HTTP response body with consistent logging.
@@ -362,27 +636,6 @@ func closeResponseBody(ctx context.Context, resp *http.Response, req *http.Reque
 	}
 }
 
-// Helper function for consistent error handling.
-func handleAPIError(ctx context.Context, resp *http.Response, req *http.Request) error {
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error response: %d, failed to read body: %v", resp.StatusCode, err)
-	}
-
-	var errorResp errorResponse
-	if jsonErr := json.Unmarshal(bodyBytes, &errorResp); jsonErr != nil {
-		return fmt.Errorf("error response: %d, body: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	tflog.Error(ctx, "API error response", map[string]interface{}{
-		"status": resp.StatusCode,
-		"error":  errorResp.ErrorMsg,
-		"body":   string(bodyBytes),
-	})
-
-	return fmt.Errorf("error: %d, reason: %q", resp.StatusCode, errorResp.ErrorMsg)
-}
-
 // ID returns a record with the ID format.
 func (record *Record) ID() string {
 	return record.Name + idSeparator + record.Type
@@ -406,22 +659,26 @@ func parseID(recID string) (string, string, error) {
 // Uses int to represent the API version: 0 is the legacy AKA version 3.4 API
 // Any other integer correlates with the same API version.
 func (client *Client) detectAPIVersion(ctx context.Context) (int, error) {
-	httpClient := client.HTTP
+	if !client.HasServer() {
+		return -1, ErrServerNotConfigured
+	}
 
 	u, err := url.Parse(client.ServerURL + apiVersion + "/servers")
 	if err != nil {
 		return -1, fmt.Errorf("error while trying to detect the API version, request URL: %s", err)
 	}
 
-	req, err := http.NewRequest(methodGet, u.String(), nil)
-	if err != nil {
-		return -1, fmt.Errorf("error during creation of request: %s", err)
-	}
-
-	req.Header.Add("X-API-Key", client.APIKey)
-	req.Header.Add("Accept", contentTypeJSON)
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		req, err = http.NewRequestWithContext(ctx, methodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error during creation of request: %s", err)
+		}
 
-	resp, err := httpClient.Do(req)
+		req.Header.Add("X-API-Key", client.APIKey)
+		req.Header.Add("Accept", contentTypeJSON)
+		return req, nil
+	}, nil)
 	if err != nil {
 		return -1, err
 	}
@@ -438,25 +695,25 @@ func (client *Client) detectAPIVersion(ctx context.Context) (int, error) {
 // ListZones returns all Zones of server, without records.
 func (client *Client) ListZones(ctx context.Context) ([]ZoneInfo, error) {
 	var zoneInfos []ZoneInfo
-	err := client.doRequest(ctx, methodGet, zonesEndpoint, nil, http.StatusOK, &zoneInfos)
+	err := client.doRequest(ctx, methodGet, client.zonesEndpoint(), nil, http.StatusOK, &zoneInfos)
 	return zoneInfos, err
 }
 
 // GetZone gets a zone.
 func (client *Client) GetZone(ctx context.Context, name string) (ZoneInfo, error) {
 	var zoneInfo ZoneInfo
-	err := client.doRequest(ctx, methodGet, fmt.Sprintf("%s/%s", zonesEndpoint, name), nil, http.StatusOK, &zoneInfo)
+	err := client.doRequest(ctx, methodGet, client.zoneEndpoint(name), nil, http.StatusOK, &zoneInfo)
 	return zoneInfo, err
 }
 
 // ZoneExists checks if requested zone exists.
 func (client *Client) ZoneExists(ctx context.Context, name string) (bool, error) {
-	req, err := client.newRequest(ctx, methodGet, fmt.Sprintf("%s/%s", zonesEndpoint, name), nil)
-	if err != nil {
-		return false, err
-	}
-
-	resp, err := client.HTTP.Do(req)
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		var err error
+		req, err = client.newRequest(ctx, methodGet, client.zoneEndpoint(name), nil)
+		return req, err
+	}, nil)
 	if err != nil {
 		return false, err
 	}
@@ -464,7 +721,7 @@ func (client *Client) ZoneExists(ctx context.Context, name string) (bool, error)
 	defer closeResponseBody(ctx, resp, req, &closeErr)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
-		return false, handleAPIError(ctx, resp, req)
+		return false, newAPIError(ctx, resp, req)
 	}
 
 	return resp.StatusCode == http.StatusOK, nil
@@ -477,8 +734,10 @@ func (client *Client) CreateZone(ctx context.Context, zoneInfo ZoneInfo) (ZoneIn
 		return ZoneInfo{}, err
 	}
 
+	defer client.invalidateZone(ctx, zoneInfo.Name)
+
 	var createdZoneInfo ZoneInfo
-	err = client.doRequest(ctx, methodPost, zonesEndpoint, body, http.StatusCreated, &createdZoneInfo)
+	err = client.doRequest(ctx, methodPost, client.zonesEndpoint(), body, http.StatusCreated, &createdZoneInfo)
 	return createdZoneInfo, err
 }
 
@@ -489,97 +748,130 @@ func (client *Client) UpdateZone(ctx context.Context, name string, zoneInfo Zone
 		return err
 	}
 
-	return client.doRequest(ctx, methodPut, fmt.Sprintf("%s/%s", zonesEndpoint, name), body, http.StatusNoContent, nil)
+	defer client.invalidateZone(ctx, name)
+
+	return client.doRequest(ctx, methodPut, client.zoneEndpoint(name), body, http.StatusNoContent, nil)
 }
 
 // DeleteZone deletes a zone.
 func (client *Client) DeleteZone(ctx context.Context, name string) error {
-	return client.doRequest(ctx, methodDelete, fmt.Sprintf("%s/%s", zonesEndpoint, name), nil, http.StatusNoContent, nil)
+	defer client.invalidateZone(ctx, name)
+
+	return client.doRequest(ctx, methodDelete, client.zoneEndpoint(name), nil, http.StatusNoContent, nil)
 }
 
-// GetZoneInfoFromCache return ZoneInfo struct.
-func (client *Client) GetZoneInfoFromCache(ctx context.Context, zone string) (*ZoneInfo, error) {
-	if client.CacheEnable {
-		cacheZoneInfo, err := client.Cache.Get([]byte(zone))
-		if err != nil {
-			return nil, err
-		}
+// ListRecords returns all records in Zone.
+func (client *Client) ListRecords(ctx context.Context, zone string) ([]Record, error) {
+	zoneInfo, err := client.loadZoneInfo(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
 
-		zoneInfo := new(ZoneInfo)
-		if err := json.Unmarshal(cacheZoneInfo, &zoneInfo); err != nil {
-			return nil, err
+	// zoneInfo may be shared with concurrent callers, don't append to it
+	records := append([]Record(nil), zoneInfo.Records...)
+	// Convert the API v1 response to v0 record structure
+	for _, rrs := range zoneInfo.ResourceRecordSets {
+		for _, record := range rrs.Records {
+			records = append(records, Record{
+				Name:     rrs.Name,
+				Type:     rrs.Type,
+				Content:  record.Content,
+				TTL:      rrs.TTL,
+				Disabled: record.Disabled,
+			})
 		}
-
-		return zoneInfo, nil
 	}
 
-	return nil, nil
+	return records, nil
 }
 
-// ListRecords returns all records in Zone.
-func (client *Client) ListRecords(ctx context.Context, zone string) ([]Record, error) {
-	zoneInfo, err := client.GetZoneInfoFromCache(ctx, zone)
+// fetchZoneInfo retrieves a zone with its rrsets from the server, bypassing
+// the cache.
+func (client *Client) fetchZoneInfo(ctx context.Context, zone string) (*ZoneInfo, error) {
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		var err error
+		req, err = client.newRequest(ctx, http.MethodGet, client.zoneEndpoint(zone), nil)
+		return req, err
+	}, nil)
 	if err != nil {
-		tflog.Warn(ctx, "Cache get failed", map[string]interface{}{
-			"zone":  zone,
-			"error": err.Error(),
-		})
 		return nil, err
 	}
-
-	if zoneInfo == nil {
-		req, err := client.newRequest(ctx, http.MethodGet, fmt.Sprintf("/servers/localhost/zones/%s", zone), nil)
-		if err != nil {
-			return nil, err
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			tflog.Warn(ctx, "Error closing response body", map[string]interface{}{
+				"error":  err.Error(),
+				"method": req.Method,
+				"url":    req.URL.String(),
+				"zone":   zone,
+			})
 		}
+	}()
 
-		resp, err := client.HTTP.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer func() {
-			if err := resp.Body.Close(); err != nil {
-				tflog.Warn(ctx, "Error closing response body", map[string]interface{}{
-					"error":  err.Error(),
-					"method": req.Method,
-					"url":    req.URL.String(),
-					"zone":   zone,
-				})
-			}
-		}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(ctx, resp, req)
+	}
 
-		zoneInfo = new(ZoneInfo)
-		if err := json.NewDecoder(resp.Body).Decode(zoneInfo); err != nil {
-			return nil, err
-		}
+	zoneInfo := new(ZoneInfo)
+	if err := json.NewDecoder(resp.Body).Decode(zoneInfo); err != nil {
+		return nil, err
+	}
+	return zoneInfo, nil
+}
 
-		if client.CacheEnable {
-			cacheValue, err := json.Marshal(zoneInfo)
-			if err != nil {
-				return nil, err
-			}
+// rrSetsApplied returns a check reporting whether the live zone already holds
+// every rrset exactly as requested, or no longer holds it for a DELETE.
+func (client *Client) rrSetsApplied(zone string, rrSets []ResourceRecordSet) appliedCheck {
+	return func(ctx context.Context) (bool, error) {
+		var zoneInfo *ZoneInfo
+		var err error
+		if len(rrSets) == 1 {
+			zoneInfo, err = client.fetchRRSet(ctx, zone, rrSets[0].Name, rrSets[0].Type)
+		} else {
+			zoneInfo, err = client.fetchZoneInfo(ctx, zone)
+		}
+		if err != nil {
+			return false, err
+		}
 
-			if err := client.Cache.Set([]byte(zone), cacheValue, client.CacheTTL); err != nil {
-				return nil, fmt.Errorf("the cache for REST API requests is enabled but the size isn't enough: cacheSize: %db \n %s",
-					DefaultCacheSize, err)
+		for _, rrSet := range rrSets {
+			if !rrSetApplied(zoneInfo, rrSet) {
+				return false, nil
 			}
 		}
+		return true, nil
 	}
+}
 
-	records := zoneInfo.Records
-	// Convert the API v1 response to v0 record structure
-	for _, rrs := range zoneInfo.ResourceRecordSets {
-		for _, record := range rrs.Records {
-			records = append(records, Record{
-				Name:    rrs.Name,
-				Type:    rrs.Type,
-				Content: record.Content,
-				TTL:     rrs.TTL,
-			})
+// rrSetApplied reports whether zoneInfo holds rrSet exactly as requested, or
+// no longer holds it for a DELETE.
+func rrSetApplied(zoneInfo *ZoneInfo, rrSet ResourceRecordSet) bool {
+	var live *ResourceRecordSet
+	for i, rrs := range zoneInfo.ResourceRecordSets {
+		if strings.EqualFold(rrs.Name, rrSet.Name) && strings.EqualFold(rrs.Type, rrSet.Type) {
+			live = &zoneInfo.ResourceRecordSets[i]
+			break
 		}
 	}
 
-	return records, nil
+	if rrSet.ChangeType == "DELETE" {
+		return live == nil || len(live.Records) == 0
+	}
+	if live == nil || live.TTL != rrSet.TTL || len(live.Records) != len(rrSet.Records) {
+		return false
+	}
+
+	contents := make(map[string]bool, len(live.Records))
+	for _, record := range live.Records {
+		contents[record.Content] = record.Disabled
+	}
+	for _, record := range rrSet.Records {
+		disabled, ok := contents[record.Content]
+		if !ok || disabled != record.Disabled {
+			return false
+		}
+	}
+	return true
 }
 
 // ListRecordsInRRSet returns only records of specified name and type.
@@ -599,6 +891,22 @@ func (client *Client) ListRecordsInRRSet(ctx context.Context, zone string, name
 	return records, nil
 }
 
+// GetRecordSet returns the rrset of the given name and type in zone, with its
+// comments, or ErrNotFound when the zone has none.
+func (client *Client) GetRecordSet(ctx context.Context, zone string, name string, tpe string) (ResourceRecordSet, error) {
+	zoneInfo, err := client.loadZoneInfo(ctx, zone)
+	if err != nil {
+		return ResourceRecordSet{}, err
+	}
+
+	for _, rrSet := range zoneInfo.ResourceRecordSets {
+		if strings.EqualFold(rrSet.Name, name) && strings.EqualFold(rrSet.Type, tpe) {
+			return rrSet, nil
+		}
+	}
+	return ResourceRecordSet{}, ErrNotFound
+}
+
 // ListRecordsByID returns all records by IDs.
 func (client *Client) ListRecordsByID(ctx context.Context, zone string, recID string) ([]Record, error) {
 	name, tpe, err := parseID(recID)
@@ -636,59 +944,54 @@ func (client *Client) RecordExistsByID(ctx context.Context, zone string, recID s
 func (client *Client) ReplaceRecordSet(ctx context.Context, zone string, rrSet ResourceRecordSet) (string, error) {
 	rrSet.ChangeType = "REPLACE"
 
-	reqBody, _ := json.Marshal(zonePatchRequest{
-		RecordSets: []ResourceRecordSet{rrSet},
-	})
+	if err := client.changeRRSet(ctx, zone, rrSet); err != nil {
+		return "", fmt.Errorf("error creating record set: %s: %w", rrSet.ID(), err)
+	}
+	return rrSet.ID(), nil
+}
 
-	req, err := client.newRequest(ctx, http.MethodPatch, fmt.Sprintf("/servers/localhost/zones/%s", zone), reqBody)
-	if err != nil {
-		return "", err
+// DeleteRecordSet deletes record set from Zone.
+func (client *Client) DeleteRecordSet(ctx context.Context, zone string, name string, tpe string) error {
+	rrSet := ResourceRecordSet{
+		Name:       name,
+		Type:       tpe,
+		ChangeType: "DELETE",
 	}
 
-	resp, err := client.HTTP.Do(req)
-	if err != nil {
-		return "", err
+	if err := client.changeRRSet(ctx, zone, rrSet); err != nil {
+		return fmt.Errorf("error deleting record: %s %s: %w", name, tpe, err)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			tflog.Warn(ctx, "Error closing response body", map[string]interface{}{
-				"error":   err.Error(),
-				"method":  req.Method,
-				"url":     req.URL.String(),
-				"zone":    zone,
-				"rrsetId": rrSet.ID(),
-			})
-		}
-	}()
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		errorResp := new(errorResponse)
-		if err = json.NewDecoder(resp.Body).Decode(errorResp); err != nil {
-			return "", fmt.Errorf("error creating record set: %s", rrSet.ID())
-		}
-		return "", fmt.Errorf("error creating record set: %s, reason: %q", rrSet.ID(), errorResp.ErrorMsg)
+// changeRRSet applies a single rrset change, batched with changes to the same
+// zone when batching is enabled.
+func (client *Client) changeRRSet(ctx context.Context, zone string, rrSet ResourceRecordSet) error {
+	if client.batcher != nil {
+		return client.batcher.submit(ctx, zone, rrSet)
 	}
-	return rrSet.ID(), nil
+	return client.patchRRSets(ctx, zone, []ResourceRecordSet{rrSet})
 }
 
-// DeleteRecordSet deletes record set from Zone.
-func (client *Client) DeleteRecordSet(ctx context.Context, zone string, name string, tpe string) error {
-	reqBody, _ := json.Marshal(zonePatchRequest{
-		RecordSets: []ResourceRecordSet{
-			{
-				Name:       name,
-				Type:       tpe,
-				ChangeType: "DELETE",
-			},
-		},
+// patchRRSets sends rrset changes to a zone in a single PATCH. PowerDNS
+// applies them atomically: either all of them or none.
+func (client *Client) patchRRSets(ctx context.Context, zone string, rrSets []ResourceRecordSet) error {
+	reqBody, err := json.Marshal(zonePatchRequest{
+		RecordSets: rrSets,
 	})
-
-	req, err := client.newRequest(ctx, http.MethodPatch, fmt.Sprintf("/servers/localhost/zones/%s", zone), reqBody)
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.HTTP.Do(req)
+	// Invalidate even on failure, the change may have been partially applied
+	defer client.invalidateZone(ctx, zone)
+
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		var err error
+		req, err = client.newRequest(ctx, http.MethodPatch, client.zoneEndpoint(zone), reqBody)
+		return req, err
+	}, client.rrSetsApplied(zone, rrSets))
 	if err != nil {
 		return err
 	}
@@ -699,18 +1002,13 @@ func (client *Client) DeleteRecordSet(ctx context.Context, zone string, name str
 				"method": req.Method,
 				"url":    req.URL.String(),
 				"zone":   zone,
-				"name":   name,
-				"type":   tpe,
+				"rrsets": len(rrSets),
 			})
 		}
 	}()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		errorResp := new(errorResponse)
-		if err = json.NewDecoder(resp.Body).Decode(errorResp); err != nil {
-			return fmt.Errorf("error deleting record: %s %s", name, tpe)
-		}
-		return fmt.Errorf("error deleting record: %s %s, reason: %q", name, tpe, errorResp.ErrorMsg)
+		return newAPIError(ctx, resp, req)
 	}
 	return nil
 }
@@ -725,12 +1023,12 @@ func (client *Client) DeleteRecordSetByID(ctx context.Context, zone string, recI
 }
 
 func (client *Client) setServerVersion(ctx context.Context) error {
-	req, err := client.newRequest(ctx, http.MethodGet, "/servers/localhost", nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := client.HTTP.Do(req)
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		var err error
+		req, err = client.newRequest(ctx, http.MethodGet, client.serverEndpoint(), nil)
+		return req, err
+	}, nil)
 	if err != nil {
 		return err
 	}
@@ -745,13 +1043,7 @@ func (client *Client) setServerVersion(ctx context.Context) error {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("invalid response code from server: '%d'. Failed to read response body: %v",
-				resp.StatusCode, err)
-		}
-		return fmt.Errorf("failed to set server version: invalid response code from server: '%d'. Response body: %s",
-			resp.StatusCode, string(bodyBytes))
+		return fmt.Errorf("failed to set server version: %w", newAPIError(ctx, resp, req))
 	}
 
 	serverInfo := new(serverInfo)
@@ -769,17 +1061,279 @@ func (client *Client) setServerVersion(ctx context.Context) error {
 	return fmt.Errorf("unable to get server version")
 }
 
+// setRecursorServerVersion detects the version of the recursor.
+func (client *Client) setRecursorServerVersion(ctx context.Context) error {
+	var info serverInfo
+	if err := client.doRequestRecursor(ctx, methodGet, client.recursorServerEndpoint(), nil, http.StatusOK, &info); err != nil {
+		return fmt.Errorf("failed to set recursor server version: %w", err)
+	}
+	if info.Version == "" {
+		return fmt.Errorf("unable to get recursor server version")
+	}
+	client.RecursorServerVersion = info.Version
+	return nil
+}
+
+// ListCryptoKeys returns the DNSSEC keys of a zone, without their private keys.
+func (client *Client) ListCryptoKeys(ctx context.Context, zone string) ([]CryptoKey, error) {
+	var keys []CryptoKey
+	err := client.doRequest(ctx, methodGet, client.cryptoKeysEndpoint(zone), nil, http.StatusOK, &keys)
+	return keys, err
+}
+
+// GetCryptoKey returns a DNSSEC key of a zone.
+func (client *Client) GetCryptoKey(ctx context.Context, zone string, id int64) (CryptoKey, error) {
+	var key CryptoKey
+	err := client.doRequest(ctx, methodGet, client.cryptoKeyEndpoint(zone, id), nil, http.StatusOK, &key)
+	return key, err
+}
+
+// CreateCryptoKey generates a DNSSEC key for a zone, or imports key.PrivateKey
+// when set.
+func (client *Client) CreateCryptoKey(ctx context.Context, zone string, key CryptoKey) (CryptoKey, error) {
+	body, err := json.Marshal(key)
+	if err != nil {
+		return CryptoKey{}, err
+	}
+
+	// Securing a zone changes its dnssec flag
+	defer client.invalidateZone(ctx, zone)
+
+	var createdKey CryptoKey
+	err = client.doRequest(ctx, methodPost, client.cryptoKeysEndpoint(zone), body, http.StatusCreated, &createdKey)
+	return createdKey, err
+}
+
+// UpdateCryptoKey activates or publishes a DNSSEC key, or the reverse.
+func (client *Client) UpdateCryptoKey(ctx context.Context, zone string, id int64, key CryptoKeyUpd) error {
+	body, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	return client.doRequest(ctx, methodPut, client.cryptoKeyEndpoint(zone, id), body, http.StatusNoContent, nil)
+}
+
+// DeleteCryptoKey deletes a DNSSEC key of a zone.
+func (client *Client) DeleteCryptoKey(ctx context.Context, zone string, id int64) error {
+	defer client.invalidateZone(ctx, zone)
+
+	return client.doRequest(ctx, methodDelete, client.cryptoKeyEndpoint(zone, id), nil, http.StatusNoContent, nil)
+}
+
+// GetZoneMetadata returns the values of a metadata kind of a zone. Kinds
+// without values are returned empty rather than not found.
+func (client *Client) GetZoneMetadata(ctx context.Context, zone string, kind string) (ZoneMetadata, error) {
+	var metadata ZoneMetadata
+	err := client.doRequest(ctx, methodGet, client.metadataEndpoint(zone, kind), nil, http.StatusOK, &metadata)
+	return metadata, err
+}
+
+// SetZoneMetadata replaces the values of a metadata kind of a zone.
+func (client *Client) SetZoneMetadata(ctx context.Context, zone string, metadata ZoneMetadata) (ZoneMetadata, error) {
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		return ZoneMetadata{}, err
+	}
+
+	var updated ZoneMetadata
+	err = client.doRequest(ctx, methodPut, client.metadataEndpoint(zone, metadata.Kind), body, http.StatusOK, &updated)
+	return updated, err
+}
+
+// DeleteZoneMetadata removes all values of a metadata kind of a zone.
+func (client *Client) DeleteZoneMetadata(ctx context.Context, zone string, kind string) error {
+	return client.doRequest(ctx, methodDelete, client.metadataEndpoint(zone, kind), nil, http.StatusNoContent, nil)
+}
+
+// RectifyZone rectifies a zone, computing the ordering and the authoritative
+// flags of its records DNSSEC relies on, and returns the result message of the
+// server.
+func (client *Client) RectifyZone(ctx context.Context, zone string) (string, error) {
+	var result operationResult
+	err := client.doRequest(ctx, methodPut, client.zoneOperationEndpoint(zone, "rectify"), nil, http.StatusOK, &result)
+	return result.Result, err
+}
+
+// NotifyZone queues a NOTIFY of a zone to its secondaries and returns the
+// result message of the server.
+func (client *Client) NotifyZone(ctx context.Context, zone string) (string, error) {
+	var result operationResult
+	err := client.doRequest(ctx, methodPut, client.zoneOperationEndpoint(zone, "notify"), nil, http.StatusOK, &result)
+	return result.Result, err
+}
+
+// RetrieveZone queues a transfer of a secondary zone from its primary and
+// returns the result message of the server. The transfer completes
+// asynchronously, see WaitForZoneSerial.
+func (client *Client) RetrieveZone(ctx context.Context, zone string) (string, error) {
+	var result operationResult
+	err := client.doRequest(ctx, methodPut, client.zoneOperationEndpoint(zone, "axfr-retrieve"), nil, http.StatusOK, &result)
+	if err == nil {
+		// The transfer replaces the rrsets of the zone
+		client.invalidateZone(ctx, zone)
+	}
+	return result.Result, err
+}
+
+// ZoneSerial returns the serial of a zone. Only its SOA is fetched where the
+// server can filter rrsets.
+func (client *Client) ZoneSerial(ctx context.Context, zone string) (int64, error) {
+	zoneInfo, err := client.fetchRRSet(ctx, zone, strings.TrimSuffix(zone, ".")+".", "SOA")
+	if err != nil {
+		return 0, err
+	}
+	return zoneInfo.Serial, nil
+}
+
+// WaitForZoneSerial polls a zone every interval until its serial advances
+// past serial, and returns the new serial. It gives up when ctx is done.
+func (client *Client) WaitForZoneSerial(ctx context.Context, zone string, serial int64, interval time.Duration) (int64, error) {
+	for {
+		current, err := client.ZoneSerial(ctx, zone)
+		if err != nil {
+			return 0, err
+		}
+		if serialAfter(current, serial) {
+			// The zone may have been cached before the transfer completed
+			client.invalidateZone(ctx, zone)
+			return current, nil
+		}
+		tflog.Debug(ctx, "Waiting for the serial of the PowerDNS zone to advance", map[string]any{"serial": current})
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, fmt.Errorf("serial of zone %s still %d: %w", zone, current, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// serialAfter reports whether serial a comes after serial b, serials wrapping
+// around as described by RFC 1982. Any other serial comes after 0, the serial
+// of secondary zones never transferred.
+func serialAfter(a, b int64) bool {
+	if b == 0 {
+		return a != 0
+	}
+	return int32(uint32(a)-uint32(b)) > 0
+}
+
+// ListTSIGKeys returns the TSIG keys of the server, without their secrets.
+func (client *Client) ListTSIGKeys(ctx context.Context) ([]TSIGKey, error) {
+	var keys []TSIGKey
+	err := client.doRequest(ctx, methodGet, client.tsigKeysEndpoint(), nil, http.StatusOK, &keys)
+	return keys, err
+}
+
+// GetTSIGKey returns a TSIG key with its secret.
+func (client *Client) GetTSIGKey(ctx context.Context, id string) (TSIGKey, error) {
+	var key TSIGKey
+	err := client.doRequest(ctx, methodGet, client.tsigKeyEndpoint(id), nil, http.StatusOK, &key)
+	return key, err
+}
+
+// FindTSIGKey returns the TSIG key of the given name with its secret, or
+// ErrNotFound when the server has none.
+func (client *Client) FindTSIGKey(ctx context.Context, name string) (TSIGKey, error) {
+	keys, err := client.ListTSIGKeys(ctx)
+	if err != nil {
+		return TSIGKey{}, err
+	}
+	for _, key := range keys {
+		if sameDNSName(key.Name, name) {
+			return client.GetTSIGKey(ctx, key.ID)
+		}
+	}
+	return TSIGKey{}, ErrNotFound
+}
+
+// sameDNSName reports whether a and b are the same DNS name, ignoring case
+// and the trailing dot.
+func sameDNSName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// CreateTSIGKey creates a TSIG key. PowerDNS generates the secret when
+// key.Key is empty.
+func (client *Client) CreateTSIGKey(ctx context.Context, key TSIGKey) (TSIGKey, error) {
+	body, err := json.Marshal(key)
+	if err != nil {
+		return TSIGKey{}, err
+	}
+
+	var createdKey TSIGKey
+	err = client.doRequest(ctx, methodPost, client.tsigKeysEndpoint(), body, http.StatusCreated, &createdKey)
+	return createdKey, err
+}
+
+// UpdateTSIGKey changes the algorithm or the secret of a TSIG key. An empty
+// key.Key keeps the current secret.
+func (client *Client) UpdateTSIGKey(ctx context.Context, id string, key TSIGKey) (TSIGKey, error) {
+	body, err := json.Marshal(key)
+	if err != nil {
+		return TSIGKey{}, err
+	}
+
+	var updatedKey TSIGKey
+	err = client.doRequest(ctx, methodPut, client.tsigKeyEndpoint(id), body, http.StatusOK, &updatedKey)
+	return updatedKey, err
+}
+
+// DeleteTSIGKey deletes a TSIG key.
+func (client *Client) DeleteTSIGKey(ctx context.Context, id string) error {
+	return client.doRequest(ctx, methodDelete, client.tsigKeyEndpoint(id), nil, http.StatusNoContent, nil)
+}
+
+// ListAutoprimaries returns the autoprimaries of the server.
+func (client *Client) ListAutoprimaries(ctx context.Context) ([]Autoprimary, error) {
+	var autoprimaries []Autoprimary
+	err := client.doRequest(ctx, methodGet, client.autoprimariesEndpoint(), nil, http.StatusOK, &autoprimaries)
+	return autoprimaries, err
+}
+
+// GetAutoprimary returns the autoprimary of the given IP and nameserver, or
+// ErrNotFound when the server has none. PowerDNS can only list them.
+func (client *Client) GetAutoprimary(ctx context.Context, ip string, nameserver string) (Autoprimary, error) {
+	autoprimaries, err := client.ListAutoprimaries(ctx)
+	if err != nil {
+		return Autoprimary{}, err
+	}
+	for _, autoprimary := range autoprimaries {
+		if sameIP(autoprimary.IP, ip) && sameDNSName(autoprimary.Nameserver, nameserver) {
+			return autoprimary, nil
+		}
+	}
+	return Autoprimary{}, ErrNotFound
+}
+
+// CreateAutoprimary adds an autoprimary.
+func (client *Client) CreateAutoprimary(ctx context.Context, autoprimary Autoprimary) error {
+	body, err := json.Marshal(autoprimary)
+	if err != nil {
+		return err
+	}
+	return client.doRequest(ctx, methodPost, client.autoprimariesEndpoint(), body, http.StatusCreated, nil)
+}
+
+// DeleteAutoprimary removes the autoprimary of the given IP and nameserver.
+func (client *Client) DeleteAutoprimary(ctx context.Context, ip string, nameserver string) error {
+	return client.doRequest(ctx, methodDelete, client.autoprimaryEndpoint(ip, nameserver), nil, http.StatusNoContent, nil)
+}
+
 // ListRecursorZones returns all zones of the recursor server.
 func (client *Client) ListRecursorZones(ctx context.Context) ([]RecursorZone, error) {
 	var zones []RecursorZone
-	err := client.doRequestRecursor(ctx, methodGet, "/servers/localhost/zones", nil, http.StatusOK, &zones)
+	err := client.doRequestRecursor(ctx, methodGet, client.recursorZonesEndpoint(), nil, http.StatusOK, &zones)
 	return zones, err
 }
 
 // GetRecursorZone gets a specific zone.
 func (client *Client) GetRecursorZone(ctx context.Context, zoneName string) (RecursorZone, error) {
 	var zone RecursorZone
-	err := client.doRequestRecursor(ctx, methodGet, fmt.Sprintf("/servers/localhost/zones/%s", zoneName), nil, http.StatusOK, &zone)
+	err := client.doRequestRecursor(ctx, methodGet, client.recursorZoneEndpoint(zoneName), nil, http.StatusOK, &zone)
 	return zone, err
 }
 
@@ -799,7 +1353,7 @@ func (client *Client) CreateRecursorZone(ctx context.Context, zone RecursorZone)
 	}
 
 	var createdZone RecursorZone
-	err = client.doRequestRecursor(ctx, methodPost, "/servers/localhost/zones", body, http.StatusCreated, &createdZone)
+	err = client.doRequestRecursor(ctx, methodPost, client.recursorZonesEndpoint(), body, http.StatusCreated, &createdZone)
 	return createdZone, err
 }
 
@@ -819,23 +1373,40 @@ func (client *Client) UpdateRecursorZone(ctx context.Context, zoneName string, z
 	}
 
 	var updatedZone RecursorZone
-	err = client.doRequestRecursor(ctx, methodPatch, fmt.Sprintf("/servers/localhost/zones/%s", zoneName), body, http.StatusOK, &updatedZone)
+	err = client.doRequestRecursor(ctx, methodPatch, client.recursorZoneEndpoint(zoneName), body, http.StatusOK, &updatedZone)
 	return updatedZone, err
 }
 
 // DeleteRecursorZone deletes a zone.
 func (client *Client) DeleteRecursorZone(ctx context.Context, zoneName string) error {
-	return client.doRequestRecursor(ctx, methodDelete, fmt.Sprintf("/servers/localhost/zones/%s", zoneName), nil, http.StatusNoContent, nil)
+	return client.doRequestRecursor(ctx, methodDelete, client.recursorZoneEndpoint(zoneName), nil, http.StatusNoContent, nil)
+}
+
+// FlushCache flushes the packet cache of the authoritative server for domain
+// and the names below it.
+func (client *Client) FlushCache(ctx context.Context, domain string) (CacheFlushResult, error) {
+	var result CacheFlushResult
+	err := client.doRequest(ctx, methodPut, client.cacheFlushEndpoint(domain), nil, http.StatusOK, &result)
+	return result, err
+}
+
+// FlushRecursorCache flushes the record, negative and packet caches of the
+// recursor server for domain, and the names below it when subtree is set,
+// limited to the records of type tpe unless it is empty.
+func (client *Client) FlushRecursorCache(ctx context.Context, domain string, subtree bool, tpe string) (CacheFlushResult, error) {
+	var result CacheFlushResult
+	err := client.doRequestRecursor(ctx, methodPut, client.recursorCacheFlushEndpoint(domain, subtree, tpe), nil, http.StatusOK, &result)
+	return result, err
 }
 
 // doRequest performs a generic HTTP request with common error handling.
 func (client *Client) doRequest(ctx context.Context, method, endpoint string, body []byte, successStatus int, response interface{}) error {
-	req, err := client.newRequest(ctx, method, endpoint, body)
-	if err != nil {
-		return err
-	}
-
-	resp, err := client.HTTP.Do(req)
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		var err error
+		req, err = client.newRequest(ctx, method, endpoint, body)
+		return req, err
+	}, nil)
 	if err != nil {
 		return err
 	}
@@ -850,11 +1421,7 @@ func (client *Client) doRequest(ctx context.Context, method, endpoint string, bo
 	}()
 
 	if resp.StatusCode != successStatus {
-		errorResp := new(errorResponse)
-		if err = json.NewDecoder(resp.Body).Decode(errorResp); err != nil {
-			return fmt.Errorf("error response: %d", resp.StatusCode)
-		}
-		return fmt.Errorf("error: %d, reason: %q", resp.StatusCode, errorResp.ErrorMsg)
+		return newAPIError(ctx, resp, req)
 	}
 
 	if response != nil {
@@ -868,24 +1435,29 @@ func (client *Client) doRequest(ctx context.Context, method, endpoint string, bo
 
 // doRequestRecursor performs a generic HTTP request to recursor API with common error handling.
 func (client *Client) doRequestRecursor(ctx context.Context, method, endpoint string, body []byte, successStatus int, response interface{}) error {
-	req, err := client.newRequestRecursor(ctx, method, endpoint, body)
-	if err != nil {
-		return err
-	}
-
-	tflog.Debug(ctx, "Making recursor API request", map[string]interface{}{
-		"method":   method,
-		"endpoint": endpoint,
-		"url":      req.URL.String(),
-		"body":     string(body),
-	})
+	var req *http.Request
+	resp, err := client.do(ctx, func() (*http.Request, error) {
+		var err error
+		req, err = client.newRequestRecursor(ctx, method, endpoint, body)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := client.HTTP.Do(req)
-	if err != nil {
-		tflog.Error(ctx, "HTTP request failed", map[string]interface{}{
-			"error": err.Error(),
-			"url":   req.URL.String(),
+		tflog.Debug(ctx, "Making recursor API request", map[string]interface{}{
+			"method":   method,
+			"endpoint": endpoint,
+			"url":      req.URL.String(),
+			"body":     string(body),
 		})
+		return req, nil
+	}, nil)
+	if err != nil {
+		if req != nil {
+			tflog.Error(ctx, "HTTP request failed", map[string]interface{}{
+				"error": err.Error(),
+				"url":   req.URL.String(),
+			})
+		}
 		return err
 	}
 	defer func() {
@@ -904,23 +1476,7 @@ func (client *Client) doRequestRecursor(ctx context.Context, method, endpoint st
 	})
 
 	if resp.StatusCode != successStatus {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("error response: %d, failed to read body: %v", resp.StatusCode, err)
-		}
-
-		var errorResp errorResponse
-		if jsonErr := json.Unmarshal(bodyBytes, &errorResp); jsonErr != nil {
-			return fmt.Errorf("error response: %d, body: %s", resp.StatusCode, string(bodyBytes))
-		}
-
-		tflog.Error(ctx, "API error response", map[string]interface{}{
-			"status": resp.StatusCode,
-			"error":  errorResp.ErrorMsg,
-			"body":   string(bodyBytes),
-		})
-
-		return fmt.Errorf("error: %d, reason: %q", resp.StatusCode, errorResp.ErrorMsg)
+		return newAPIError(ctx, resp, req)
 	}
 
 	if response != nil {
@@ -2,8 +2,13 @@ package provider
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,20 +38,26 @@ func TestClient_New(t *testing.T) {
 			expectError:       false,
 		},
 		{
-			name:              "empty serverURL",
+			name:              "recursor only",
 			serverURL:         "",
 			recursorServerURL: "https://recursor.example.com",
 			apiKey:            "test-key",
-			expectError:       true,
-			expectedErrorMsg:  "serverURL cannot be empty",
+			expectError:       false,
 		},
 		{
-			name:              "empty recursorServerURL",
-			serverURL:         "https://example.com",
+			name:              "authoritative only",
+			serverURL:         "http://localhost:8081",
+			recursorServerURL: "",
+			apiKey:            "test-key",
+			expectError:       false,
+		},
+		{
+			name:              "no server",
+			serverURL:         "",
 			recursorServerURL: "",
 			apiKey:            "test-key",
 			expectError:       true,
-			expectedErrorMsg:  "recursorServerURL cannot be empty",
+			expectedErrorMsg:  "at least one of serverURL and recursorServerURL must be set",
 		},
 		{
 			name:              "empty apiKey",
@@ -85,6 +96,86 @@ func TestClient_New(t *testing.T) {
 	}
 }
 
+func TestClient_ServerIDs(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	recursor := httptest.NewServer(handler)
+	defer recursor.Close()
+
+	client, err := NewClient(context.Background(), server.URL, recursor.URL, "secret", nil, false, "", 0,
+		WithRetryPolicy(0, 0, 0), WithServerIDs("pdns-1", "rec-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "pdns-1", client.ServerID)
+	assert.Equal(t, "rec-1", client.RecursorServerID)
+
+	mu.Lock()
+	paths = nil
+	mu.Unlock()
+	_, err = client.ListZones(context.Background())
+	require.NoError(t, err)
+	_, err = client.ListRecursorZones(context.Background())
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Equal(t, []string{"/api/v1/servers/pdns-1/zones", "/api/v1/servers/rec-1/zones"}, paths)
+	mu.Unlock()
+
+	_, err = NewClient(context.Background(), server.URL, recursor.URL, "secret", nil, false, "", 0,
+		WithRetryPolicy(0, 0, 0), WithServerIDs("a/b", ""))
+	require.Error(t, err)
+}
+
+func TestClient_TSIGKeyEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	var uris []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		uris = append(uris, r.RequestURI)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), server.URL, "", "secret", nil, false, "", 0,
+		WithRetryPolicy(0, 0, 0))
+	require.NoError(t, err)
+
+	mu.Lock()
+	uris = nil
+	mu.Unlock()
+	_, err = client.GetTSIGKey(context.Background(), "key/1?x#y")
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Equal(t, []string{"/api/v1/servers/localhost/tsigkeys/key%2F1%3Fx%23y"}, uris)
+	mu.Unlock()
+}
+
+func TestClient_UnconfiguredServer(t *testing.T) {
+	ctx := context.Background()
+
+	recursorOnly, err := NewClient(ctx, "", "https://recursor.example.com", "secret", nil, false, "", 0)
+	require.NoError(t, err)
+	assert.False(t, recursorOnly.HasServer())
+	assert.True(t, recursorOnly.HasRecursorServer())
+	_, err = recursorOnly.ListZones(ctx)
+	assert.ErrorIs(t, err, ErrServerNotConfigured)
+
+	authOnly := &Client{ServerURL: "https://pdns.example.com", APIVersion: 1}
+	assert.True(t, authOnly.HasServer())
+	assert.False(t, authOnly.HasRecursorServer())
+	_, err = authOnly.ListRecursorZones(ctx)
+	assert.ErrorIs(t, err, ErrRecursorServerNotConfigured)
+}
+
 func TestClient_ParseCacheSizeMB(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -238,3 +329,102 @@ func TestClient_ParseID(t *testing.T) {
 		})
 	}
 }
+
+func newFakeServerClient(t *testing.T, opts ...ClientOption) (*Client, *pdnstest.Server) {
+	server := pdnstest.New(t)
+	opts = append([]ClientOption{WithRetryPolicy(2, time.Millisecond, 5*time.Millisecond)}, opts...)
+	client, err := NewClient(context.Background(), server.URL, server.RecursorURL, server.APIKey(), nil, false, "", 0, opts...)
+	require.NoError(t, err)
+	return client, server
+}
+
+func TestClient_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	assert.Equal(t, pdnstest.DefaultVersion, client.ServerVersion)
+	assert.Equal(t, pdnstest.DefaultRecursorVersion, client.RecursorServerVersion)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	id, err := client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}},
+	})
+	require.NoError(t, err)
+	records, err := client.ListRecordsByID(ctx, "example.com.", id)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "192.0.2.1", records[0].Content)
+
+	// Transient failures are retried
+	server.Inject(pdnstest.Fault{Method: http.MethodPatch, Status: http.StatusServiceUnavailable, Times: 1})
+	require.NoError(t, client.DeleteRecordSetByID(ctx, "example.com.", id))
+	_, exists := server.RRSet("example.com.", "www.example.com.", "A")
+	assert.False(t, exists)
+
+	_, err = client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.org.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}},
+	})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+
+	require.NoError(t, client.DeleteZone(ctx, "example.com."))
+	_, err = client.GetZone(ctx, "example.com.")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_FakeServerNoRecordEditing(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	server.RejectRecordEdits()
+	err = client.DeleteRecordSet(ctx, "example.com.", "www.example.com.", "A")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Hosting backend does not support editing records")
+}
+
+func TestClient_FakeServerZoneDNSSEC(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeServerClient(t)
+
+	apiRectify := true
+	created, err := client.CreateZone(ctx, ZoneInfo{
+		Name:        "example.com.",
+		Kind:        "Native",
+		Nameservers: []string{"ns1.example.com."},
+		DNSSec:      true,
+		Nsec3Param:  "1 0 0 -",
+		APIRectify:  &apiRectify,
+	})
+	require.NoError(t, err)
+	assert.True(t, created.DNSSec)
+	assert.Equal(t, "1 0 0 -", created.Nsec3Param)
+
+	// Settings left out of the update are kept
+	nsec := ""
+	presigned := true
+	require.NoError(t, client.UpdateZone(ctx, "example.com.", ZoneInfoUpd{Name: "example.com.", Kind: "Native", Nsec3Param: &nsec, Presigned: &presigned}))
+	zone, err := client.GetZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.True(t, zone.DNSSec)
+	assert.Empty(t, zone.Nsec3Param)
+	assert.True(t, zone.Presigned)
+	require.NotNil(t, zone.APIRectify)
+	assert.True(t, *zone.APIRectify)
+
+	disabled := false
+	require.NoError(t, client.UpdateZone(ctx, "example.com.", ZoneInfoUpd{Name: "example.com.", Kind: "Native", DNSSec: &disabled}))
+	zone, err = client.GetZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.False(t, zone.DNSSec)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -21,6 +22,15 @@ type Config struct {
 	CacheEnable       bool
 	CacheMemorySize   string
 	CacheTTL          int
+	MaxRetries        int
+	RetryMinWait      time.Duration
+	RetryMaxWait      time.Duration
+	RequestTimeout    time.Duration
+	ServerID          string
+	RecursorServerID  string
+	MaxConcurrent     int
+	RequestsPerSecond float64
+	RRSetBatchWindow  time.Duration
 }
 
 // Client returns a new client for accessing PowerDNS.
@@ -67,6 +77,11 @@ func (c *Config) Client(ctx context.Context) (*Client, error) {
 		c.CacheEnable,
 		c.CacheMemorySize,
 		c.CacheTTL,
+		WithRetryPolicy(c.MaxRetries, c.RetryMinWait, c.RetryMaxWait),
+		WithRequestTimeout(c.RequestTimeout),
+		WithServerIDs(c.ServerID, c.RecursorServerID),
+		WithConcurrencyLimit(c.MaxConcurrent, c.RequestsPerSecond),
+		WithRRSetBatching(c.RRSetBatchWindow),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up PowerDNS client: %s", err)
@@ -75,8 +90,12 @@ func (c *Config) Client(ctx context.Context) (*Client, error) {
 	// Attach some persistent fields for follow-up logs if callers reuse ctx
 	ctx = tflog.SetField(ctx, "server_url", c.ServerURL)
 	ctx = tflog.SetField(ctx, "recursor_server_url", c.RecursorServerURL)
+	ctx = tflog.SetField(ctx, "server_id", client.ServerID)
+	ctx = tflog.SetField(ctx, "recursor_server_id", client.RecursorServerID)
 	ctx = tflog.SetField(ctx, "cache_enabled", c.CacheEnable)
 	ctx = tflog.SetField(ctx, "cache_ttl_sec", c.CacheTTL)
+	ctx = tflog.SetField(ctx, "max_retries", c.MaxRetries)
+	ctx = tflog.SetField(ctx, "max_concurrent_requests", c.MaxConcurrent)
 
 	tflog.Info(ctx, "PowerDNS client configured")
 
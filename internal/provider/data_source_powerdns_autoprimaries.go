@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ datasource.DataSource = &AutoprimariesDataSource{}
+
+// AutoprimariesDataSource defines the data source implementation.
+type AutoprimariesDataSource struct {
+	client *Client
+}
+
+// AutoprimariesDataSourceModel describes the data source data model.
+type AutoprimariesDataSourceModel struct {
+	ID            types.String       `tfsdk:"id"`
+	Account       types.String       `tfsdk:"account"`
+	Autoprimaries []AutoprimaryModel `tfsdk:"autoprimaries"`
+}
+
+// AutoprimaryModel describes a single autoprimary of the data source.
+type AutoprimaryModel struct {
+	IP         types.String `tfsdk:"ip"`
+	Nameserver types.String `tfsdk:"nameserver"`
+	Account    types.String `tfsdk:"account"`
+}
+
+func (d *AutoprimariesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_autoprimaries"
+}
+
+func (d *AutoprimariesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the autoprimaries of the authoritative server.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier",
+			},
+			"account": schema.StringAttribute{
+				MarkdownDescription: "Only list the autoprimaries of this account",
+				Optional:            true,
+			},
+			"autoprimaries": schema.ListNestedAttribute{
+				MarkdownDescription: "The autoprimaries of the server, ordered by IP address and nameserver",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ip": schema.StringAttribute{
+							MarkdownDescription: "The IP address of the primary server",
+							Computed:            true,
+						},
+						"nameserver": schema.StringAttribute{
+							MarkdownDescription: "The nameserver of the primary server",
+							Computed:            true,
+						},
+						"account": schema.StringAttribute{
+							MarkdownDescription: "The account owning the zones provisioned by the primary server",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AutoprimariesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type", "Expected *Client")
+		return
+	}
+	d.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_autoprimaries")
+}
+
+func (d *AutoprimariesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AutoprimariesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Reading autoprimaries data source")
+
+	autoprimaries, err := d.client.ListAutoprimaries(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't fetch autoprimaries", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue("autoprimaries")
+	if !data.Account.IsNull() {
+		data.ID = types.StringValue("autoprimaries/" + data.Account.ValueString())
+	}
+	data.Autoprimaries = autoprimaryModels(autoprimaries, data.Account)
+
+	tflog.Info(ctx, "Successfully retrieved autoprimaries", map[string]interface{}{
+		"autoprimary_count": len(data.Autoprimaries),
+	})
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// autoprimaryModels returns the autoprimaries of account, or all of them when
+// account is null, ordered by IP address and nameserver.
+func autoprimaryModels(autoprimaries []Autoprimary, account types.String) []AutoprimaryModel {
+	sort.SliceStable(autoprimaries, func(i, j int) bool {
+		if autoprimaries[i].IP != autoprimaries[j].IP {
+			return autoprimaries[i].IP < autoprimaries[j].IP
+		}
+		return autoprimaries[i].Nameserver < autoprimaries[j].Nameserver
+	})
+
+	models := []AutoprimaryModel{}
+	for _, autoprimary := range autoprimaries {
+		if !account.IsNull() && autoprimary.Account != account.ValueString() {
+			continue
+		}
+		models = append(models, AutoprimaryModel{
+			IP:         types.StringValue(autoprimary.IP),
+			Nameserver: types.StringValue(autoprimary.Nameserver),
+			Account:    types.StringValue(autoprimary.Account),
+		})
+	}
+	return models
+}
+
+func NewAutoprimariesDataSource() datasource.DataSource {
+	return &AutoprimariesDataSource{}
+}
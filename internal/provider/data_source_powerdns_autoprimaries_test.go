@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourcePDNSAutoprimaries_basic(t *testing.T) {
+	dataSourceName := "data.powerdns_autoprimaries.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourcePDNSAutoprimariesConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "autoprimaries.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "autoprimaries.0.ip", "192.0.2.54"),
+					resource.TestCheckResourceAttr(dataSourceName, "autoprimaries.0.nameserver", "ns2.primary.sysa.abc."),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourcePDNSAutoprimariesConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_autoprimary" "test" {
+	ip         = "192.0.2.54"
+	nameserver = "ns2.primary.sysa.abc."
+	account    = "autoprimaries-test"
+}
+
+data "powerdns_autoprimaries" "test" {
+	account = powerdns_autoprimary.test.account
+}`
+
+func TestAutoprimaries_DataSource(t *testing.T) {
+	client, server := newFakeServerClient(t)
+	// Left out by the account filter
+	err := client.CreateAutoprimary(context.Background(), Autoprimary{IP: "192.0.2.55", Nameserver: "ns3.primary.sysa.abc.", Account: "other"})
+	require.NoError(t, err)
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccDataSourcePDNSAutoprimariesConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerdns_autoprimaries.test", "autoprimaries.#", "1"),
+					resource.TestCheckResourceAttr("data.powerdns_autoprimaries.test", "autoprimaries.0.ip", "192.0.2.54"),
+				),
+			},
+		},
+	})
+}
+
+func TestAutoprimaries_AutoprimaryModels(t *testing.T) {
+	autoprimaries := []Autoprimary{
+		{IP: "192.0.2.2", Nameserver: "ns1.example.com.", Account: "b"},
+		{IP: "192.0.2.1", Nameserver: "ns2.example.com.", Account: "a"},
+		{IP: "192.0.2.1", Nameserver: "ns1.example.com.", Account: "b"},
+	}
+
+	all := autoprimaryModels(autoprimaries, types.StringNull())
+	assert.Equal(t, []AutoprimaryModel{
+		{IP: types.StringValue("192.0.2.1"), Nameserver: types.StringValue("ns1.example.com."), Account: types.StringValue("b")},
+		{IP: types.StringValue("192.0.2.1"), Nameserver: types.StringValue("ns2.example.com."), Account: types.StringValue("a")},
+		{IP: types.StringValue("192.0.2.2"), Nameserver: types.StringValue("ns1.example.com."), Account: types.StringValue("b")},
+	}, all)
+
+	filtered := autoprimaryModels(autoprimaries, types.StringValue("a"))
+	assert.Len(t, filtered, 1)
+	assert.Empty(t, autoprimaryModels(autoprimaries, types.StringValue("c")))
+}
@@ -69,6 +69,8 @@ func (d *ReverseZoneDataSource) Configure(ctx context.Context, req datasource.Co
 		return
 	}
 	d.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_reverse_zone")
 }
 
 func (d *ReverseZoneDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -87,6 +87,8 @@ func (d *ZoneDataSource) Configure(ctx context.Context, req datasource.Configure
 		return
 	}
 	d.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone")
 }
 
 func (d *ZoneDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
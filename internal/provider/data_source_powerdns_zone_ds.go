@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ datasource.DataSource = &ZoneDSDataSource{}
+
+// dsDigestTypes are the DS digest types returned by the data source, SHA-256
+// and SHA-384. SHA-1 digests are deprecated and left out.
+var dsDigestTypes = map[int64]bool{
+	2: true,
+	4: true,
+}
+
+// ZoneDSDataSource defines the data source implementation.
+type ZoneDSDataSource struct {
+	client *Client
+}
+
+// ZoneDSDataSourceModel describes the data source data model.
+type ZoneDSDataSourceModel struct {
+	ID        types.String        `tfsdk:"id"`
+	Zone      types.String        `tfsdk:"zone"`
+	DSRecords []ZoneDSRecordModel `tfsdk:"ds_records"`
+}
+
+// ZoneDSRecordModel describes a single DS record of the data source.
+type ZoneDSRecordModel struct {
+	KeyID      types.Int64  `tfsdk:"key_id"`
+	KeyType    types.String `tfsdk:"keytype"`
+	KeyTag     types.Int64  `tfsdk:"key_tag"`
+	Algorithm  types.Int64  `tfsdk:"algorithm"`
+	DigestType types.Int64  `tfsdk:"digest_type"`
+	Digest     types.String `tfsdk:"digest"`
+	Record     types.String `tfsdk:"record"`
+}
+
+func (d *ZoneDSDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_ds"
+}
+
+func (d *ZoneDSDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns the DS records of the active key signing keys of a zone, to be published in its parent zone.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Zone identifier",
+			},
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "The name of the zone to retrieve the DS records for",
+				Required:            true,
+			},
+			"ds_records": schema.ListNestedAttribute{
+				MarkdownDescription: "The SHA-256 and SHA-384 DS records of the active KSK and CSK keys of the zone",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key_id": schema.Int64Attribute{
+							MarkdownDescription: "The ID of the key the record is derived from",
+							Computed:            true,
+						},
+						"keytype": schema.StringAttribute{
+							MarkdownDescription: "The type of the key the record is derived from, `ksk` or `csk`",
+							Computed:            true,
+						},
+						"key_tag": schema.Int64Attribute{
+							MarkdownDescription: "The key tag of the DNSKEY record",
+							Computed:            true,
+						},
+						"algorithm": schema.Int64Attribute{
+							MarkdownDescription: "The DNSSEC algorithm number of the key",
+							Computed:            true,
+						},
+						"digest_type": schema.Int64Attribute{
+							MarkdownDescription: "The digest type, 2 for SHA-256 and 4 for SHA-384",
+							Computed:            true,
+						},
+						"digest": schema.StringAttribute{
+							MarkdownDescription: "The hex encoded digest of the DNSKEY record",
+							Computed:            true,
+						},
+						"record": schema.StringAttribute{
+							MarkdownDescription: "The content of the DS record, `<key tag> <algorithm> <digest type> <digest>`",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ZoneDSDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type", "Expected *Client")
+		return
+	}
+	d.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone_ds")
+}
+
+func (d *ZoneDSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ZoneDSDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneName := data.Zone.ValueString()
+	ctx = tflog.SetField(ctx, "zone_name", zoneName)
+	tflog.Info(ctx, "Reading zone DS data source")
+
+	keys, err := d.client.ListCryptoKeys(ctx, zoneName)
+	if errors.Is(err, ErrNotFound) {
+		resp.Diagnostics.AddError("Zone not found", fmt.Sprintf("zone %s not found", zoneName))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't fetch DNSSEC keys", err.Error())
+		return
+	}
+
+	records, err := zoneDSRecords(keys)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't parse DS records", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(zoneName)
+	data.DSRecords = records
+
+	tflog.Info(ctx, "Successfully retrieved zone DS records", map[string]interface{}{
+		"ds_count": len(records),
+	})
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// zoneDSRecords returns the SHA-256 and SHA-384 DS records of the active KSK
+// and CSK keys, ordered by key and digest type.
+func zoneDSRecords(keys []CryptoKey) ([]ZoneDSRecordModel, error) {
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	records := []ZoneDSRecordModel{}
+	for _, key := range keys {
+		keyType := strings.ToLower(key.KeyType)
+		if !key.Active || (keyType != "ksk" && keyType != "csk") {
+			continue
+		}
+
+		var keyRecords []ZoneDSRecordModel
+		for _, ds := range key.DS {
+			record, err := parseDSRecord(ds)
+			if err != nil {
+				return nil, fmt.Errorf("key %d: %w", key.ID, err)
+			}
+			if !dsDigestTypes[record.DigestType.ValueInt64()] {
+				continue
+			}
+			record.KeyID = types.Int64Value(key.ID)
+			record.KeyType = types.StringValue(keyType)
+			keyRecords = append(keyRecords, record)
+		}
+		sort.SliceStable(keyRecords, func(i, j int) bool {
+			return keyRecords[i].DigestType.ValueInt64() < keyRecords[j].DigestType.ValueInt64()
+		})
+		records = append(records, keyRecords...)
+	}
+	return records, nil
+}
+
+// parseDSRecord parses the content of a DS record, as returned by the
+// cryptokeys endpoint.
+func parseDSRecord(content string) (ZoneDSRecordModel, error) {
+	fields := strings.Fields(content)
+	if len(fields) != 4 {
+		return ZoneDSRecordModel{}, fmt.Errorf("invalid DS record %q, expected <key tag> <algorithm> <digest type> <digest>", content)
+	}
+
+	var numbers [3]int64
+	for i, field := range fields[:3] {
+		n, err := strconv.ParseUint(field, 10, 16)
+		if err != nil {
+			return ZoneDSRecordModel{}, fmt.Errorf("invalid DS record %q: %w", content, err)
+		}
+		numbers[i] = int64(n)
+	}
+	digest := strings.ToLower(fields[3])
+
+	return ZoneDSRecordModel{
+		KeyTag:     types.Int64Value(numbers[0]),
+		Algorithm:  types.Int64Value(numbers[1]),
+		DigestType: types.Int64Value(numbers[2]),
+		Digest:     types.StringValue(digest),
+		Record:     types.StringValue(fmt.Sprintf("%d %d %d %s", numbers[0], numbers[1], numbers[2], digest)),
+	}, nil
+}
+
+func NewZoneDSDataSource() datasource.DataSource {
+	return &ZoneDSDataSource{}
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourcePDNSZoneDS_basic(t *testing.T) {
+	dataSourceName := "data.powerdns_zone_ds.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourcePDNSZoneDSConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "ds_records.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "ds_records.0.keytype", "ksk"),
+					resource.TestCheckResourceAttr(dataSourceName, "ds_records.0.algorithm", "13"),
+					resource.TestCheckResourceAttr(dataSourceName, "ds_records.0.digest_type", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "ds_records.1.digest_type", "4"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ds_records.0.key_id", "powerdns_zone_cryptokey.ksk", "key_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "ds_records.0.digest"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourcePDNSZoneDSConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_zone" "test" {
+	name        = "ds.sysa.abc."
+	kind        = "Native"
+	nameservers = ["ns1.sysa.abc."]
+}
+
+resource "powerdns_zone_cryptokey" "ksk" {
+	zone      = powerdns_zone.test.name
+	keytype   = "ksk"
+	algorithm = "ECDSAP256SHA256"
+	active    = true
+}
+
+resource "powerdns_zone_cryptokey" "zsk" {
+	zone      = powerdns_zone.test.name
+	keytype   = "zsk"
+	algorithm = "ECDSAP256SHA256"
+	active    = true
+}
+
+data "powerdns_zone_ds" "test" {
+	zone       = powerdns_zone.test.name
+	depends_on = [powerdns_zone_cryptokey.ksk, powerdns_zone_cryptokey.zsk]
+}`
+
+func TestZoneDS_DataSource(t *testing.T) {
+	dataSourceName := "data.powerdns_zone_ds.test"
+	server := pdnstest.New(t)
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccDataSourcePDNSZoneDSConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "ds_records.#", "2"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ds_records.0.key_id", "powerdns_zone_cryptokey.ksk", "key_id"),
+					// Only the DS records of the KSK, as PowerDNS computed them
+					func(s *terraform.State) error {
+						attrs := s.RootModule().Resources[dataSourceName].Primary.Attributes
+						for _, key := range server.CryptoKeys("ds.sysa.abc.") {
+							if key.KeyType != "ksk" {
+								continue
+							}
+							for i, ds := range key.DS {
+								if record := attrs[fmt.Sprintf("ds_records.%d.record", i)]; record != ds {
+									return fmt.Errorf("expected DS record %q, got %q", ds, record)
+								}
+							}
+							return nil
+						}
+						return fmt.Errorf("no KSK found")
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestZoneDS_ParseDSRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "sha256",
+			content:  "3613 15 2 3aa5ab37efce57f737fc1627013fee07bdf241bd10f3b1964ab55c78e79a304b",
+			expected: "3613 15 2 3aa5ab37efce57f737fc1627013fee07bdf241bd10f3b1964ab55c78e79a304b",
+		},
+		{
+			name:     "upper case digest",
+			content:  "3613  15 2 3AA5AB37",
+			expected: "3613 15 2 3aa5ab37",
+		},
+		{name: "missing digest", content: "3613 15 2", expectError: true},
+		{name: "invalid key tag", content: "tag 15 2 3aa5ab37", expectError: true},
+		{name: "key tag out of range", content: "65536 15 2 3aa5ab37", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := parseDSRecord(tt.content)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, record.Record.ValueString())
+		})
+	}
+}
+
+func TestZoneDS_ZoneDSRecords(t *testing.T) {
+	ds := func(digestType string) string {
+		return "1234 13 " + digestType + " abcdef"
+	}
+	keys := []CryptoKey{
+		{ID: 3, KeyType: "csk", Active: true, DS: []string{ds("4"), ds("1"), ds("2")}},
+		{ID: 1, KeyType: "KSK", Active: true, DS: []string{ds("2"), ds("4")}},
+		{ID: 2, KeyType: "zsk", Active: true, DS: []string{ds("2")}},
+		{ID: 4, KeyType: "ksk", Active: false, DS: []string{ds("2")}},
+	}
+
+	records, err := zoneDSRecords(keys)
+	require.NoError(t, err)
+
+	type summary struct {
+		keyID      int64
+		keyType    string
+		digestType int64
+	}
+	var got []summary
+	for _, r := range records {
+		got = append(got, summary{r.KeyID.ValueInt64(), r.KeyType.ValueString(), r.DigestType.ValueInt64()})
+	}
+	assert.Equal(t, []summary{
+		{1, "ksk", 2},
+		{1, "ksk", 4},
+		{3, "csk", 2},
+		{3, "csk", 4},
+	}, got)
+
+	_, err = zoneDSRecords([]CryptoKey{{ID: 1, KeyType: "ksk", Active: true, DS: []string{"invalid"}}})
+	assert.Error(t, err)
+}
+
+func TestZoneDS_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+	key, err := client.CreateCryptoKey(ctx, "example.com.", CryptoKey{KeyType: "ksk", Algorithm: "ed25519", Active: true})
+	require.NoError(t, err)
+
+	keys, err := client.ListCryptoKeys(ctx, "example.com.")
+	require.NoError(t, err)
+	records, err := zoneDSRecords(keys)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, key.ID, records[0].KeyID.ValueInt64())
+	assert.Equal(t, int64(15), records[0].Algorithm.ValueInt64())
+	assert.Contains(t, server.CryptoKeys("example.com.")[0].DS, records[0].Record.ValueString())
+}
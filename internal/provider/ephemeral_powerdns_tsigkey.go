@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ ephemeral.EphemeralResource = &TSIGKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &TSIGKeyEphemeralResource{}
+
+// TSIGKeyEphemeralResource defines the ephemeral resource implementation.
+type TSIGKeyEphemeralResource struct {
+	client *Client
+}
+
+// TSIGKeyEphemeralResourceModel describes the ephemeral resource data model.
+type TSIGKeyEphemeralResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Algorithm types.String `tfsdk:"algorithm"`
+	Key       types.String `tfsdk:"key"`
+}
+
+func (e *TSIGKeyEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tsigkey"
+}
+
+func (e *TSIGKeyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches a TSIG key of the authoritative server, including its secret, without storing it in state.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the key",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID PowerDNS assigned to the key",
+				Computed:            true,
+			},
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "The TSIG algorithm of the key",
+				Computed:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded secret of the key",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (e *TSIGKeyEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Ephemeral Resource Configure Type", "Expected *Client")
+		return
+	}
+	e.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_tsigkey")
+}
+
+func (e *TSIGKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data TSIGKeyEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+	ctx = tflog.SetField(ctx, "name", name)
+	tflog.Debug(ctx, "Fetching PowerDNS TSIG key")
+
+	key, err := e.client.FindTSIGKey(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		resp.Diagnostics.AddError("TSIG key not found", fmt.Sprintf("TSIG key %s not found", name))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't fetch TSIG key", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(key.ID)
+	data.Algorithm = types.StringValue(key.Algorithm)
+	data.Key = types.StringValue(key.Key)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func NewTSIGKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &TSIGKeyEphemeralResource{}
+}
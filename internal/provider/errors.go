@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// APIError is returned when the PowerDNS API answers with an unexpected status.
+type APIError struct {
+	StatusCode int      // HTTP status code of the response
+	Method     string   // HTTP method of the request
+	Endpoint   string   // Request path, without scheme and host
+	Message    string   // The "error" field of the PowerDNS error payload
+	Errors     []string // The "errors" field of the PowerDNS error payload
+	Body       string   // Raw body, kept when it isn't a PowerDNS error payload
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: error: %d", e.Method, e.Endpoint, e.StatusCode)
+	switch {
+	case e.Message != "":
+		msg += fmt.Sprintf(", reason: %q", e.Message)
+	case e.Body != "":
+		msg += fmt.Sprintf(", body: %s", e.Body)
+	}
+	if len(e.Errors) > 0 {
+		msg += fmt.Sprintf(", errors: %q", e.Errors)
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrNotFound) hold for missing objects. Besides a
+// plain 404, the recursor reports unknown zones as a 422 "Could not find
+// domain".
+func (e *APIError) Is(target error) bool {
+	if target != ErrNotFound {
+		return false
+	}
+	return e.StatusCode == http.StatusNotFound ||
+		(e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(e.Message, "Could not find domain"))
+}
+
+// IsValidation reports whether the server rejected the request content.
+func (e *APIError) IsValidation() bool {
+	return e.StatusCode == http.StatusUnprocessableEntity || e.StatusCode == http.StatusBadRequest
+}
+
+// Reason returns the most specific explanation provided by the server.
+func (e *APIError) Reason() string {
+	reasons := make([]string, 0, len(e.Errors)+1)
+	if e.Message != "" {
+		reasons = append(reasons, e.Message)
+	}
+	reasons = append(reasons, e.Errors...)
+	if len(reasons) == 0 {
+		if e.Body != "" {
+			return e.Body
+		}
+		return http.StatusText(e.StatusCode)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// newAPIError builds an APIError from an unexpected response, consuming its body.
+func newAPIError(ctx context.Context, resp *http.Response, req *http.Request) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Endpoint:   req.URL.Path,
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		apiErr.Body = fmt.Sprintf("failed to read body: %v", err)
+		return apiErr
+	}
+
+	var errorResp errorResponse
+	if jsonErr := json.Unmarshal(bodyBytes, &errorResp); jsonErr != nil {
+		apiErr.Body = strings.TrimSpace(string(bodyBytes))
+	} else {
+		apiErr.Message = errorResp.ErrorMsg
+		apiErr.Errors = errorResp.Errors
+	}
+
+	tflog.Error(ctx, "API error response", map[string]interface{}{
+		"status":   resp.StatusCode,
+		"method":   req.Method,
+		"endpoint": apiErr.Endpoint,
+		"error":    apiErr.Message,
+		"body":     string(bodyBytes),
+	})
+
+	return apiErr
+}
+
+// addAPIErrorDiagnostic reports err in diags. Validation errors (422) are
+// attached to an attribute: the one in hints whose keyword appears in the
+// server message, or fallback otherwise.
+func addAPIErrorDiagnostic(diags *diag.Diagnostics, summary string, err error, fallback path.Path, hints map[string]path.Path) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsValidation() {
+		diags.AddError(summary, err.Error())
+		return
+	}
+
+	reason := apiErr.Reason()
+	lowerReason := strings.ToLower(reason)
+
+	// Prefer the longest keyword, so that e.g. "soa_edit_api" beats "soa"
+	keywords := make([]string, 0, len(hints))
+	for keyword := range hints {
+		keywords = append(keywords, keyword)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if len(keywords[i]) != len(keywords[j]) {
+			return len(keywords[i]) > len(keywords[j])
+		}
+		return keywords[i] < keywords[j]
+	})
+
+	attributePath := fallback
+	for _, keyword := range keywords {
+		if strings.Contains(lowerReason, strings.ToLower(keyword)) {
+			attributePath = hints[keyword]
+			break
+		}
+	}
+
+	diags.AddAttributeError(attributePath, summary, fmt.Sprintf("PowerDNS rejected the request: %s", reason))
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *APIError
+		expected string
+	}{
+		{
+			name:     "message",
+			err:      &APIError{StatusCode: 422, Method: "PATCH", Endpoint: "/api/v1/servers/localhost/zones/example.com.", Message: "RRset is invalid"},
+			expected: `PATCH /api/v1/servers/localhost/zones/example.com.: error: 422, reason: "RRset is invalid"`,
+		},
+		{
+			name:     "raw body",
+			err:      &APIError{StatusCode: 500, Method: "GET", Endpoint: "/api/v1/servers", Body: "Internal Server Error"},
+			expected: `GET /api/v1/servers: error: 500, body: Internal Server Error`,
+		},
+		{
+			name:     "message and errors",
+			err:      &APIError{StatusCode: 400, Method: "POST", Endpoint: "/api/v1/servers/localhost/zones", Message: "Invalid", Errors: []string{"a", "b"}},
+			expected: `POST /api/v1/servers/localhost/zones: error: 400, reason: "Invalid", errors: ["a" "b"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.Error())
+		})
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "not found", err: &APIError{StatusCode: http.StatusNotFound}, expected: true},
+		{name: "recursor unknown domain", err: &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Could not find domain 'example.com.'"}, expected: true},
+		{name: "validation error", err: &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Invalid zone kind"}, expected: false},
+		{name: "server error", err: &APIError{StatusCode: http.StatusInternalServerError}, expected: false},
+		{name: "wrapped not found", err: fmt.Errorf("error reading zone: %w", &APIError{StatusCode: http.StatusNotFound}), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, errors.Is(tt.err, ErrNotFound))
+		})
+	}
+}
+
+func TestAPIError_NewAPIError(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:8081/api/v1/servers/localhost/zones", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedMessage string
+		expectedErrors  []string
+		expectedBody    string
+	}{
+		{
+			name:            "error payload",
+			body:            `{"error": "Nameserver is not canonical", "errors": ["ns1.example.com"]}`,
+			expectedMessage: "Nameserver is not canonical",
+			expectedErrors:  []string{"ns1.example.com"},
+		},
+		{
+			name:         "plain text",
+			body:         "Bad Gateway\n",
+			expectedBody: "Bad Gateway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusUnprocessableEntity, Body: io.NopCloser(strings.NewReader(tt.body))}
+			apiErr := newAPIError(context.Background(), resp, req)
+			assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+			assert.Equal(t, http.MethodPost, apiErr.Method)
+			assert.Equal(t, "/api/v1/servers/localhost/zones", apiErr.Endpoint)
+			assert.Equal(t, tt.expectedMessage, apiErr.Message)
+			assert.Equal(t, tt.expectedErrors, apiErr.Errors)
+			assert.Equal(t, tt.expectedBody, apiErr.Body)
+		})
+	}
+}
+
+func TestAPIError_AddAPIErrorDiagnostic(t *testing.T) {
+	hints := map[string]path.Path{
+		"soa":          path.Root("soa"),
+		"soa-edit-api": path.Root("soa_edit_api"),
+		"nameserver":   path.Root("nameservers"),
+	}
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedPath *path.Path
+	}{
+		{
+			name:         "matching keyword",
+			err:          &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Nameserver is not canonical: 'ns1'"},
+			expectedPath: pathPointer(path.Root("nameservers")),
+		},
+		{
+			name:         "longest keyword wins",
+			err:          &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Invalid SOA-EDIT-API value"},
+			expectedPath: pathPointer(path.Root("soa_edit_api")),
+		},
+		{
+			name:         "fallback",
+			err:          fmt.Errorf("error creating zone: %w", &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Conflicting zone"}),
+			expectedPath: pathPointer(path.Root("name")),
+		},
+		{
+			name: "server error",
+			err:  &APIError{StatusCode: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addAPIErrorDiagnostic(&diags, "Failed to create zone", tt.err, path.Root("name"), hints)
+			require.Len(t, diags, 1)
+
+			withPath, ok := diags[0].(diag.DiagnosticWithPath)
+			if tt.expectedPath == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, *tt.expectedPath, withPath.Path())
+		})
+	}
+}
+
+func pathPointer(p path.Path) *path.Path {
+	return &p
+}
+
+func TestAPIError_GetZoneNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 0)
+	_, err := client.GetZone(context.Background(), "example.com.")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
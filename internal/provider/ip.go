@@ -246,3 +246,13 @@ func GetReverseZoneName(cidr string) (string, error) {
 	return zone, nil
 
 }
+
+// sameIP reports whether a and b are the same IP address, whatever their
+// spelling, e.g. "2001:db8::1" and "2001:db8:0::1".
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
@@ -290,3 +290,23 @@ func TestIP_GetReverseZoneName(t *testing.T) {
 		})
 	}
 }
+
+func TestIP_SameIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{name: "identical IPv4", a: "192.0.2.1", b: "192.0.2.1", expected: true},
+		{name: "different IPv4", a: "192.0.2.1", b: "192.0.2.2", expected: false},
+		{name: "IPv6 spellings", a: "2001:db8::1", b: "2001:DB8:0::1", expected: true},
+		{name: "invalid IPs compared as strings", a: "primary", b: "primary", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sameIP(tt.a, tt.b))
+		})
+	}
+}
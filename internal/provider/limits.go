@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithConcurrencyLimit bounds how hard the client may hit PowerDNS: at most
+// maxConcurrent requests in flight, and at most requestsPerSecond requests
+// started every second. Zero disables the respective limit.
+func WithConcurrencyLimit(maxConcurrent int, requestsPerSecond float64) ClientOption {
+	return func(client *Client) {
+		client.MaxConcurrentRequests = maxConcurrent
+		client.RequestsPerSecond = requestsPerSecond
+	}
+}
+
+// rateLimiter spaces requests evenly so that no more than a given number of
+// them start every second.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the caller may start a request, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// acquire waits until the configured limits allow one more request. The
+// returned func gives the slot back and must be called once the response has
+// been consumed.
+func (client *Client) acquire(ctx context.Context) (func(), error) {
+	if client.limiter != nil {
+		if err := client.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if client.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case client.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-client.slots })
+	}, nil
+}
+
+// apiVersion returns the API version of the authoritative server, detecting
+// it on first use. Concurrent callers wait for a single detection.
+func (client *Client) apiVersion(ctx context.Context) (int, error) {
+	client.apiVersionMu.Lock()
+	defer client.apiVersionMu.Unlock()
+
+	if client.APIVersion < 0 {
+		detected, err := client.detectAPIVersion(ctx)
+		if err != nil {
+			return -1, err
+		}
+		client.APIVersion = detected
+	}
+	return client.APIVersion, nil
+}
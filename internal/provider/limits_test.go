@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits_APIVersionDetectedOnce(t *testing.T) {
+	var detections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/servers" {
+			detections.Add(1)
+			// Keep concurrent callers waiting on the detection
+			time.Sleep(20 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 0)
+	client.APIVersion = -1
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListZones(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), detections.Load())
+	assert.Equal(t, 1, client.APIVersion)
+}
+
+func TestLimits_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), server.URL, "", "secret", nil, false, "", 0,
+		WithRetryPolicy(0, 0, 0), WithConcurrencyLimit(2, 0))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListZones(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	assert.Empty(t, client.slots, "every slot must be released")
+}
+
+func TestLimits_RequestsPerSecond(t *testing.T) {
+	limiter := newRateLimiter(50)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		require.NoError(t, limiter.wait(context.Background()))
+	}
+
+	// The first request starts right away, the next five 20ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestLimits_AcquireCancelled(t *testing.T) {
+	client := &Client{slots: make(chan struct{}, 1)}
+
+	release, err := client.acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release()
+	assert.Empty(t, client.slots)
+}
+
+func TestLimits_NewClientValidation(t *testing.T) {
+	_, err := NewClient(context.Background(), "https://pdns.example.com", "", "secret", nil, false, "", 0,
+		WithConcurrencyLimit(-1, 0))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maxConcurrentRequests cannot be negative")
+
+	_, err = NewClient(context.Background(), "https://pdns.example.com", "", "secret", nil, false, "", 0,
+		WithConcurrencyLimit(0, -1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requestsPerSecond cannot be negative")
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestMain serves the acceptance tests from the in-process fake PowerDNS API
+// when PDNS_TEST_FAKE is set, on the addresses of the docker-compose stack.
+func TestMain(m *testing.M) {
+	if os.Getenv(resource.EnvTfAcc) == "" || os.Getenv("PDNS_TEST_FAKE") == "" {
+		os.Exit(m.Run())
+	}
+
+	server, err := pdnstest.Start(pdnstest.WithAddrs("127.0.0.1:8081", "127.0.0.1:8082"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start the fake PowerDNS API: %s\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("PDNS_SERVER_URL", server.URL)
+	os.Setenv("PDNS_RECURSOR_SERVER_URL", server.RecursorURL)
+	os.Setenv("PDNS_API_KEY", server.APIKey())
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
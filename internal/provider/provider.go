@@ -5,10 +5,16 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -47,8 +53,62 @@ func getConfigIntWithEnvFallback(configValue int, isNull bool, isUnknown bool, e
 	return configValue
 }
 
+// getConfigFloatWithEnvFallback returns the config float or falls back to the environment variable.
+func getConfigFloatWithEnvFallback(configValue float64, isNull bool, isUnknown bool, envVar string) float64 {
+	if isNull || isUnknown {
+		if env := os.Getenv(envVar); env != "" {
+			if parsed, err := strconv.ParseFloat(env, 64); err == nil {
+				return parsed
+			}
+		}
+	}
+	return configValue
+}
+
+// parseDurationWithDefault parses a duration string such as "500ms" or "30s",
+// returning defaultValue when the string is empty.
+func parseDurationWithDefault(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("duration %q cannot be negative", value)
+	}
+	return duration, nil
+}
+
+// requireServer reports that typeName, a resource, data source or action
+// type, can't be used when the authoritative server isn't configured.
+func requireServer(diags *diag.Diagnostics, client *Client, typeName string) {
+	if client.HasServer() {
+		return
+	}
+	diags.AddError(
+		"PowerDNS authoritative server not configured",
+		fmt.Sprintf("%s requires the PowerDNS authoritative server, but the provider has no server_url. Set server_url in the provider configuration or the PDNS_SERVER_URL environment variable.", typeName),
+	)
+}
+
+// requireRecursorServer reports that typeName, a resource, data source or
+// action type, can't be used when the recursor server isn't configured.
+func requireRecursorServer(diags *diag.Diagnostics, client *Client, typeName string) {
+	if client.HasRecursorServer() {
+		return
+	}
+	diags.AddError(
+		"PowerDNS recursor server not configured",
+		fmt.Sprintf("%s requires the PowerDNS recursor server, but the provider has no recursor_server_url. Set recursor_server_url in the provider configuration or the PDNS_RECURSOR_SERVER_URL environment variable.", typeName),
+	)
+}
+
 // Ensure PowerDNSProvider satisfies various provider interfaces.
 var _ provider.Provider = &PowerDNSProvider{}
+var _ provider.ProviderWithEphemeralResources = &PowerDNSProvider{}
+var _ provider.ProviderWithActions = &PowerDNSProvider{}
 
 // PowerDNSProvider defines the provider implementation.
 type PowerDNSProvider struct {
@@ -60,16 +120,25 @@ type PowerDNSProvider struct {
 
 // PowerDNSProviderModel describes the provider data model.
 type PowerDNSProviderModel struct {
-	APIKey            types.String `tfsdk:"api_key"`
-	ClientCertFile    types.String `tfsdk:"client_cert_file"`
-	ClientCertKeyFile types.String `tfsdk:"client_cert_key_file"`
-	ServerURL         types.String `tfsdk:"server_url"`
-	RecursorServerURL types.String `tfsdk:"recursor_server_url"`
-	InsecureHTTPS     types.Bool   `tfsdk:"insecure_https"`
-	CACertificate     types.String `tfsdk:"ca_certificate"`
-	CacheRequests     types.Bool   `tfsdk:"cache_requests"`
-	CacheMemSize      types.String `tfsdk:"cache_mem_size"`
-	CacheTTL          types.Int64  `tfsdk:"cache_ttl"`
+	APIKey            types.String  `tfsdk:"api_key"`
+	ClientCertFile    types.String  `tfsdk:"client_cert_file"`
+	ClientCertKeyFile types.String  `tfsdk:"client_cert_key_file"`
+	ServerURL         types.String  `tfsdk:"server_url"`
+	RecursorServerURL types.String  `tfsdk:"recursor_server_url"`
+	InsecureHTTPS     types.Bool    `tfsdk:"insecure_https"`
+	CACertificate     types.String  `tfsdk:"ca_certificate"`
+	CacheRequests     types.Bool    `tfsdk:"cache_requests"`
+	CacheMemSize      types.String  `tfsdk:"cache_mem_size"`
+	CacheTTL          types.Int64   `tfsdk:"cache_ttl"`
+	MaxRetries        types.Int64   `tfsdk:"max_retries"`
+	RetryMinWait      types.String  `tfsdk:"retry_min_wait"`
+	RetryMaxWait      types.String  `tfsdk:"retry_max_wait"`
+	RequestTimeout    types.String  `tfsdk:"request_timeout"`
+	ServerID          types.String  `tfsdk:"server_id"`
+	RecursorServerID  types.String  `tfsdk:"recursor_server_id"`
+	MaxConcurrent     types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	RRSetBatchWindow  types.String  `tfsdk:"rrset_batch_window"`
 }
 
 func (p *PowerDNSProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -121,6 +190,42 @@ func (p *PowerDNSProvider) Schema(ctx context.Context, req provider.SchemaReques
 				MarkdownDescription: "Base URL of the PowerDNS recursor server. Also via PDNS_RECURSOR_SERVER_URL.",
 				Optional:            true,
 			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of retries for transient API failures (connection errors, 429, 502, 503, 504). Defaults to 3, 0 disables retries. Also via PDNS_MAX_RETRIES.",
+				Optional:            true,
+			},
+			"retry_min_wait": schema.StringAttribute{
+				MarkdownDescription: "Minimum wait between retries as a duration, e.g. `500ms`. Defaults to `1s`. Also via PDNS_RETRY_MIN_WAIT.",
+				Optional:            true,
+			},
+			"retry_max_wait": schema.StringAttribute{
+				MarkdownDescription: "Maximum wait between retries as a duration, e.g. `30s`. Defaults to `30s`. Also via PDNS_RETRY_MAX_WAIT.",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of a single API request as a duration, e.g. `60s`. Defaults to `60s`. Also via PDNS_REQUEST_TIMEOUT.",
+				Optional:            true,
+			},
+			"server_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the authoritative server in API paths (`/servers/{server_id}`). Defaults to `localhost`. Also via PDNS_SERVER_ID.",
+				Optional:            true,
+			},
+			"recursor_server_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the recursor server in API paths (`/servers/{recursor_server_id}`). Defaults to `localhost`. Also via PDNS_RECURSOR_SERVER_ID.",
+				Optional:            true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of API requests in flight at once. Defaults to 0, no limit. Also via PDNS_MAX_CONCURRENT_REQUESTS.",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of API requests started per second. Defaults to 0, no limit. Also via PDNS_REQUESTS_PER_SECOND.",
+				Optional:            true,
+			},
+			"rrset_batch_window": schema.StringAttribute{
+				MarkdownDescription: "When set, record changes to the same zone made within this duration, e.g. `200ms`, are sent as a single PATCH. Disabled by default. Also via PDNS_RRSET_BATCH_WINDOW.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -134,6 +239,31 @@ func (p *PowerDNSProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	maxRetries := int(data.MaxRetries.ValueInt64())
+	if data.MaxRetries.IsNull() {
+		maxRetries = defaultMaxRetries
+	}
+
+	retryMinWait, err := parseDurationWithDefault(getConfigValueWithEnvFallback(data.RetryMinWait.ValueString(), "PDNS_RETRY_MIN_WAIT"), defaultRetryMinWait)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("retry_min_wait"), "Invalid retry_min_wait", err.Error())
+	}
+	retryMaxWait, err := parseDurationWithDefault(getConfigValueWithEnvFallback(data.RetryMaxWait.ValueString(), "PDNS_RETRY_MAX_WAIT"), defaultRetryMaxWait)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("retry_max_wait"), "Invalid retry_max_wait", err.Error())
+	}
+	requestTimeout, err := parseDurationWithDefault(getConfigValueWithEnvFallback(data.RequestTimeout.ValueString(), "PDNS_REQUEST_TIMEOUT"), defaultRequestTimeout)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("request_timeout"), "Invalid request_timeout", err.Error())
+	}
+	rrSetBatchWindow, err := parseDurationWithDefault(getConfigValueWithEnvFallback(data.RRSetBatchWindow.ValueString(), "PDNS_RRSET_BATCH_WINDOW"), 0)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("rrset_batch_window"), "Invalid rrset_batch_window", err.Error())
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create the client
 	config := Config{
 		APIKey:            getConfigValueWithEnvFallback(data.APIKey.ValueString(), "PDNS_API_KEY"),
@@ -146,6 +276,15 @@ func (p *PowerDNSProvider) Configure(ctx context.Context, req provider.Configure
 		CacheEnable:       getConfigBoolWithEnvFallback(data.CacheRequests.ValueBool(), data.CacheRequests.IsNull(), data.CacheRequests.IsUnknown(), "PDNS_CACHE_REQUESTS"),
 		CacheMemorySize:   getConfigValueWithEnvFallback(data.CacheMemSize.ValueString(), "PDNS_CACHE_MEM_SIZE"),
 		CacheTTL:          getConfigIntWithEnvFallback(int(data.CacheTTL.ValueInt64()), data.CacheTTL.IsNull(), data.CacheTTL.IsUnknown(), "PDNS_CACHE_TTL"),
+		MaxRetries:        getConfigIntWithEnvFallback(maxRetries, data.MaxRetries.IsNull(), data.MaxRetries.IsUnknown(), "PDNS_MAX_RETRIES"),
+		RetryMinWait:      retryMinWait,
+		RetryMaxWait:      retryMaxWait,
+		RequestTimeout:    requestTimeout,
+		ServerID:          getConfigValueWithEnvFallback(data.ServerID.ValueString(), "PDNS_SERVER_ID"),
+		RecursorServerID:  getConfigValueWithEnvFallback(data.RecursorServerID.ValueString(), "PDNS_RECURSOR_SERVER_ID"),
+		MaxConcurrent:     getConfigIntWithEnvFallback(int(data.MaxConcurrent.ValueInt64()), data.MaxConcurrent.IsNull(), data.MaxConcurrent.IsUnknown(), "PDNS_MAX_CONCURRENT_REQUESTS"),
+		RRSetBatchWindow:  rrSetBatchWindow,
+		RequestsPerSecond: getConfigFloatWithEnvFallback(data.RequestsPerSecond.ValueFloat64(), data.RequestsPerSecond.IsNull(), data.RequestsPerSecond.IsUnknown(), "PDNS_REQUESTS_PER_SECOND"),
 	}
 
 	client, err := config.Client(ctx)
@@ -156,6 +295,8 @@ func (p *PowerDNSProvider) Configure(ctx context.Context, req provider.Configure
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
+	resp.ActionData = client
 }
 
 func (p *PowerDNSProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -165,6 +306,10 @@ func (p *PowerDNSProvider) Resources(ctx context.Context) []func() resource.Reso
 		NewPTRRecordResource,
 		NewReverseZoneResource,
 		NewRecursorForwardZoneResource,
+		NewZoneCryptoKeyResource,
+		NewZoneMetadataResource,
+		NewTSIGKeyResource,
+		NewAutoprimaryResource,
 	}
 }
 
@@ -172,6 +317,24 @@ func (p *PowerDNSProvider) DataSources(ctx context.Context) []func() datasource.
 	return []func() datasource.DataSource{
 		NewReverseZoneDataSource,
 		NewZoneDataSource,
+		NewZoneDSDataSource,
+		NewAutoprimariesDataSource,
+	}
+}
+
+func (p *PowerDNSProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewTSIGKeyEphemeralResource,
+	}
+}
+
+func (p *PowerDNSProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewZoneRectifyAction,
+		NewZoneNotifyAction,
+		NewZoneAXFRRetrieveAction,
+		NewCacheFlushAction,
+		NewRecursorCacheFlushAction,
 	}
 }
 
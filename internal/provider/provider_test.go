@@ -1,10 +1,13 @@
 package provider
 
 import (
+	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
@@ -37,6 +40,57 @@ func TestAccProvider_Configure(t *testing.T) {
 	})
 }
 
+func TestProvider_Schema(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema() error = %v", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Errorf("GetProviderSchema() diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+func TestProvider_RequireConfiguredServer(t *testing.T) {
+	ctx := context.Background()
+	recursorOnly := &Client{RecursorServerURL: "https://recursor.example.com"}
+	authOnly := &Client{ServerURL: "https://pdns.example.com"}
+
+	tests := []struct {
+		name        string
+		resource    fwresource.ResourceWithConfigure
+		client      *Client
+		expectError string
+	}{
+		{name: "zone without server", resource: &ZoneResource{}, client: recursorOnly, expectError: "powerdns_zone requires the PowerDNS authoritative server"},
+		{name: "zone with server", resource: &ZoneResource{}, client: authOnly},
+		{name: "forward zone without recursor", resource: &RecursorForwardZoneResource{}, client: authOnly, expectError: "powerdns_recursor_forward_zone requires the PowerDNS recursor server"},
+		{name: "forward zone with recursor", resource: &RecursorForwardZoneResource{}, client: recursorOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &fwresource.ConfigureResponse{}
+			tt.resource.Configure(ctx, fwresource.ConfigureRequest{ProviderData: tt.client}, resp)
+			if tt.expectError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Configure() unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() {
+				t.Fatalf("Configure() expected an error")
+			}
+			if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, tt.expectError) {
+				t.Errorf("Configure() detail = %q, want it to contain %q", detail, tt.expectError)
+			}
+		})
+	}
+}
+
 func TestProvider_GetConfigValueWithEnvFallback(t *testing.T) {
 	// Set up environment variable
 	os.Setenv("TEST_ENV_VAR", "env-value")
@@ -193,3 +247,56 @@ func TestProvider_GetConfigIntWithEnvFallback(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_GetConfigFloatWithEnvFallback(t *testing.T) {
+	// Set up environment variables
+	os.Setenv("TEST_FLOAT_2_5", "2.5")
+	os.Setenv("TEST_FLOAT_INVALID", "invalid")
+	defer func() {
+		os.Unsetenv("TEST_FLOAT_2_5")
+		os.Unsetenv("TEST_FLOAT_INVALID")
+	}()
+
+	tests := []struct {
+		name        string
+		configValue float64
+		isNull      bool
+		isUnknown   bool
+		envVar      string
+		expected    float64
+	}{
+		{
+			name:        "config value provided",
+			configValue: 10,
+			isNull:      false,
+			isUnknown:   false,
+			envVar:      "TEST_FLOAT_2_5",
+			expected:    10,
+		},
+		{
+			name:        "fallback to env var",
+			configValue: 0,
+			isNull:      true,
+			isUnknown:   false,
+			envVar:      "TEST_FLOAT_2_5",
+			expected:    2.5,
+		},
+		{
+			name:        "invalid env var",
+			configValue: 10,
+			isNull:      true,
+			isUnknown:   false,
+			envVar:      "TEST_FLOAT_INVALID",
+			expected:    10, // Should return config value on parse error
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getConfigFloatWithEnvFallback(tt.configValue, tt.isNull, tt.isUnknown, tt.envVar)
+			if result != tt.expected {
+				t.Errorf("getConfigFloatWithEnvFallback() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,752 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxCharacterString is the length limit of the strings of TXT records.
+const maxCharacterString = 255
+
+// recordContent is implemented by the models of the typed record blocks.
+type recordContent interface {
+	// content renders the block in the presentation format of PowerDNS.
+	content() string
+	// check reports the first invalid known value of the block.
+	check() error
+}
+
+// typedRecordBlock describes a block of powerdns_record rendering the
+// content of records of some types from structured attributes.
+type typedRecordBlock struct {
+	name        string
+	recordTypes []string
+	description string
+	objectType  types.ObjectType
+	attributes  map[string]schema.Attribute
+	// field returns the set of the block in the model.
+	field func(m *RecordResourceModel) *types.Set
+	// contents renders the blocks of the set.
+	contents func(ctx context.Context, set types.Set) ([]string, diag.Diagnostics)
+	// blocks parses record contents back into a set of blocks.
+	blocks func(ctx context.Context, contents []string) (types.Set, error)
+}
+
+// supports reports whether the block can describe records of type tpe.
+func (b typedRecordBlock) supports(tpe string) bool {
+	return slices.Contains(b.recordTypes, strings.ToUpper(tpe))
+}
+
+// schemaBlock returns the schema of the block.
+func (b typedRecordBlock) schemaBlock() schema.Block {
+	return schema.SetNestedBlock{
+		MarkdownDescription: b.description,
+		NestedObject:        schema.NestedBlockObject{Attributes: b.attributes},
+	}
+}
+
+// typedRecordBlocks lists the typed record blocks of powerdns_record.
+var typedRecordBlocks = []typedRecordBlock{
+	{
+		name:        "mx",
+		recordTypes: []string{"MX"},
+		description: "An MX record of the rrset, as an alternative to `records`",
+		objectType:  mxRecordType,
+		attributes: map[string]schema.Attribute{
+			"preference": int64ContentAttribute("The preference of the mail server, lower values being preferred"),
+			"exchange":   dnsNameContentAttribute("The host name of the mail server"),
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.MX },
+		contents: renderContents[MXRecordModel],
+		blocks:   parseContents(mxRecordType, parseMXContent),
+	},
+	{
+		name:        "srv",
+		recordTypes: []string{"SRV"},
+		description: "An SRV record of the rrset, as an alternative to `records`",
+		objectType:  srvRecordType,
+		attributes: map[string]schema.Attribute{
+			"priority": int64ContentAttribute("The priority of the target, lower values being preferred"),
+			"weight":   int64ContentAttribute("The relative weight of targets of the same priority"),
+			"port":     int64ContentAttribute("The port of the service on the target"),
+			"target":   dnsNameContentAttribute("The host name providing the service"),
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.SRV },
+		contents: renderContents[SRVRecordModel],
+		blocks:   parseContents(srvRecordType, parseSRVContent),
+	},
+	{
+		name:        "caa",
+		recordTypes: []string{"CAA"},
+		description: "A CAA record of the rrset, as an alternative to `records`",
+		objectType:  caaRecordType,
+		attributes: map[string]schema.Attribute{
+			"flags": int64ContentAttribute("The flags of the property, `128` marking it critical"),
+			"tag":   stringContentAttribute("The property, such as `issue`, `issuewild` or `iodef`"),
+			"value": stringContentAttribute("The value of the property, unquoted"),
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.CAA },
+		contents: renderContents[CAARecordModel],
+		blocks:   parseContents(caaRecordType, parseCAAContent),
+	},
+	{
+		name:        "tlsa",
+		recordTypes: []string{"TLSA"},
+		description: "A TLSA record of the rrset, as an alternative to `records`",
+		objectType:  tlsaRecordType,
+		attributes: map[string]schema.Attribute{
+			"usage":            int64ContentAttribute("The certificate usage"),
+			"selector":         int64ContentAttribute("Which part of the certificate is matched"),
+			"matching_type":    int64ContentAttribute("How the certificate data is matched"),
+			"certificate_data": stringContentAttribute("The lowercase hex encoded certificate association data"),
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.TLSA },
+		contents: renderContents[TLSARecordModel],
+		blocks:   parseContents(tlsaRecordType, parseTLSAContent),
+	},
+	{
+		name:        "sshfp",
+		recordTypes: []string{"SSHFP"},
+		description: "An SSHFP record of the rrset, as an alternative to `records`",
+		objectType:  sshfpRecordType,
+		attributes: map[string]schema.Attribute{
+			"algorithm":        int64ContentAttribute("The algorithm of the SSH key"),
+			"fingerprint_type": int64ContentAttribute("The hash algorithm of the fingerprint"),
+			"fingerprint":      stringContentAttribute("The lowercase hex encoded fingerprint of the SSH key"),
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.SSHFP },
+		contents: renderContents[SSHFPRecordModel],
+		blocks:   parseContents(sshfpRecordType, parseSSHFPContent),
+	},
+	{
+		name:        "svcb",
+		recordTypes: []string{"SVCB", "HTTPS"},
+		description: "An SVCB or HTTPS record of the rrset, as an alternative to `records`",
+		objectType:  svcbRecordType,
+		attributes: map[string]schema.Attribute{
+			"priority": int64ContentAttribute("The priority of the record, `0` making it an alias"),
+			"target":   dnsNameContentAttribute("The host name of the service, `.` for the owner name"),
+			"params": schema.MapAttribute{
+				MarkdownDescription: "The service parameters, such as `alpn` or `port`, by key. Parameters without a value, such as `no-default-alpn`, map to an empty string. Leave it out rather than setting it to an empty map. `ipv6hint` addresses are written in their canonical form, such as `2001:db8::1`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.SVCB },
+		contents: renderContents[SVCBRecordModel],
+		blocks:   parseContents(svcbRecordType, parseSVCBContent),
+	},
+	{
+		name:        "txt",
+		recordTypes: []string{"TXT"},
+		description: "A TXT record of the rrset, as an alternative to `records`",
+		objectType:  txtRecordType,
+		attributes: map[string]schema.Attribute{
+			"value": stringContentAttribute("The text of the record, unquoted. Values longer than 255 bytes are split into several strings."),
+		},
+		field:    func(m *RecordResourceModel) *types.Set { return &m.TXT },
+		contents: renderContents[TXTRecordModel],
+		blocks:   parseContents(txtRecordType, parseTXTContent),
+	},
+}
+
+func int64ContentAttribute(description string) schema.Attribute {
+	return schema.Int64Attribute{MarkdownDescription: description, Required: true}
+}
+
+func stringContentAttribute(description string) schema.Attribute {
+	return schema.StringAttribute{MarkdownDescription: description, Required: true}
+}
+
+func dnsNameContentAttribute(description string) schema.Attribute {
+	return schema.StringAttribute{MarkdownDescription: description, CustomType: DNSNameType{}, Required: true}
+}
+
+// typedRecordBlockFor returns the typed block able to describe records of
+// type tpe.
+func typedRecordBlockFor(tpe string) (typedRecordBlock, bool) {
+	for _, block := range typedRecordBlocks {
+		if block.supports(tpe) {
+			return block, true
+		}
+	}
+	return typedRecordBlock{}, false
+}
+
+// renderContents renders the blocks of a set, reporting invalid values.
+func renderContents[T recordContent](ctx context.Context, set types.Set) ([]string, diag.Diagnostics) {
+	var blocks []T
+	diags := set.ElementsAs(ctx, &blocks, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	contents := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if err := block.check(); err != nil {
+			diags.AddError("Invalid record", err.Error())
+			continue
+		}
+		contents = append(contents, block.content())
+	}
+	return contents, diags
+}
+
+// parseContents returns a func parsing record contents with parse into a set
+// of blocks of type objectType.
+func parseContents[T any](objectType types.ObjectType, parse func(string) (T, error)) func(context.Context, []string) (types.Set, error) {
+	return func(ctx context.Context, contents []string) (types.Set, error) {
+		blocks := make([]T, 0, len(contents))
+		for _, content := range contents {
+			block, err := parse(content)
+			if err != nil {
+				return types.SetNull(objectType), fmt.Errorf("can't parse %q: %w", content, err)
+			}
+			blocks = append(blocks, block)
+		}
+
+		set, diags := types.SetValueFrom(ctx, objectType, blocks)
+		if diags.HasError() {
+			return types.SetNull(objectType), errors.New(diags.Errors()[0].Detail())
+		}
+		return set, nil
+	}
+}
+
+var mxRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"preference": types.Int64Type,
+	"exchange":   DNSNameType{},
+}}
+
+// MXRecordModel describes an mx block of powerdns_record.
+type MXRecordModel struct {
+	Preference types.Int64  `tfsdk:"preference"`
+	Exchange   DNSNameValue `tfsdk:"exchange"`
+}
+
+func (m MXRecordModel) content() string {
+	return fmt.Sprintf("%d %s", m.Preference.ValueInt64(), m.Exchange.FQDN())
+}
+
+func (m MXRecordModel) check() error {
+	return errors.Join(checkUint(m.Preference, "preference", 16), checkHostName(m.Exchange, "exchange"))
+}
+
+func parseMXContent(content string) (MXRecordModel, error) {
+	var m MXRecordModel
+	fields, err := contentFields(content, 2, 2)
+	if err != nil {
+		return m, err
+	}
+	m.Preference, err = parseUint(fields[0], 16)
+	m.Exchange = NewDNSNameValue(fields[1])
+	return m, err
+}
+
+var srvRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"priority": types.Int64Type,
+	"weight":   types.Int64Type,
+	"port":     types.Int64Type,
+	"target":   DNSNameType{},
+}}
+
+// SRVRecordModel describes an srv block of powerdns_record.
+type SRVRecordModel struct {
+	Priority types.Int64  `tfsdk:"priority"`
+	Weight   types.Int64  `tfsdk:"weight"`
+	Port     types.Int64  `tfsdk:"port"`
+	Target   DNSNameValue `tfsdk:"target"`
+}
+
+func (m SRVRecordModel) content() string {
+	return fmt.Sprintf("%d %d %d %s", m.Priority.ValueInt64(), m.Weight.ValueInt64(), m.Port.ValueInt64(), m.Target.FQDN())
+}
+
+func (m SRVRecordModel) check() error {
+	return errors.Join(
+		checkUint(m.Priority, "priority", 16),
+		checkUint(m.Weight, "weight", 16),
+		checkUint(m.Port, "port", 16),
+		checkHostName(m.Target, "target"),
+	)
+}
+
+func parseSRVContent(content string) (SRVRecordModel, error) {
+	var m SRVRecordModel
+	fields, err := contentFields(content, 4, 4)
+	if err != nil {
+		return m, err
+	}
+	var errs [3]error
+	m.Priority, errs[0] = parseUint(fields[0], 16)
+	m.Weight, errs[1] = parseUint(fields[1], 16)
+	m.Port, errs[2] = parseUint(fields[2], 16)
+	m.Target = NewDNSNameValue(fields[3])
+	return m, errors.Join(errs[:]...)
+}
+
+var caaRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"flags": types.Int64Type,
+	"tag":   types.StringType,
+	"value": types.StringType,
+}}
+
+// CAARecordModel describes a caa block of powerdns_record.
+type CAARecordModel struct {
+	Flags types.Int64  `tfsdk:"flags"`
+	Tag   types.String `tfsdk:"tag"`
+	Value types.String `tfsdk:"value"`
+}
+
+func (m CAARecordModel) content() string {
+	return fmt.Sprintf("%d %s %s", m.Flags.ValueInt64(), m.Tag.ValueString(), quoteCharacterString(m.Value.ValueString()))
+}
+
+func (m CAARecordModel) check() error {
+	var errTag error
+	tag := m.Tag.ValueString()
+	if !m.Tag.IsUnknown() && (tag == "" || strings.IndexFunc(tag, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	}) >= 0) {
+		errTag = fmt.Errorf("tag must be made of letters and digits only, got %q", tag)
+	}
+	return errors.Join(checkUint(m.Flags, "flags", 8), errTag)
+}
+
+func parseCAAContent(content string) (CAARecordModel, error) {
+	var m CAARecordModel
+	fields, err := contentFields(content, 3, 3)
+	if err != nil {
+		return m, err
+	}
+	m.Flags, err = parseUint(fields[0], 8)
+	m.Tag = types.StringValue(fields[1])
+	m.Value = types.StringValue(fields[2])
+	return m, err
+}
+
+var tlsaRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"usage":            types.Int64Type,
+	"selector":         types.Int64Type,
+	"matching_type":    types.Int64Type,
+	"certificate_data": types.StringType,
+}}
+
+// TLSARecordModel describes a tlsa block of powerdns_record.
+type TLSARecordModel struct {
+	Usage           types.Int64  `tfsdk:"usage"`
+	Selector        types.Int64  `tfsdk:"selector"`
+	MatchingType    types.Int64  `tfsdk:"matching_type"`
+	CertificateData types.String `tfsdk:"certificate_data"`
+}
+
+func (m TLSARecordModel) content() string {
+	return fmt.Sprintf("%d %d %d %s", m.Usage.ValueInt64(), m.Selector.ValueInt64(), m.MatchingType.ValueInt64(), strings.ToLower(m.CertificateData.ValueString()))
+}
+
+func (m TLSARecordModel) check() error {
+	return errors.Join(
+		checkUint(m.Usage, "usage", 8),
+		checkUint(m.Selector, "selector", 8),
+		checkUint(m.MatchingType, "matching_type", 8),
+		checkHex(m.CertificateData, "certificate_data"),
+	)
+}
+
+func parseTLSAContent(content string) (TLSARecordModel, error) {
+	var m TLSARecordModel
+	fields, err := contentFields(content, 4, -1)
+	if err != nil {
+		return m, err
+	}
+	var errs [3]error
+	m.Usage, errs[0] = parseUint(fields[0], 8)
+	m.Selector, errs[1] = parseUint(fields[1], 8)
+	m.MatchingType, errs[2] = parseUint(fields[2], 8)
+	m.CertificateData = types.StringValue(strings.ToLower(strings.Join(fields[3:], "")))
+	return m, errors.Join(errs[:]...)
+}
+
+var sshfpRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"algorithm":        types.Int64Type,
+	"fingerprint_type": types.Int64Type,
+	"fingerprint":      types.StringType,
+}}
+
+// SSHFPRecordModel describes an sshfp block of powerdns_record.
+type SSHFPRecordModel struct {
+	Algorithm       types.Int64  `tfsdk:"algorithm"`
+	FingerprintType types.Int64  `tfsdk:"fingerprint_type"`
+	Fingerprint     types.String `tfsdk:"fingerprint"`
+}
+
+func (m SSHFPRecordModel) content() string {
+	return fmt.Sprintf("%d %d %s", m.Algorithm.ValueInt64(), m.FingerprintType.ValueInt64(), strings.ToLower(m.Fingerprint.ValueString()))
+}
+
+func (m SSHFPRecordModel) check() error {
+	return errors.Join(
+		checkUint(m.Algorithm, "algorithm", 8),
+		checkUint(m.FingerprintType, "fingerprint_type", 8),
+		checkHex(m.Fingerprint, "fingerprint"),
+	)
+}
+
+func parseSSHFPContent(content string) (SSHFPRecordModel, error) {
+	var m SSHFPRecordModel
+	fields, err := contentFields(content, 3, -1)
+	if err != nil {
+		return m, err
+	}
+	var errs [2]error
+	m.Algorithm, errs[0] = parseUint(fields[0], 8)
+	m.FingerprintType, errs[1] = parseUint(fields[1], 8)
+	m.Fingerprint = types.StringValue(strings.ToLower(strings.Join(fields[2:], "")))
+	return m, errors.Join(errs[:]...)
+}
+
+var svcbRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"priority": types.Int64Type,
+	"target":   DNSNameType{},
+	"params":   types.MapType{ElemType: types.StringType},
+}}
+
+// svcbParamKeys maps the names of the SVCB parameters to their key numbers,
+// the order PowerDNS lists them in.
+var svcbParamKeys = map[string]int{
+	"mandatory":       0,
+	"alpn":            1,
+	"no-default-alpn": 2,
+	"port":            3,
+	"ipv4hint":        4,
+	"ech":             5,
+	"ipv6hint":        6,
+}
+
+// svcbParamKey returns the key number of an SVCB parameter, named either
+// after its mnemonic or as keyNNNNN.
+func svcbParamKey(name string) (int, bool) {
+	if key, ok := svcbParamKeys[name]; ok {
+		return key, true
+	}
+	if number, ok := strings.CutPrefix(name, "key"); ok {
+		key, err := strconv.ParseUint(number, 10, 16)
+		return int(key), err == nil
+	}
+	return 0, false
+}
+
+// SVCBRecordModel describes an svcb block of powerdns_record.
+type SVCBRecordModel struct {
+	Priority types.Int64  `tfsdk:"priority"`
+	Target   DNSNameValue `tfsdk:"target"`
+	Params   types.Map    `tfsdk:"params"`
+}
+
+func (m SVCBRecordModel) content() string {
+	params := m.Params.Elements()
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ki, _ := svcbParamKey(names[i])
+		kj, _ := svcbParamKey(names[j])
+		return ki < kj
+	})
+
+	content := fmt.Sprintf("%d %s", m.Priority.ValueInt64(), m.Target.FQDN())
+	for _, name := range names {
+		value, _ := params[name].(types.String)
+		if name == "ipv6hint" {
+			value = types.StringValue(canonicalIPv6Hint(value.ValueString()))
+		}
+		switch {
+		case value.ValueString() == "":
+			content += " " + name
+		case strings.ContainsAny(value.ValueString(), " \t\"\\"):
+			content += " " + name + "=" + quoteCharacterString(value.ValueString())
+		default:
+			content += " " + name + "=" + value.ValueString()
+		}
+	}
+	return content
+}
+
+func (m SVCBRecordModel) check() error {
+	errs := []error{checkUint(m.Priority, "priority", 16), checkHostName(m.Target, "target")}
+	// An empty map would be read back as a null one
+	if !m.Params.IsNull() && !m.Params.IsUnknown() && len(m.Params.Elements()) == 0 {
+		errs = append(errs, errors.New("params must not be empty, leave it out instead"))
+	}
+	for name, value := range m.Params.Elements() {
+		if _, ok := svcbParamKey(name); !ok {
+			errs = append(errs, fmt.Errorf("params: unknown SVCB parameter %q", name))
+		}
+		if hint, ok := value.(types.String); ok && name == "ipv6hint" && !hint.IsUnknown() {
+			errs = append(errs, checkIPv6Hint(hint.ValueString()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func parseSVCBContent(content string) (SVCBRecordModel, error) {
+	m := SVCBRecordModel{Params: types.MapNull(types.StringType)}
+	fields, err := contentFields(content, 2, -1)
+	if err != nil {
+		return m, err
+	}
+	m.Priority, err = parseUint(fields[0], 16)
+	if err != nil {
+		return m, err
+	}
+	m.Target = NewDNSNameValue(fields[1])
+	if len(fields) == 2 {
+		return m, nil
+	}
+
+	params := make(map[string]attr.Value, len(fields)-2)
+	for _, field := range fields[2:] {
+		name, value, _ := strings.Cut(field, "=")
+		if _, ok := svcbParamKey(name); !ok {
+			return m, fmt.Errorf("unknown SVCB parameter %q", name)
+		}
+		if _, ok := params[name]; ok {
+			return m, fmt.Errorf("duplicate SVCB parameter %q", name)
+		}
+		if name == "ipv6hint" {
+			value = canonicalIPv6Hint(value)
+		}
+		params[name] = types.StringValue(value)
+	}
+	m.Params = types.MapValueMust(types.StringType, params)
+	return m, nil
+}
+
+var txtRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"value": types.StringType,
+}}
+
+// TXTRecordModel describes a txt block of powerdns_record.
+type TXTRecordModel struct {
+	Value types.String `tfsdk:"value"`
+}
+
+func (m TXTRecordModel) content() string {
+	value := m.Value.ValueString()
+	if value == "" {
+		return `""`
+	}
+
+	var chunks []string
+	for len(value) > maxCharacterString {
+		chunks = append(chunks, quoteCharacterString(value[:maxCharacterString]))
+		value = value[maxCharacterString:]
+	}
+	if value != "" {
+		chunks = append(chunks, quoteCharacterString(value))
+	}
+	return strings.Join(chunks, " ")
+}
+
+func (m TXTRecordModel) check() error {
+	return nil
+}
+
+// parseTXTContent joins the strings of a TXT record. Records made of several
+// strings are only parsed when they are the split of a single long value, as
+// rendering them back wouldn't split them the same way otherwise.
+func parseTXTContent(content string) (TXTRecordModel, error) {
+	var m TXTRecordModel
+	fields, err := contentFields(content, 1, -1)
+	if err != nil {
+		return m, err
+	}
+	for _, field := range fields[:len(fields)-1] {
+		if len(field) != maxCharacterString {
+			return m, fmt.Errorf("made of several strings")
+		}
+	}
+	m.Value = types.StringValue(strings.Join(fields, ""))
+	return m, nil
+}
+
+// contentField is a field of record content.
+type contentField struct {
+	value  string
+	quoted bool
+}
+
+// splitContent splits record content into its whitespace separated fields,
+// removing quotes and resolving escapes.
+func splitContent(content string) ([]contentField, error) {
+	var fields []contentField
+	var field strings.Builder
+	inField, quoted, wasQuoted := false, false, false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\\':
+			if i+3 < len(content) && isDigits(content[i+1:i+4]) {
+				code, err := strconv.ParseUint(content[i+1:i+4], 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid escape \\%s", content[i+1:i+4])
+				}
+				field.WriteByte(byte(code))
+				i += 3
+			} else if i+1 < len(content) {
+				field.WriteByte(content[i+1])
+				i++
+			} else {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			inField = true
+		case c == '"':
+			quoted = !quoted
+			inField, wasQuoted = true, true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inField {
+				fields = append(fields, contentField{value: field.String(), quoted: wasQuoted})
+				field.Reset()
+				inField, wasQuoted = false, false
+			}
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+	if inField {
+		fields = append(fields, contentField{value: field.String(), quoted: wasQuoted})
+	}
+	return fields, nil
+}
+
+// contentFields returns the values of the fields of record content, checking
+// there are between minimum and maximum of them, maximum being -1 for no
+// limit.
+func contentFields(content string, minimum int, maximum int) ([]string, error) {
+	fields, err := splitContent(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < minimum || (maximum >= 0 && len(fields) > maximum) {
+		return nil, fmt.Errorf("unexpected number of fields %d", len(fields))
+	}
+
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		values = append(values, field.value)
+	}
+	return values, nil
+}
+
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// quoteCharacterString quotes s, escaping quotes, backslashes and the bytes
+// that aren't printable ASCII the way PowerDNS does.
+func quoteCharacterString(s string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&quoted, "\\%03d", c)
+		default:
+			quoted.WriteByte(c)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+func parseUint(field string, bits int) (types.Int64, error) {
+	value, err := strconv.ParseUint(field, 10, bits)
+	if err != nil {
+		return types.Int64Null(), fmt.Errorf("invalid number %q", field)
+	}
+	return types.Int64Value(int64(value)), nil
+}
+
+// checkUint checks that a known value fits an unsigned integer of the given
+// size.
+func checkUint(value types.Int64, name string, bits int) error {
+	maximum := int64(1)<<bits - 1
+	if value.IsNull() || value.IsUnknown() || (value.ValueInt64() >= 0 && value.ValueInt64() <= maximum) {
+		return nil
+	}
+	return fmt.Errorf("%s must be between 0 and %d, got %d", name, maximum, value.ValueInt64())
+}
+
+// checkHostName checks that a known value is a single host name.
+func checkHostName(value DNSNameValue, name string) error {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	if host := value.ValueString(); host == "" || strings.ContainsAny(host, " \t\"") {
+		return fmt.Errorf("%s must be a host name, got %q", name, host)
+	}
+	return nil
+}
+
+// lowerHex matches the hex encoding PowerDNS renders binary data in.
+var lowerHex = regexp.MustCompile(`^(?:[0-9a-f]{2})+$`)
+
+// checkHex checks that a known value is lowercase hex encoded, as PowerDNS
+// returns it.
+func checkHex(value types.String, name string) error {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	if !lowerHex.MatchString(value.ValueString()) {
+		return fmt.Errorf("%s must be lowercase hex encoded, got %q", name, value.ValueString())
+	}
+	return nil
+}
+
+// canonicalIPv6Hint rewrites the addresses of an ipv6hint SVCB parameter in
+// their canonical form, leaving those that don't parse as they are.
+func canonicalIPv6Hint(value string) string {
+	addrs := strings.Split(value, ",")
+	for i, addr := range addrs {
+		if ip, err := netip.ParseAddr(addr); err == nil {
+			addrs[i] = ip.String()
+		}
+	}
+	return strings.Join(addrs, ",")
+}
+
+// checkIPv6Hint checks that the addresses of an ipv6hint SVCB parameter are
+// IPv6 addresses in their canonical form, as PowerDNS returns them.
+func checkIPv6Hint(value string) error {
+	for _, addr := range strings.Split(value, ",") {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil || !ip.Is6() || ip.Zone() != "" {
+			return fmt.Errorf("params: ipv6hint must be a list of IPv6 addresses, got %q", value)
+		}
+		if ip.String() != addr {
+			return fmt.Errorf("params: ipv6hint address %q must be written %q", addr, ip.String())
+		}
+	}
+	return nil
+}
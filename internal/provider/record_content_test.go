@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordContent_RoundTrip(t *testing.T) {
+	longText := strings.Repeat("a", 300)
+
+	tests := []struct {
+		name    string
+		model   recordContent
+		content string
+		parse   func(string) (recordContent, error)
+	}{
+		{
+			name:    "mx",
+			model:   MXRecordModel{Preference: types.Int64Value(10), Exchange: NewDNSNameValue("mail.example.com.")},
+			content: "10 mail.example.com.",
+			parse:   func(s string) (recordContent, error) { return parseMXContent(s) },
+		},
+		{
+			name:    "srv",
+			model:   SRVRecordModel{Priority: types.Int64Value(10), Weight: types.Int64Value(60), Port: types.Int64Value(5060), Target: NewDNSNameValue("sip.example.com.")},
+			content: "10 60 5060 sip.example.com.",
+			parse:   func(s string) (recordContent, error) { return parseSRVContent(s) },
+		},
+		{
+			name:    "caa",
+			model:   CAARecordModel{Flags: types.Int64Value(0), Tag: types.StringValue("issue"), Value: types.StringValue("letsencrypt.org")},
+			content: `0 issue "letsencrypt.org"`,
+			parse:   func(s string) (recordContent, error) { return parseCAAContent(s) },
+		},
+		{
+			name:    "caa escaped",
+			model:   CAARecordModel{Flags: types.Int64Value(128), Tag: types.StringValue("iodef"), Value: types.StringValue(`mailto:"ca"@example.com`)},
+			content: `128 iodef "mailto:\"ca\"@example.com"`,
+			parse:   func(s string) (recordContent, error) { return parseCAAContent(s) },
+		},
+		{
+			name:    "tlsa",
+			model:   TLSARecordModel{Usage: types.Int64Value(3), Selector: types.Int64Value(1), MatchingType: types.Int64Value(1), CertificateData: types.StringValue("0123abcd")},
+			content: "3 1 1 0123abcd",
+			parse:   func(s string) (recordContent, error) { return parseTLSAContent(s) },
+		},
+		{
+			name:    "sshfp",
+			model:   SSHFPRecordModel{Algorithm: types.Int64Value(4), FingerprintType: types.Int64Value(2), Fingerprint: types.StringValue("abcdef0123")},
+			content: "4 2 abcdef0123",
+			parse:   func(s string) (recordContent, error) { return parseSSHFPContent(s) },
+		},
+		{
+			name: "svcb",
+			model: SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"port":            types.StringValue("8443"),
+				"alpn":            types.StringValue("h2,h3"),
+				"no-default-alpn": types.StringValue(""),
+			})},
+			content: "1 . alpn=h2,h3 no-default-alpn port=8443",
+			parse:   func(s string) (recordContent, error) { return parseSVCBContent(s) },
+		},
+		{
+			name: "svcb ipv6hint",
+			model: SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"ipv6hint": types.StringValue("2001:db8::1,2001:db8::2"),
+			})},
+			content: "1 . ipv6hint=2001:db8::1,2001:db8::2",
+			parse:   func(s string) (recordContent, error) { return parseSVCBContent(s) },
+		},
+		{
+			name:    "svcb alias",
+			model:   SVCBRecordModel{Priority: types.Int64Value(0), Target: NewDNSNameValue("svc.example.com."), Params: types.MapNull(types.StringType)},
+			content: "0 svc.example.com.",
+			parse:   func(s string) (recordContent, error) { return parseSVCBContent(s) },
+		},
+		{
+			name:    "txt",
+			model:   TXTRecordModel{Value: types.StringValue(`v=spf1 include:"x" -all`)},
+			content: `"v=spf1 include:\"x\" -all"`,
+			parse:   func(s string) (recordContent, error) { return parseTXTContent(s) },
+		},
+		{
+			name:    "txt non ASCII",
+			model:   TXTRecordModel{Value: types.StringValue("café")},
+			content: `"caf\195\169"`,
+			parse:   func(s string) (recordContent, error) { return parseTXTContent(s) },
+		},
+		{
+			name:    "txt long",
+			model:   TXTRecordModel{Value: types.StringValue(longText)},
+			content: `"` + longText[:255] + `" "` + longText[255:] + `"`,
+			parse:   func(s string) (recordContent, error) { return parseTXTContent(s) },
+		},
+		{
+			name:    "txt empty",
+			model:   TXTRecordModel{Value: types.StringValue("")},
+			content: `""`,
+			parse:   func(s string) (recordContent, error) { return parseTXTContent(s) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.model.check())
+			assert.Equal(t, tt.content, tt.model.content())
+
+			parsed, err := tt.parse(tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.model, parsed)
+		})
+	}
+}
+
+func TestRecordContent_Canonical(t *testing.T) {
+	// Host names are rendered with the trailing dot PowerDNS requires
+	mx := MXRecordModel{Preference: types.Int64Value(10), Exchange: NewDNSNameValue("mail.example.com")}
+	assert.Equal(t, "10 mail.example.com.", mx.content())
+	srv := SRVRecordModel{Priority: types.Int64Value(10), Weight: types.Int64Value(60), Port: types.Int64Value(5060), Target: NewDNSNameValue("sip.example.com")}
+	assert.Equal(t, "10 60 5060 sip.example.com.", srv.content())
+	alias := SVCBRecordModel{Priority: types.Int64Value(0), Target: NewDNSNameValue("svc.example.com"), Params: types.MapNull(types.StringType)}
+	assert.Equal(t, "0 svc.example.com.", alias.content())
+	self := SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapNull(types.StringType)}
+	assert.Equal(t, "1 .", self.content())
+
+	tlsa := TLSARecordModel{Usage: types.Int64Value(3), Selector: types.Int64Value(1), MatchingType: types.Int64Value(1), CertificateData: types.StringValue("0123ABCD")}
+	assert.Equal(t, "3 1 1 0123abcd", tlsa.content())
+	parsedTLSA, err := parseTLSAContent("3 1 1 0123ABCD")
+	require.NoError(t, err)
+	assert.Equal(t, types.StringValue("0123abcd"), parsedTLSA.CertificateData)
+
+	sshfp := SSHFPRecordModel{Algorithm: types.Int64Value(4), FingerprintType: types.Int64Value(2), Fingerprint: types.StringValue("ABCDEF0123")}
+	assert.Equal(t, "4 2 abcdef0123", sshfp.content())
+	parsedSSHFP, err := parseSSHFPContent("4 2 ABCDEF0123")
+	require.NoError(t, err)
+	assert.Equal(t, types.StringValue("abcdef0123"), parsedSSHFP.Fingerprint)
+
+	svcb := SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{
+		"ipv6hint": types.StringValue("2001:DB8:0::0001"),
+	})}
+	assert.Equal(t, "1 . ipv6hint=2001:db8::1", svcb.content())
+	parsedSVCB, err := parseSVCBContent("1 . ipv6hint=2001:0db8::0001")
+	require.NoError(t, err)
+	assert.Equal(t, types.MapValueMust(types.StringType, map[string]attr.Value{"ipv6hint": types.StringValue("2001:db8::1")}), parsedSVCB.Params)
+}
+
+func TestRecordContent_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(string) error
+		content string
+	}{
+		{name: "mx missing exchange", parse: func(s string) error { _, err := parseMXContent(s); return err }, content: "10"},
+		{name: "mx preference too large", parse: func(s string) error { _, err := parseMXContent(s); return err }, content: "65536 mail.example.com."},
+		{name: "srv not a number", parse: func(s string) error { _, err := parseSRVContent(s); return err }, content: "10 sixty 5060 sip.example.com."},
+		{name: "caa unterminated", parse: func(s string) error { _, err := parseCAAContent(s); return err }, content: `0 issue "letsencrypt.org`},
+		{name: "svcb unknown parameter", parse: func(s string) error { _, err := parseSVCBContent(s); return err }, content: "1 . color=blue"},
+		{name: "svcb duplicate parameter", parse: func(s string) error { _, err := parseSVCBContent(s); return err }, content: "1 . port=1 port=2"},
+		{name: "txt several strings", parse: func(s string) error { _, err := parseTXTContent(s); return err }, content: `"a" "b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.parse(tt.content))
+		})
+	}
+}
+
+func TestRecordContent_Check(t *testing.T) {
+	tests := []struct {
+		name          string
+		model         recordContent
+		expectedError string
+	}{
+		{
+			name:          "mx preference",
+			model:         MXRecordModel{Preference: types.Int64Value(-1), Exchange: NewDNSNameValue("mail.example.com.")},
+			expectedError: "preference must be between 0 and 65535, got -1",
+		},
+		{
+			name:          "caa tag",
+			model:         CAARecordModel{Flags: types.Int64Value(0), Tag: types.StringValue("is sue"), Value: types.StringValue("ca.example.net")},
+			expectedError: `tag must be made of letters and digits only, got "is sue"`,
+		},
+		{
+			name:          "tlsa certificate data",
+			model:         TLSARecordModel{Usage: types.Int64Value(3), Selector: types.Int64Value(1), MatchingType: types.Int64Value(1), CertificateData: types.StringValue("xyz")},
+			expectedError: `certificate_data must be lowercase hex encoded, got "xyz"`,
+		},
+		{
+			name:          "tlsa uppercase certificate data",
+			model:         TLSARecordModel{Usage: types.Int64Value(3), Selector: types.Int64Value(1), MatchingType: types.Int64Value(1), CertificateData: types.StringValue("0123ABCD")},
+			expectedError: `certificate_data must be lowercase hex encoded, got "0123ABCD"`,
+		},
+		{
+			name:          "sshfp odd fingerprint",
+			model:         SSHFPRecordModel{Algorithm: types.Int64Value(4), FingerprintType: types.Int64Value(2), Fingerprint: types.StringValue("abc")},
+			expectedError: `fingerprint must be lowercase hex encoded, got "abc"`,
+		},
+		{
+			name:          "sshfp uppercase fingerprint",
+			model:         SSHFPRecordModel{Algorithm: types.Int64Value(4), FingerprintType: types.Int64Value(2), Fingerprint: types.StringValue("ABCDEF0123")},
+			expectedError: `fingerprint must be lowercase hex encoded, got "ABCDEF0123"`,
+		},
+		{
+			name:          "svcb empty params",
+			model:         SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{})},
+			expectedError: "params must not be empty, leave it out instead",
+		},
+		{
+			name:          "svcb ipv6hint not canonical",
+			model:         SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{"ipv6hint": types.StringValue("2001:db8::1,2001:DB8:0::0002")})},
+			expectedError: `params: ipv6hint address "2001:DB8:0::0002" must be written "2001:db8::2"`,
+		},
+		{
+			name:          "svcb ipv6hint not IPv6",
+			model:         SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{"ipv6hint": types.StringValue("192.0.2.1")})},
+			expectedError: `params: ipv6hint must be a list of IPv6 addresses, got "192.0.2.1"`,
+		},
+		{
+			name:          "svcb parameter",
+			model:         SVCBRecordModel{Priority: types.Int64Value(1), Target: NewDNSNameValue("."), Params: types.MapValueMust(types.StringType, map[string]attr.Value{"color": types.StringValue("blue")})},
+			expectedError: `params: unknown SVCB parameter "color"`,
+		},
+		{
+			name:  "unknown values",
+			model: SRVRecordModel{Priority: types.Int64Unknown(), Weight: types.Int64Value(0), Port: types.Int64Unknown(), Target: DNSNameValue{StringValue: types.StringUnknown()}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.check()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestRecordContent_Blocks(t *testing.T) {
+	ctx := context.Background()
+	block, ok := typedRecordBlockFor("https")
+	require.True(t, ok)
+	assert.Equal(t, "svcb", block.name)
+	_, ok = typedRecordBlockFor("A")
+	assert.False(t, ok)
+
+	mx, ok := typedRecordBlockFor("MX")
+	require.True(t, ok)
+	set, err := mx.blocks(ctx, []string{"10 mail1.example.com.", "20 mail2.example.com."})
+	require.NoError(t, err)
+	contents, diags := mx.contents(ctx, set)
+	require.False(t, diags.HasError())
+	assert.ElementsMatch(t, []string{"10 mail1.example.com.", "20 mail2.example.com."}, contents)
+
+	_, err = mx.blocks(ctx, []string{"10 mail1.example.com.", "mail2.example.com."})
+	assert.ErrorContains(t, err, `can't parse "mail2.example.com."`)
+}
@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the implementations satisfy the expected interfaces.
+var _ basetypes.StringTypable = DNSNameType{}
+var _ basetypes.StringValuableWithSemanticEquals = DNSNameValue{}
+var _ basetypes.StringTypable = RecordContentType{}
+var _ basetypes.StringValuableWithSemanticEquals = RecordContentValue{}
+
+// DNSNameType is the type of attributes holding DNS names, which PowerDNS
+// lowercases and makes absolute with a trailing dot.
+type DNSNameType struct {
+	basetypes.StringType
+}
+
+func (t DNSNameType) String() string {
+	return "DNSNameType"
+}
+
+func (t DNSNameType) ValueType(ctx context.Context) attr.Value {
+	return DNSNameValue{}
+}
+
+func (t DNSNameType) Equal(o attr.Type) bool {
+	other, ok := o.(DNSNameType)
+	return ok && t.StringType.Equal(other.StringType)
+}
+
+func (t DNSNameType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return DNSNameValue{StringValue: in}, nil
+}
+
+func (t DNSNameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	return stringValuableFromTerraform(ctx, t.StringType, t, in)
+}
+
+// DNSNameValue is a DNS name, equal to the same name in another case or
+// without the trailing dot.
+type DNSNameValue struct {
+	basetypes.StringValue
+}
+
+// NewDNSNameValue returns a known DNSNameValue.
+func NewDNSNameValue(name string) DNSNameValue {
+	return DNSNameValue{StringValue: basetypes.NewStringValue(name)}
+}
+
+func (v DNSNameValue) Type(ctx context.Context) attr.Type {
+	return DNSNameType{}
+}
+
+func (v DNSNameValue) Equal(o attr.Value) bool {
+	other, ok := o.(DNSNameValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+func (v DNSNameValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(DNSNameValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected value type %T, got %T", v, newValuable))
+		return false, diags
+	}
+	return sameDNSName(v.ValueString(), newValue.ValueString()), diags
+}
+
+// FQDN returns the name with its trailing dot, the form PowerDNS requires.
+func (v DNSNameValue) FQDN() string {
+	return strings.TrimSuffix(v.ValueString(), ".") + "."
+}
+
+// RecordContentType is the type of attributes holding record content, which
+// PowerDNS stores in a canonical form depending on the type of the record.
+type RecordContentType struct {
+	basetypes.StringType
+}
+
+func (t RecordContentType) String() string {
+	return "RecordContentType"
+}
+
+func (t RecordContentType) ValueType(ctx context.Context) attr.Value {
+	return RecordContentValue{}
+}
+
+func (t RecordContentType) Equal(o attr.Type) bool {
+	other, ok := o.(RecordContentType)
+	return ok && t.StringType.Equal(other.StringType)
+}
+
+func (t RecordContentType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return RecordContentValue{StringValue: in}, nil
+}
+
+func (t RecordContentType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	return stringValuableFromTerraform(ctx, t.StringType, t, in)
+}
+
+// RecordContentValue is the content of a record, semantically equal to the
+// canonical form PowerDNS turns it into for the type of the record. Only the
+// values built for records read from the server know that type: the values
+// the framework rebuilds from Terraform data don't, and are only equal when
+// identical. powerdns_record thus compares the contents it reads to the prior
+// ones itself, see keepContents.
+type RecordContentValue struct {
+	basetypes.StringValue
+	recordType string
+}
+
+// NewRecordContentValue returns a known RecordContentValue.
+func NewRecordContentValue(content string) RecordContentValue {
+	return RecordContentValue{StringValue: basetypes.NewStringValue(content)}
+}
+
+// newRecordContentValueOfType returns a known RecordContentValue of a record
+// of type tpe.
+func newRecordContentValueOfType(tpe, content string) RecordContentValue {
+	return RecordContentValue{StringValue: basetypes.NewStringValue(content), recordType: strings.ToUpper(tpe)}
+}
+
+func (v RecordContentValue) Type(ctx context.Context) attr.Type {
+	return RecordContentType{}
+}
+
+func (v RecordContentValue) Equal(o attr.Value) bool {
+	other, ok := o.(RecordContentValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+func (v RecordContentValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(RecordContentValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected value type %T, got %T", v, newValuable))
+		return false, diags
+	}
+	tpe := v.recordType
+	if tpe == "" {
+		tpe = newValue.recordType
+	}
+	return sameRecordContent(tpe, v.ValueString(), newValue.ValueString()), diags
+}
+
+// stringValuableFromTerraform converts a tftypes.Value to a value of the
+// custom string type t, based on the basetypes.StringType base.
+func stringValuableFromTerraform(ctx context.Context, base basetypes.StringType, t basetypes.StringTypable, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := base.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+// keepContents returns contents, read from the server for records of type
+// tpe, with the contents semantically equal to one of prior replaced by it, so
+// that the canonical forms PowerDNS stores don't show up as changes.
+// Identical contents are matched first.
+func keepContents(ctx context.Context, tpe string, prior, contents []string) []string {
+	kept := slices.Clone(contents)
+	matched := make([]bool, len(contents))
+	used := make([]bool, len(prior))
+	for _, same := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		func(a, b string) bool {
+			equal, _ := newRecordContentValueOfType(tpe, b).StringSemanticEquals(ctx, NewRecordContentValue(a))
+			return equal
+		},
+	} {
+		for i, content := range contents {
+			for j, priorContent := range prior {
+				if !matched[i] && !used[j] && same(priorContent, content) {
+					kept[i], matched[i], used[j] = priorContent, true, true
+				}
+			}
+		}
+	}
+	return kept
+}
+
+// contentFieldKind is how PowerDNS canonicalizes a field of record content.
+type contentFieldKind int
+
+const (
+	// opaqueField is kept as is.
+	opaqueField contentFieldKind = iota
+	// numberField is stored without leading zeros.
+	numberField
+	// addressField is stored in the canonical form of the address, PowerDNS
+	// compressing IPv6 addresses.
+	addressField
+	// hostNameField is stored lowercased, with a trailing dot.
+	hostNameField
+	// stringField is a character string, re-quoted and re-escaped.
+	stringField
+)
+
+// recordContentFields lists the kinds of the fields of the content of the
+// record types PowerDNS canonicalizes, the last kind applying to the
+// remaining fields. The content of the other types is kept as is.
+var recordContentFields = map[string][]contentFieldKind{
+	"A":     {addressField},
+	"AAAA":  {addressField},
+	"ALIAS": {hostNameField},
+	"CNAME": {hostNameField},
+	"DNAME": {hostNameField},
+	"NS":    {hostNameField},
+	"PTR":   {hostNameField},
+	"MX":    {numberField, hostNameField},
+	"SRV":   {numberField, numberField, numberField, hostNameField},
+	"SVCB":  {numberField, hostNameField, opaqueField},
+	"HTTPS": {numberField, hostNameField, opaqueField},
+	"CAA":   {numberField, opaqueField, stringField},
+	"SPF":   {stringField},
+	"TXT":   {stringField},
+}
+
+// sameRecordContent reports whether a and b are the same content of a record
+// of type tpe, as PowerDNS would store it. For the types listed in
+// recordContentFields, the contents are compared field by field, according
+// to the kind of each field:
+//   - numbers, such as MX preferences or SRV ports, are equal when they have
+//     the same value;
+//   - addresses, of A and AAAA records, are equal when they are the same
+//     address;
+//   - host names, of CNAME, NS, MX, SRV or SVCB records for instance, are
+//     equal ignoring case and the trailing dot;
+//   - strings, of TXT and SPF records or the values of CAA records, are equal
+//     when their unescaped values are;
+//   - other fields are only equal when they are identical.
+//
+// The contents of the other types, such as the keys and hashes of DNSKEY,
+// SSHFP or TLSA records, are only equal when they are identical.
+func sameRecordContent(tpe, a, b string) bool {
+	if a == b {
+		return true
+	}
+	kinds, ok := recordContentFields[strings.ToUpper(tpe)]
+	if !ok {
+		return false
+	}
+	fieldsA, errA := splitContent(a)
+	fieldsB, errB := splitContent(b)
+	if errA != nil || errB != nil || len(fieldsA) != len(fieldsB) {
+		return false
+	}
+
+	for i := range fieldsA {
+		kind := kinds[min(i, len(kinds)-1)]
+		if !sameContentField(kind, fieldsA[i], fieldsB[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameContentField reports whether two fields of record content of the given
+// kind are the same, as described by sameRecordContent.
+func sameContentField(kind contentFieldKind, a, b contentField) bool {
+	if kind == stringField {
+		return a.value == b.value
+	}
+	if a.quoted || b.quoted {
+		return a == b
+	}
+	switch kind {
+	case numberField:
+		numberA, errA := strconv.ParseUint(a.value, 10, 64)
+		numberB, errB := strconv.ParseUint(b.value, 10, 64)
+		return errA == nil && errB == nil && numberA == numberB
+	case addressField:
+		addrA, errA := netip.ParseAddr(a.value)
+		addrB, errB := netip.ParseAddr(b.value)
+		return errA == nil && errB == nil && addrA == addrB
+	case hostNameField:
+		return sameDNSName(a.value, b.value)
+	default:
+		return a.value == b.value
+	}
+}
+
+// canonicalRecordContent returns content, of a record of type tpe, in the
+// form PowerDNS expects for the types listed in recordContentFields: numbers
+// without leading zeros, addresses in their canonical form, absolute host
+// names and quoted strings. Other contents, and contents that don't split in
+// fields, are returned as they are.
+func canonicalRecordContent(tpe, content string) string {
+	kinds, ok := recordContentFields[strings.ToUpper(tpe)]
+	if !ok {
+		return content
+	}
+
+	var canonical []string
+	if slices.Contains(kinds, stringField) {
+		fields, err := splitContent(content)
+		if err != nil || len(fields) == 0 {
+			return content
+		}
+		for i, field := range fields {
+			kind := kinds[min(i, len(kinds)-1)]
+			if kind == stringField || field.quoted {
+				canonical = append(canonical, quoteCharacterString(field.value))
+			} else {
+				canonical = append(canonical, canonicalContentField(kind, field.value))
+			}
+		}
+		return strings.Join(canonical, " ")
+	}
+
+	// The other types don't quote their fields, but the parameters of SVCB
+	// records may, which are kept as they are
+	rest := content
+	for i := 0; ; i++ {
+		kind := kinds[min(i, len(kinds)-1)]
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+		if kind == opaqueField {
+			canonical = append(canonical, rest)
+			break
+		}
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		canonical = append(canonical, canonicalContentField(kind, rest[:end]))
+		rest = rest[end:]
+	}
+	if len(canonical) == 0 {
+		return content
+	}
+	return strings.Join(canonical, " ")
+}
+
+// canonicalContentField returns an unquoted field of record content of the
+// given kind in the form PowerDNS expects, as described by
+// canonicalRecordContent.
+func canonicalContentField(kind contentFieldKind, field string) string {
+	switch kind {
+	case numberField:
+		if number, err := strconv.ParseUint(field, 10, 64); err == nil {
+			return strconv.FormatUint(number, 10)
+		}
+	case addressField:
+		if addr, err := netip.ParseAddr(field); err == nil {
+			return addr.String()
+		}
+	case hostNameField:
+		return strings.TrimSuffix(field, ".") + "."
+	}
+	return field
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordTypes_SameRecordContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		tpe      string
+		config   string
+		server   string
+		expected bool
+	}{
+		{name: "identical", tpe: "A", config: "192.0.2.1", server: "192.0.2.1", expected: true},
+		{name: "IPv6 compressed", tpe: "AAAA", config: "2001:0DB8:0000:0000:0000:0000:0000:0001", server: "2001:db8::1", expected: true},
+		{name: "different address", tpe: "A", config: "192.0.2.1", server: "192.0.2.2"},
+		{name: "address trailing dot", tpe: "A", config: "1.2.3.4", server: "1.2.3.4."},
+		{name: "type case", tpe: "aaaa", config: "2001:db8:0::1", server: "2001:db8::1", expected: true},
+		{name: "host name trailing dot", tpe: "CNAME", config: "target.example.com", server: "target.example.com.", expected: true},
+		{name: "host name case", tpe: "CNAME", config: "Target.Example.com.", server: "target.example.com.", expected: true},
+		{name: "different host name", tpe: "NS", config: "target.example.com.", server: "target.example.org."},
+		{name: "MX", tpe: "MX", config: "10 Mail.example.com", server: "10 mail.example.com.", expected: true},
+		{name: "MX preference", tpe: "MX", config: "10 mail.example.com.", server: "20 mail.example.com."},
+		{name: "SRV leading zero", tpe: "SRV", config: "010 60 5060 sip.example.com", server: "10 60 5060 sip.example.com.", expected: true},
+		{name: "TXT re-quoted", tpe: "TXT", config: "v=spf1", server: `"v=spf1"`, expected: true},
+		{name: "TXT re-escaped", tpe: "TXT", config: `"café"`, server: `"caf\195\169"`, expected: true},
+		{name: "TXT case", tpe: "TXT", config: `"Hello"`, server: `"hello"`},
+		{name: "TXT strings", tpe: "TXT", config: `"a b"`, server: `"a" "b"`},
+		{name: "TXT leading zero", tpe: "TXT", config: "10", server: "010"},
+		{name: "TXT trailing dot", tpe: "TXT", config: `"example.com"`, server: `"example.com."`},
+		{name: "CAA", tpe: "CAA", config: `0 issue "letsencrypt.org"`, server: `0 issue "letsencrypt.org"`, expected: true},
+		{name: "CAA value case", tpe: "CAA", config: `0 issue "LetsEncrypt.org"`, server: `0 issue "letsencrypt.org"`},
+		{name: "CAA tag case", tpe: "CAA", config: `0 ISSUE "letsencrypt.org"`, server: `0 issue "letsencrypt.org"`},
+		{name: "field count", tpe: "MX", config: "10 mail.example.com.", server: "mail.example.com."},
+		{name: "unterminated quote", tpe: "TXT", config: `"abc`, server: `"abc"`},
+		{name: "DNSKEY key case", tpe: "DNSKEY", config: "257 3 13 AbCd", server: "257 3 13 abcd"},
+		{name: "DNSKEY leading zero", tpe: "DNSKEY", config: "0257 3 13 AbCd", server: "257 3 13 AbCd"},
+		{name: "OPENPGPKEY case", tpe: "OPENPGPKEY", config: "mQENBFxyz", server: "mqenbfxyz"},
+		{name: "SSHFP case", tpe: "SSHFP", config: "4 2 ABCDEF", server: "4 2 abcdef"},
+		{name: "TLSA case", tpe: "TLSA", config: "3 1 1 0123ABCD", server: "3 1 1 0123abcd"},
+		{name: "unknown type trailing dot", tpe: "LOC", config: "1.2.3.4", server: "1.2.3.4."},
+		{name: "unknown type leading zero", tpe: "TYPE65534", config: "10", server: "010"},
+		{name: "no type", config: "2001:0db8::0001", server: "2001:db8::1"},
+		{name: "HTTPS target", tpe: "HTTPS", config: "1 svc.example.com alpn=h2", server: "1 svc.example.com. alpn=h2", expected: true},
+		{name: "HTTPS params", tpe: "HTTPS", config: "1 . alpn=H2", server: "1 . alpn=h2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sameRecordContent(tt.tpe, tt.config, tt.server))
+		})
+	}
+}
+
+func TestRecordTypes_CanonicalRecordContent(t *testing.T) {
+	tests := []struct {
+		tpe       string
+		content   string
+		canonical string
+	}{
+		{tpe: "AAAA", content: "2001:0DB8:0000:0000:0000:0000:0000:0001", canonical: "2001:db8::1"},
+		{tpe: "a", content: "192.0.2.1", canonical: "192.0.2.1"},
+		{tpe: "CNAME", content: "Target.example.com", canonical: "Target.example.com."},
+		{tpe: "MX", content: "010  mail.example.com", canonical: "10 mail.example.com."},
+		{tpe: "SRV", content: "10 60 5060 sip.example.com.", canonical: "10 60 5060 sip.example.com."},
+		{tpe: "HTTPS", content: "1 svc.example.com alpn=h2", canonical: "1 svc.example.com. alpn=h2"},
+		{tpe: "SVCB", content: "01\tsvc.example.com  alpn=\"h2,h3\"  port=443", canonical: "1 svc.example.com. alpn=\"h2,h3\"  port=443"},
+		{tpe: "SVCB", content: "0 svc.example.com", canonical: "0 svc.example.com."},
+		{tpe: "TXT", content: "v=spf1", canonical: `"v=spf1"`},
+		{tpe: "TXT", content: `"café"`, canonical: `"caf\195\169"`},
+		{tpe: "CAA", content: `0 issue "letsencrypt.org"`, canonical: `0 issue "letsencrypt.org"`},
+		{tpe: "TLSA", content: "3 1 1 0123ABCD", canonical: "3 1 1 0123ABCD"},
+		{tpe: "AAAA", content: "not an address", canonical: "not an address"},
+		{tpe: "TXT", content: `"unterminated`, canonical: `"unterminated`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tpe+" "+tt.content, func(t *testing.T) {
+			assert.Equal(t, tt.canonical, canonicalRecordContent(tt.tpe, tt.content))
+		})
+	}
+}
+
+func TestRecordTypes_KeepContents(t *testing.T) {
+	ctx := context.Background()
+	prior := []string{"2001:0db8::0001", "2001:db8::2"}
+	server := []string{"2001:db8::1", "2001:db8::2", "2001:db8::3"}
+	assert.Equal(t, []string{"2001:0db8::0001", "2001:db8::2", "2001:db8::3"}, keepContents(ctx, "AAAA", prior, server))
+
+	// Identical contents are matched before semantically equal ones
+	assert.Equal(t, []string{"10 mail.example.com.", "010 mail.example.com"}, keepContents(ctx, "MX", []string{"010 mail.example.com", "10 mail.example.com."}, []string{"10 mail.example.com.", "10 mail.example.com"}))
+
+	assert.Equal(t, []string{"3 1 1 0123abcd"}, keepContents(ctx, "TLSA", []string{"3 1 1 0123ABCD"}, []string{"3 1 1 0123abcd"}))
+	assert.Equal(t, []string{"1.2.3.4."}, keepContents(ctx, "LOC", []string{"1.2.3.4"}, []string{"1.2.3.4."}))
+}
+
+func TestRecordTypes_SemanticEquals(t *testing.T) {
+	ctx := context.Background()
+
+	equal, diags := NewDNSNameValue("www.example.com.").StringSemanticEquals(ctx, NewDNSNameValue("WWW.example.com"))
+	require.False(t, diags.HasError())
+	assert.True(t, equal)
+
+	equal, diags = NewDNSNameValue("www.example.com.").StringSemanticEquals(ctx, NewDNSNameValue("mail.example.com."))
+	require.False(t, diags.HasError())
+	assert.False(t, equal)
+
+	_, diags = NewDNSNameValue("www.example.com.").StringSemanticEquals(ctx, types.StringValue("www.example.com."))
+	assert.True(t, diags.HasError())
+
+	// Record contents are compared according to the type of the value read
+	// from the server, whichever side it is on
+	equal, diags = newRecordContentValueOfType("AAAA", "2001:db8::1").StringSemanticEquals(ctx, NewRecordContentValue("2001:0db8::0001"))
+	require.False(t, diags.HasError())
+	assert.True(t, equal)
+
+	equal, diags = NewRecordContentValue("Target.example.com").StringSemanticEquals(ctx, newRecordContentValueOfType("cname", "target.example.com."))
+	require.False(t, diags.HasError())
+	assert.True(t, equal)
+
+	equal, diags = newRecordContentValueOfType("TLSA", "3 1 1 0123abcd").StringSemanticEquals(ctx, NewRecordContentValue("3 1 1 0123ABCD"))
+	require.False(t, diags.HasError())
+	assert.False(t, equal)
+
+	// Without a type, only identical contents are equal
+	equal, diags = NewRecordContentValue("2001:db8::1").StringSemanticEquals(ctx, NewRecordContentValue("2001:0db8::0001"))
+	require.False(t, diags.HasError())
+	assert.False(t, equal)
+
+	_, diags = NewRecordContentValue("192.0.2.1").StringSemanticEquals(ctx, types.StringValue("192.0.2.1"))
+	assert.True(t, diags.HasError())
+}
+
+func TestRecordTypes_FQDN(t *testing.T) {
+	assert.Equal(t, "www.example.com.", NewDNSNameValue("www.example.com").FQDN())
+	assert.Equal(t, "www.example.com.", NewDNSNameValue("www.example.com.").FQDN())
+}
+
+func TestRecordTypes_ValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+	tfValue, err := NewRecordContentValue("192.0.2.1").ToTerraformValue(ctx)
+	require.NoError(t, err)
+
+	value, err := RecordContentType{}.ValueFromTerraform(ctx, tfValue)
+	require.NoError(t, err)
+	assert.Equal(t, NewRecordContentValue("192.0.2.1"), value)
+
+	value, err = DNSNameType{}.ValueFromTerraform(ctx, tfValue)
+	require.NoError(t, err)
+	assert.Equal(t, NewDNSNameValue("192.0.2.1"), value)
+}
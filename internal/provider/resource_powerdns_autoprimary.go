@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.Resource = &AutoprimaryResource{}
+var _ resource.ResourceWithImportState = &AutoprimaryResource{}
+var _ resource.ResourceWithModifyPlan = &AutoprimaryResource{}
+
+// AutoprimaryResource defines the resource implementation.
+type AutoprimaryResource struct {
+	client *Client
+}
+
+// autoprimaryValidationHints maps keywords of PowerDNS validation errors to
+// the autoprimary attribute they are about.
+var autoprimaryValidationHints = map[string]path.Path{
+	"ip":         path.Root("ip"),
+	"address":    path.Root("ip"),
+	"nameserver": path.Root("nameserver"),
+	"account":    path.Root("account"),
+}
+
+// AutoprimaryResourceModel describes the resource data model.
+type AutoprimaryResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	IP         types.String `tfsdk:"ip"`
+	Nameserver types.String `tfsdk:"nameserver"`
+	Account    types.String `tfsdk:"account"`
+	Timeouts   types.Object `tfsdk:"timeouts"`
+}
+
+// IPAddressValidator implements a custom validator for IP addresses.
+type IPAddressValidator struct{}
+
+func (v IPAddressValidator) Description(ctx context.Context) string {
+	return "Validates that the value is an IPv4 or IPv6 address"
+}
+
+func (v IPAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return "Validates that the value is an IPv4 or IPv6 address"
+}
+
+func (v IPAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if net.ParseIP(req.ConfigValue.ValueString()) == nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid IP address",
+			fmt.Sprintf("%q is not a valid IPv4 or IPv6 address", req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+func (r *AutoprimaryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_autoprimary"
+}
+
+func (r *AutoprimaryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an autoprimary, a primary server allowed to provision zones on this server by sending NOTIFY.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Autoprimary identifier, made of the IP address and the nameserver separated by `/`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ip": schema.StringAttribute{
+				MarkdownDescription: "The IP address of the primary server",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					IPAddressValidator{},
+				},
+			},
+			"nameserver": schema.StringAttribute{
+				MarkdownDescription: "The nameserver of the primary server, as found in the NS records of the zones it provisions",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"account": schema.StringAttribute{
+				MarkdownDescription: "The account owning the zones provisioned by the primary server",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}
+}
+
+func (r *AutoprimaryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type", "Expected *Client")
+		return
+	}
+	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_autoprimary")
+}
+
+func (r *AutoprimaryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		r.client.requireCapability(&resp.Diagnostics, capabilityAutoprimaries, path.Root("ip"))
+	}
+}
+
+func (r *AutoprimaryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AutoprimaryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	autoprimary := Autoprimary{
+		IP:         data.IP.ValueString(),
+		Nameserver: data.Nameserver.ValueString(),
+		Account:    data.Account.ValueString(),
+	}
+
+	ctx = tflog.SetField(ctx, "ip", autoprimary.IP)
+	ctx = tflog.SetField(ctx, "nameserver", autoprimary.Nameserver)
+	tflog.Debug(ctx, "Creating PowerDNS autoprimary")
+
+	if err := r.client.CreateAutoprimary(ctx, autoprimary); err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create autoprimary", fmt.Errorf("error creating PowerDNS autoprimary: %w", err), path.Root("ip"), autoprimaryValidationHints)
+		return
+	}
+
+	// PowerDNS doesn't return the autoprimary it created
+	created, err := r.client.GetAutoprimary(ctx, autoprimary.IP, autoprimary.Nameserver)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read created autoprimary", fmt.Errorf("couldn't fetch PowerDNS autoprimary: %w", err).Error())
+		return
+	}
+
+	data.ID = types.StringValue(autoprimaryID(autoprimary.IP, autoprimary.Nameserver))
+	data.Account = types.StringValue(created.Account)
+	tflog.Info(ctx, "Created PowerDNS autoprimary")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AutoprimaryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AutoprimaryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	ctx = tflog.SetField(ctx, "autoprimary_id", data.ID.ValueString())
+	tflog.Debug(ctx, "Reading PowerDNS autoprimary")
+
+	autoprimary, err := r.client.GetAutoprimary(ctx, data.IP.ValueString(), data.Nameserver.ValueString())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "PowerDNS autoprimary not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read autoprimary", fmt.Errorf("couldn't fetch PowerDNS autoprimary: %w", err).Error())
+		return
+	}
+
+	// The IP and nameserver keep their configured spelling, the server
+	// normalizing them
+	data.Account = types.StringValue(autoprimary.Account)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AutoprimaryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All the attributes force a new resource, PowerDNS can't change an
+	// autoprimary in place, so only the timeouts get here
+	var data AutoprimaryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AutoprimaryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AutoprimaryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	ctx = tflog.SetField(ctx, "autoprimary_id", data.ID.ValueString())
+	tflog.Debug(ctx, "Deleting PowerDNS autoprimary")
+
+	if err := r.client.DeleteAutoprimary(ctx, data.IP.ValueString(), data.Nameserver.ValueString()); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "PowerDNS autoprimary already deleted")
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete autoprimary", fmt.Errorf("error deleting PowerDNS autoprimary: %w", err).Error())
+		return
+	}
+
+	tflog.Info(ctx, "Deleted PowerDNS autoprimary")
+}
+
+func (r *AutoprimaryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tflog.Info(ctx, "Importing PowerDNS autoprimary", map[string]any{"id": req.ID})
+
+	ip, nameserver, err := parseAutoprimaryID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ip"), ip)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("nameserver"), nameserver)...)
+}
+
+// autoprimaryID returns the resource ID of the autoprimary of the given IP
+// and nameserver.
+func autoprimaryID(ip string, nameserver string) string {
+	return ip + "/" + nameserver
+}
+
+// parseAutoprimaryID splits a resource ID built by autoprimaryID.
+func parseAutoprimaryID(id string) (string, string, error) {
+	ip, nameserver, found := strings.Cut(id, "/")
+	if !found || ip == "" || nameserver == "" {
+		return "", "", fmt.Errorf("expected an ID of the form <ip>/<nameserver>, got %q", id)
+	}
+	if net.ParseIP(ip) == nil {
+		return "", "", fmt.Errorf("%q is not a valid IP address", ip)
+	}
+	return ip, nameserver, nil
+}
+
+func NewAutoprimaryResource() resource.Resource {
+	return &AutoprimaryResource{}
+}
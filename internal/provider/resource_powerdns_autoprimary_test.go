@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccPDNSAutoprimary_basic(t *testing.T) {
+	resourceName := "powerdns_autoprimary.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPDNSAutoprimaryConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "192.0.2.53/ns1.primary.sysa.abc."),
+					resource.TestCheckResourceAttr(resourceName, "ip", "192.0.2.53"),
+					resource.TestCheckResourceAttr(resourceName, "nameserver", "ns1.primary.sysa.abc."),
+					resource.TestCheckResourceAttr(resourceName, "account", "dns-team"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+const testAccPDNSAutoprimaryConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_autoprimary" "test" {
+	ip         = "192.0.2.53"
+	nameserver = "ns1.primary.sysa.abc."
+	account    = "dns-team"
+}`
+
+func TestAutoprimary_Resource(t *testing.T) {
+	server := pdnstest.New(t)
+	expected := pdnstest.Autoprimary{IP: "192.0.2.53", Nameserver: "ns1.primary.sysa.abc.", Account: "dns-team"}
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		CheckDestroy: func(*terraform.State) error {
+			if autoprimaries := server.Autoprimaries(); len(autoprimaries) != 0 {
+				return fmt.Errorf("expected no autoprimaries, got %+v", autoprimaries)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccPDNSAutoprimaryConfig),
+				Check: func(*terraform.State) error {
+					autoprimaries := server.Autoprimaries()
+					if len(autoprimaries) != 1 || autoprimaries[0] != expected {
+						return fmt.Errorf("expected autoprimary %+v, got %+v", expected, autoprimaries)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:            "powerdns_autoprimary.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestAutoprimary_ParseAutoprimaryID(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		expectedIP         string
+		expectedNameserver string
+		expectError        bool
+	}{
+		{name: "IPv4", id: "192.0.2.1/ns1.example.com.", expectedIP: "192.0.2.1", expectedNameserver: "ns1.example.com."},
+		{name: "IPv6", id: "2001:db8::1/ns1.example.com.", expectedIP: "2001:db8::1", expectedNameserver: "ns1.example.com."},
+		{name: "missing nameserver", id: "192.0.2.1/", expectError: true},
+		{name: "no separator", id: "192.0.2.1", expectError: true},
+		{name: "invalid IP", id: "primary/ns1.example.com.", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, nameserver, err := parseAutoprimaryID(tt.id)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIP, ip)
+			assert.Equal(t, tt.expectedNameserver, nameserver)
+			assert.Equal(t, tt.id, autoprimaryID(ip, nameserver))
+		})
+	}
+}
+
+func TestAutoprimary_IPAddressValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{name: "IPv4", value: types.StringValue("192.0.2.1")},
+		{name: "IPv6", value: types.StringValue("2001:db8::1")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "hostname", value: types.StringValue("primary.example.com"), expectError: true},
+		{name: "with port", value: types.StringValue("192.0.2.1:53"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("ip"), ConfigValue: tt.value}
+			resp := &validator.StringResponse{}
+			IPAddressValidator{}.ValidateString(context.Background(), req, resp)
+			assert.Equal(t, tt.expectError, resp.Diagnostics.HasError())
+		})
+	}
+}
+
+func TestAutoprimary_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	require.NoError(t, client.CreateAutoprimary(ctx, Autoprimary{IP: "2001:db8:0::1", Nameserver: "ns1.example.com.", Account: "dns"}))
+
+	// Lookups ignore how the IP address and the nameserver are spelled
+	autoprimary, err := client.GetAutoprimary(ctx, "2001:db8::1", "NS1.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "dns", autoprimary.Account)
+
+	_, err = client.GetAutoprimary(ctx, "2001:db8::2", "ns1.example.com.")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = client.CreateAutoprimary(ctx, Autoprimary{IP: "primary", Nameserver: "ns1.example.com."})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+
+	require.NoError(t, client.DeleteAutoprimary(ctx, "2001:db8::1", "ns1.example.com."))
+	assert.Empty(t, server.Autoprimaries())
+	assert.ErrorIs(t, client.DeleteAutoprimary(ctx, "2001:db8::1", "ns1.example.com."), ErrNotFound)
+}
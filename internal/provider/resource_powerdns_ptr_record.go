@@ -3,10 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
@@ -31,6 +33,7 @@ type PTRRecordResourceModel struct {
 	TTL         types.Int64  `tfsdk:"ttl"`
 	ReverseZone types.String `tfsdk:"reverse_zone"`
 	ID          types.String `tfsdk:"id"`
+	Timeouts    types.Object `tfsdk:"timeouts"`
 }
 
 func (r *PTRRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -76,6 +79,9 @@ func (r *PTRRecordResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}
 }
 
@@ -89,6 +95,8 @@ func (r *PTRRecordResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_ptr_record")
 }
 
 func (r *PTRRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -99,6 +107,13 @@ func (r *PTRRecordResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	ipAddress := data.IPAddress.ValueString()
 	hostname := data.Hostname.ValueString()
 	ttl := int(data.TTL.ValueInt64())
@@ -149,7 +164,10 @@ func (r *PTRRecordResource) Create(ctx context.Context, req resource.CreateReque
 
 	recID, err := r.client.ReplaceRecordSet(ctx, reverseZone, rrSet)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create PTR record", fmt.Errorf("failed to create PTR record: %w", err).Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create PTR record", fmt.Errorf("failed to create PTR record: %w", err), path.Root("hostname"), map[string]path.Path{
+			"ttl":         path.Root("ttl"),
+			"out of zone": path.Root("reverse_zone"),
+		})
 		return
 	}
 
@@ -171,6 +189,13 @@ func (r *PTRRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	ipAddress := data.IPAddress.ValueString()
 	reverseZone := data.ReverseZone.ValueString()
 
@@ -193,6 +218,11 @@ func (r *PTRRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	records, err := r.client.ListRecordsInRRSet(ctx, reverseZone, ptrName+suffix, "PTR")
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "Reverse zone of the PTR record not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Failed to read PTR record", fmt.Errorf("couldn't fetch PTR record: %w", err).Error())
 		return
 	}
@@ -224,6 +254,13 @@ func (r *PTRRecordResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	ipAddress := data.IPAddress.ValueString()
 	reverseZone := data.ReverseZone.ValueString()
 
@@ -245,6 +282,10 @@ func (r *PTRRecordResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 
 	if err := r.client.DeleteRecordSet(ctx, reverseZone, ptrName+suffix, "PTR"); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "Reverse zone of the PTR record already deleted")
+			return
+		}
 		// Check if this is a backend limitation error (common with LMDB)
 		if strings.Contains(err.Error(), "Hosting backend does not support editing records") ||
 			strings.Contains(err.Error(), "Attempt to abort a transaction while there isn't one open") {
@@ -321,6 +362,7 @@ func (r *PTRRecordResource) ImportState(ctx context.Context, req resource.Import
 	dataModel.TTL = types.Int64Value(int64(records[0].TTL))
 	dataModel.IPAddress = types.StringValue(ip.String())
 	dataModel.ID = types.StringValue(recordID)
+	dataModel.Timeouts = timeoutsNull()
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &dataModel)...)
 }
@@ -335,6 +377,13 @@ func (r *PTRRecordResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	// Since PTR records are immutable, just read the current state
 	ipAddress := data.IPAddress.ValueString()
 	reverseZone := data.ReverseZone.ValueString()
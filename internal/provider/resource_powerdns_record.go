@@ -3,15 +3,17 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -19,21 +21,84 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var _ resource.Resource = &RecordResource{}
+var _ resource.ResourceWithModifyPlan = &RecordResource{}
+var _ resource.ResourceWithValidateConfig = &RecordResource{}
 
 // RecordResource defines the resource implementation.
 type RecordResource struct {
 	client *Client
 }
 
+// recordValidationHints maps keywords of PowerDNS validation errors to the
+// record attribute they are about.
+var recordValidationHints = map[string]path.Path{
+	"ttl":         path.Root("ttl"),
+	"out of zone": path.Root("name"),
+	"comment":     path.Root("comments"),
+}
+
 // RecordResourceModel describes the resource data model.
 type RecordResourceModel struct {
-	Zone    types.String `tfsdk:"zone"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
-	TTL     types.Int64  `tfsdk:"ttl"`
-	Records types.Set    `tfsdk:"records"`
-	SetPtr  types.Bool   `tfsdk:"set_ptr"`
-	ID      types.String `tfsdk:"id"`
+	Zone     DNSNameValue `tfsdk:"zone"`
+	Name     DNSNameValue `tfsdk:"name"`
+	Type     types.String `tfsdk:"type"`
+	TTL      types.Int64  `tfsdk:"ttl"`
+	Records  types.Set    `tfsdk:"records"`
+	Record   types.Set    `tfsdk:"record"`
+	MX       types.Set    `tfsdk:"mx"`
+	SRV      types.Set    `tfsdk:"srv"`
+	CAA      types.Set    `tfsdk:"caa"`
+	TLSA     types.Set    `tfsdk:"tlsa"`
+	SSHFP    types.Set    `tfsdk:"sshfp"`
+	SVCB     types.Set    `tfsdk:"svcb"`
+	TXT      types.Set    `tfsdk:"txt"`
+	Comments types.List   `tfsdk:"comments"`
+	SetPtr   types.Bool   `tfsdk:"set_ptr"`
+	ID       types.String `tfsdk:"id"`
+	Timeouts types.Object `tfsdk:"timeouts"`
+}
+
+// RecordBlockModel describes a record block of the resource.
+type RecordBlockModel struct {
+	Content  RecordContentValue `tfsdk:"content"`
+	Disabled types.Bool         `tfsdk:"disabled"`
+}
+
+// recordBlockType is the type of the elements of the record block set.
+var recordBlockType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"content":  RecordContentType{},
+	"disabled": types.BoolType,
+}}
+
+// RecordCommentModel describes a comments block of the resource.
+type RecordCommentModel struct {
+	Content    types.String `tfsdk:"content"`
+	Account    types.String `tfsdk:"account"`
+	ModifiedAt types.Int64  `tfsdk:"modified_at"`
+}
+
+// recordCommentType is the type of the elements of the comments block list.
+var recordCommentType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"content":     types.StringType,
+	"account":     types.StringType,
+	"modified_at": types.Int64Type,
+}}
+
+// BoolDefault implements a static default for bool attributes.
+type BoolDefault struct {
+	Value bool
+}
+
+func (d BoolDefault) Description(ctx context.Context) string {
+	return fmt.Sprintf("Defaults to %t", d.Value)
+}
+
+func (d BoolDefault) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Defaults to `%t`", d.Value)
+}
+
+func (d BoolDefault) DefaultBool(ctx context.Context, req defaults.BoolRequest, resp *defaults.BoolResponse) {
+	resp.PlanValue = types.BoolValue(d.Value)
 }
 
 func (r *RecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -41,10 +106,56 @@ func (r *RecordResource) Metadata(ctx context.Context, req resource.MetadataRequ
 }
 
 func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	blocks := map[string]schema.Block{
+		"record": schema.SetNestedBlock{
+			MarkdownDescription: "A record of the rrset, as an alternative to `records` allowing to disable it",
+			NestedObject: schema.NestedBlockObject{
+				Attributes: map[string]schema.Attribute{
+					"content": schema.StringAttribute{
+						MarkdownDescription: "The record value",
+						CustomType:          RecordContentType{},
+						Required:            true,
+					},
+					"disabled": schema.BoolAttribute{
+						MarkdownDescription: "Whether the record is kept out of answers without being deleted. Defaults to `false`.",
+						Optional:            true,
+						Computed:            true,
+						Default:             BoolDefault{Value: false},
+					},
+				},
+			},
+		},
+		"comments": schema.ListNestedBlock{
+			MarkdownDescription: "A comment of the rrset. Comments are only managed when at least one block is set, otherwise those set outside of Terraform are kept.",
+			NestedObject: schema.NestedBlockObject{
+				Attributes: map[string]schema.Attribute{
+					"content": schema.StringAttribute{
+						MarkdownDescription: "The text of the comment",
+						Required:            true,
+					},
+					"account": schema.StringAttribute{
+						MarkdownDescription: "The account the comment is attributed to",
+						Optional:            true,
+						Computed:            true,
+					},
+					"modified_at": schema.Int64Attribute{
+						MarkdownDescription: "The time the comment was last changed, as a UNIX timestamp",
+						Computed:            true,
+					},
+				},
+			},
+		},
+		"timeouts": timeoutsBlock(),
+	}
+	for _, block := range typedRecordBlocks {
+		blocks[block.name] = block.schemaBlock()
+	}
+
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"zone": schema.StringAttribute{
 				MarkdownDescription: "The zone name",
+				CustomType:          DNSNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
@@ -52,6 +163,7 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The record name",
+				CustomType:          DNSNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
@@ -67,24 +179,15 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"ttl": schema.Int64Attribute{
 				MarkdownDescription: "The record TTL",
 				Required:            true,
-				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.RequiresReplace(),
-				},
 			},
 			"records": schema.SetAttribute{
-				ElementType:         types.StringType,
-				MarkdownDescription: "List of record values",
-				Required:            true,
-				PlanModifiers: []planmodifier.Set{
-					setplanmodifier.RequiresReplace(),
-				},
+				ElementType:         RecordContentType{},
+				MarkdownDescription: "List of record values. Exactly one of `records`, `record` blocks and the typed blocks, such as `mx`, must be set.",
+				Optional:            true,
 			},
 			"set_ptr": schema.BoolAttribute{
 				MarkdownDescription: "For A and AAAA records, if true, create corresponding PTR",
 				Optional:            true,
-				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
-				},
 			},
 			"id": schema.StringAttribute{
 				Computed:            true,
@@ -94,6 +197,49 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 		},
+		Blocks: blocks,
+	}
+}
+
+func (r *RecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data RecordResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Records.IsUnknown() || data.Record.IsUnknown() {
+		return
+	}
+
+	var sources []string
+	if !data.Records.IsNull() {
+		sources = append(sources, "'records'")
+	}
+	if len(data.Record.Elements()) > 0 {
+		sources = append(sources, "'record' blocks")
+	}
+	for _, block := range typedRecordBlocks {
+		set := *block.field(&data)
+		if set.IsUnknown() || len(set.Elements()) == 0 {
+			continue
+		}
+		sources = append(sources, fmt.Sprintf("'%s' blocks", block.name))
+		if !data.Type.IsUnknown() && !block.supports(data.Type.ValueString()) {
+			resp.Diagnostics.AddAttributeError(path.Root(block.name), "Invalid configuration", fmt.Sprintf("'%s' blocks can't describe records of type %s", block.name, data.Type.ValueString()))
+			continue
+		}
+		_, diags := block.contents(ctx, set)
+		for _, d := range diags {
+			resp.Diagnostics.Append(diag.WithPath(path.Root(block.name), d))
+		}
+	}
+
+	switch {
+	case len(sources) > 1:
+		conflicting := strings.Join(sources[:len(sources)-1], ", ") + " and " + sources[len(sources)-1]
+		resp.Diagnostics.AddAttributeError(path.Root("record"), "Conflicting configuration", conflicting+" can't be set together")
+	case len(sources) == 0:
+		resp.Diagnostics.AddAttributeError(path.Root("records"), "Invalid configuration", "one of 'records', 'record' blocks or typed blocks such as 'mx' must be set")
+	case !data.Records.IsNull() && len(data.Records.Elements()) == 0:
+		resp.Diagnostics.AddAttributeError(path.Root("records"), "Invalid configuration", "'records' must not be empty")
 	}
 }
 
@@ -107,6 +253,25 @@ func (r *RecordResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_record")
+}
+
+func (r *RecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var recordType types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("type"), &recordType)...)
+	if resp.Diagnostics.HasError() || recordType.IsNull() || recordType.IsUnknown() {
+		return
+	}
+
+	if strings.EqualFold(recordType.ValueString(), "LUA") {
+		r.client.requireCapability(&resp.Diagnostics, capabilityLuaRecords, path.Root("type"))
+	}
 }
 
 func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -117,14 +282,30 @@ func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	// Validate records
-	if data.Records.IsNull() || len(data.Records.Elements()) == 0 {
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	rrSet, diags := data.recordSet(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(rrSet.Records) == 0 {
 		resp.Diagnostics.AddError("Invalid configuration", "'records' must not be empty")
 		return
 	}
+	rrSet.Comments, diags = commentsUpdate(ctx, data.Comments, types.ListNull(recordCommentType))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Ensure zone exists before creating records
-	zoneName := data.Zone.ValueString()
+	zoneName := data.Zone.FQDN()
 	tflog.Debug(ctx, "Verifying zone exists", map[string]any{"zone": zoneName})
 
 	exists, err := r.client.ZoneExists(ctx, zoneName)
@@ -139,45 +320,26 @@ func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	// Basic validation for records content
-	for _, raw := range data.Records.Elements() {
-		if str, ok := raw.(types.String); ok && strings.TrimSpace(str.ValueString()) == "" {
+	for _, record := range rrSet.Records {
+		if strings.TrimSpace(record.Content) == "" {
 			tflog.Warn(ctx, "One or more values in 'records' are empty strings")
 			break
 		}
 	}
 
-	rrSet := ResourceRecordSet{
-		Name: data.Name.ValueString(),
-		Type: data.Type.ValueString(),
-		TTL:  int(data.TTL.ValueInt64()),
-	}
-
-	records := make([]Record, 0, len(data.Records.Elements()))
-	for _, rc := range data.Records.Elements() {
-		if str, ok := rc.(types.String); ok {
-			records = append(records, Record{
-				Name:    rrSet.Name,
-				Type:    rrSet.Type,
-				TTL:     rrSet.TTL,
-				Content: str.ValueString(),
-				SetPtr:  data.SetPtr.ValueBool(),
-			})
-		}
-	}
-	rrSet.Records = records
-
 	tflog.SetField(ctx, "zone", data.Zone.ValueString())
 	tflog.SetField(ctx, "name", data.Name.ValueString())
 	tflog.SetField(ctx, "type", data.Type.ValueString())
 	tflog.Debug(ctx, "Creating PowerDNS record set")
 
-	recID, err := r.client.ReplaceRecordSet(ctx, data.Zone.ValueString(), rrSet)
+	recID, err := r.client.ReplaceRecordSet(ctx, data.Zone.FQDN(), rrSet)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create record", fmt.Errorf("failed to create PowerDNS Record: %w", err).Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create record", fmt.Errorf("failed to create PowerDNS Record: %w", err), path.Root("records"), recordValidationHints)
 		return
 	}
 
 	data.ID = types.StringValue(recID)
+	resp.Diagnostics.Append(r.readComments(ctx, &data)...)
 	tflog.Info(ctx, "Created PowerDNS Record", map[string]any{"id": recID})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -191,12 +353,24 @@ func (r *RecordResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	tflog.SetField(ctx, "zone", data.Zone.ValueString())
 	tflog.SetField(ctx, "record_id", data.ID.ValueString())
 	tflog.Debug(ctx, "Reading PowerDNS Record")
 
-	records, err := r.client.ListRecordsByID(ctx, data.Zone.ValueString(), data.ID.ValueString())
+	records, err := r.client.ListRecordsByID(ctx, data.Zone.FQDN(), data.ID.ValueString())
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "PowerDNS Zone of the Record not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Failed to read record", fmt.Errorf("couldn't fetch PowerDNS Record: %w", err).Error())
 		return
 	}
@@ -208,50 +382,65 @@ func (r *RecordResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	var recs []types.String
-	for _, record := range records {
-		recs = append(recs, types.StringValue(record.Content))
-	}
-
-	data.Records, _ = types.SetValueFrom(ctx, types.StringType, recs)
+	resp.Diagnostics.Append(data.setRecords(ctx, records)...)
 	data.TTL = types.Int64Value(int64(records[0].TTL))
-	data.Name = types.StringValue(records[0].Name)
+	data.Name = NewDNSNameValue(records[0].Name)
 	data.Type = types.StringValue(records[0].Type)
+	resp.Diagnostics.Append(r.readComments(ctx, &data)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *RecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Records are immutable in PowerDNS - they use RequiresReplace() plan modifiers
-	// So Update should not be called, but we need to implement it for the interface
-	var data RecordResourceModel
+	var data, state RecordResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Since records are immutable, just read the current state
-	records, err := r.client.ListRecordsByID(ctx, data.Zone.ValueString(), data.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to read record", fmt.Errorf("couldn't fetch PowerDNS Record: %w", err).Error())
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	defer cancel()
 
-	if len(records) == 0 {
-		resp.Diagnostics.AddError("Record not found", "PowerDNS Record not found during update")
+	rrSet, diags := data.recordSet(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(rrSet.Records) == 0 {
+		resp.Diagnostics.AddError("Invalid configuration", "'records' must not be empty")
+		return
+	}
+	rrSet.Comments, diags = commentsUpdate(ctx, data.Comments, state.Comments)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var recs []types.String
-	for _, record := range records {
-		recs = append(recs, types.StringValue(record.Content))
+	ctx = tflog.SetField(ctx, "zone", data.Zone.ValueString())
+	ctx = tflog.SetField(ctx, "record_id", data.ID.ValueString())
+	tflog.Debug(ctx, "Updating PowerDNS record set")
+
+	// Zone, name and type force a new resource, so the rrset is replaced in
+	// place, in a single PATCH that never leaves the name without records
+	recID, err := r.client.ReplaceRecordSet(ctx, data.Zone.FQDN(), rrSet)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			resp.Diagnostics.AddError("Zone not found", fmt.Sprintf("zone %s does not exist", data.Zone.ValueString()))
+			return
+		}
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to update record", fmt.Errorf("failed to update PowerDNS Record: %w", err), path.Root("records"), recordValidationHints)
+		return
 	}
 
-	data.Records, _ = types.SetValueFrom(ctx, types.StringType, recs)
-	data.TTL = types.Int64Value(int64(records[0].TTL))
-	data.Name = types.StringValue(records[0].Name)
-	data.Type = types.StringValue(records[0].Type)
+	data.ID = types.StringValue(recID)
+	resp.Diagnostics.Append(r.readComments(ctx, &data)...)
+	tflog.Info(ctx, "Updated PowerDNS Record")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -264,12 +453,23 @@ func (r *RecordResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	tflog.SetField(ctx, "zone", data.Zone.ValueString())
 	tflog.SetField(ctx, "record_id", data.ID.ValueString())
 	tflog.Debug(ctx, "Deleting PowerDNS Record")
 
-	err := r.client.DeleteRecordSetByID(ctx, data.Zone.ValueString(), data.ID.ValueString())
+	err := r.client.DeleteRecordSetByID(ctx, data.Zone.FQDN(), data.ID.ValueString())
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "PowerDNS Zone of the Record already deleted")
+			return
+		}
 		// Check if this is a backend limitation error (common with LMDB)
 		if strings.Contains(err.Error(), "Hosting backend does not support editing records") ||
 			strings.Contains(err.Error(), "Attempt to abort a transaction while there isn't one open") {
@@ -322,23 +522,227 @@ func (r *RecordResource) ImportState(ctx context.Context, req resource.ImportSta
 		return
 	}
 
-	var recs []types.String
-	for _, record := range records {
-		recs = append(recs, types.StringValue(record.Content))
-	}
-
 	var dataModel RecordResourceModel
-	dataModel.Zone = types.StringValue(zoneName)
-	dataModel.Name = types.StringValue(records[0].Name)
+	dataModel.Zone = NewDNSNameValue(zoneName)
+	dataModel.Name = NewDNSNameValue(records[0].Name)
 	dataModel.TTL = types.Int64Value(int64(records[0].TTL))
 	dataModel.Type = types.StringValue(records[0].Type)
 	dataModel.ID = types.StringValue(recordID)
-
-	dataModel.Records, _ = types.SetValueFrom(ctx, types.StringType, recs)
+	dataModel.Timeouts = timeoutsNull()
+	dataModel.Record = types.SetNull(recordBlockType)
+	dataModel.Comments = types.ListValueMust(recordCommentType, []attr.Value{})
+	resp.Diagnostics.Append(dataModel.setRecords(ctx, records)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &dataModel)...)
 }
 
+// recordSet returns the rrset described by the model, from either its
+// records, its record blocks or its typed blocks. Record contents are sent in
+// the form PowerDNS expects for their type, see canonicalRecordContent.
+func (m *RecordResourceModel) recordSet(ctx context.Context) (ResourceRecordSet, diag.Diagnostics) {
+	rrSet := ResourceRecordSet{
+		Name: m.Name.FQDN(),
+		Type: m.Type.ValueString(),
+		TTL:  int(m.TTL.ValueInt64()),
+	}
+	record := func(content string, disabled bool) Record {
+		return Record{
+			Name:     rrSet.Name,
+			Type:     rrSet.Type,
+			TTL:      rrSet.TTL,
+			Content:  content,
+			Disabled: disabled,
+			SetPtr:   m.SetPtr.ValueBool(),
+		}
+	}
+
+	if len(m.Record.Elements()) > 0 {
+		var blocks []RecordBlockModel
+		diags := m.Record.ElementsAs(ctx, &blocks, false)
+		if diags.HasError() {
+			return rrSet, diags
+		}
+		for _, block := range blocks {
+			rrSet.Records = append(rrSet.Records, record(canonicalRecordContent(rrSet.Type, block.Content.ValueString()), block.Disabled.ValueBool()))
+		}
+		return rrSet, nil
+	}
+
+	for _, block := range typedRecordBlocks {
+		set := *block.field(m)
+		if len(set.Elements()) == 0 {
+			continue
+		}
+		contents, diags := block.contents(ctx, set)
+		if diags.HasError() {
+			return rrSet, diags
+		}
+		for _, content := range contents {
+			rrSet.Records = append(rrSet.Records, record(content, false))
+		}
+		return rrSet, nil
+	}
+
+	for _, rc := range m.Records.Elements() {
+		if str, ok := rc.(RecordContentValue); ok {
+			rrSet.Records = append(rrSet.Records, record(canonicalRecordContent(rrSet.Type, str.ValueString()), false))
+		}
+	}
+	return rrSet, nil
+}
+
+// setRecords sets the records of the model from those of the rrset. Record
+// blocks are used when the model already has some, or when records are
+// disabled, which the other forms can't express. Typed blocks are used when
+// the model already has some, or on import for the types having some, as long
+// as all the records parse. Contents semantically equal to those of the
+// model, for the record type, keep the form of the model.
+func (m *RecordResourceModel) setRecords(ctx context.Context, records []Record) diag.Diagnostics {
+	useBlocks := len(m.Record.Elements()) > 0
+	for _, record := range records {
+		useBlocks = useBlocks || record.Disabled
+	}
+	contents := make([]string, 0, len(records))
+	for _, record := range records {
+		contents = append(contents, record.Content)
+	}
+	contents = keepContents(ctx, m.Type.ValueString(), m.priorContents(ctx), contents)
+
+	// Neither records nor record blocks are known on import
+	useTyped := m.Records.IsNull() && m.Record.IsNull()
+	typed, hasTyped := typedRecordBlockFor(m.Type.ValueString())
+	for _, block := range typedRecordBlocks {
+		set := block.field(m)
+		if len(set.Elements()) > 0 {
+			typed, hasTyped, useTyped = block, true, true
+		}
+		*set = types.SetValueMust(block.objectType, []attr.Value{})
+	}
+
+	var diags diag.Diagnostics
+	if !useBlocks && hasTyped && useTyped {
+		set, err := typed.blocks(ctx, contents)
+		if err == nil {
+			m.Records = types.SetNull(RecordContentType{})
+			m.Record = types.SetValueMust(recordBlockType, []attr.Value{})
+			*typed.field(m) = set
+			return diags
+		}
+		tflog.Warn(ctx, "Records can't be read into typed blocks, reading them into records", map[string]any{"block": typed.name, "error": err.Error()})
+	}
+
+	if !useBlocks {
+		m.Records, diags = types.SetValueFrom(ctx, RecordContentType{}, contents)
+		m.Record = types.SetValueMust(recordBlockType, []attr.Value{})
+		return diags
+	}
+
+	blocks := make([]RecordBlockModel, 0, len(records))
+	for i, record := range records {
+		blocks = append(blocks, RecordBlockModel{
+			Content:  NewRecordContentValue(contents[i]),
+			Disabled: types.BoolValue(record.Disabled),
+		})
+	}
+	m.Records = types.SetNull(RecordContentType{})
+	m.Record, diags = types.SetValueFrom(ctx, recordBlockType, blocks)
+	return diags
+}
+
+// priorContents returns the contents of the records or record blocks of the
+// model.
+func (m *RecordResourceModel) priorContents(ctx context.Context) []string {
+	var contents []string
+	for _, rc := range m.Records.Elements() {
+		if str, ok := rc.(RecordContentValue); ok {
+			contents = append(contents, str.ValueString())
+		}
+	}
+	var blocks []RecordBlockModel
+	if diags := m.Record.ElementsAs(ctx, &blocks, false); !diags.HasError() {
+		for _, block := range blocks {
+			contents = append(contents, block.Content.ValueString())
+		}
+	}
+	return contents
+}
+
+// readComments refreshes the comments of the model from the server, when they
+// are managed, that is when the model has some.
+func (r *RecordResource) readComments(ctx context.Context, m *RecordResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(m.Comments.Elements()) == 0 {
+		m.Comments = types.ListValueMust(recordCommentType, []attr.Value{})
+		return diags
+	}
+
+	rrSet, err := r.client.GetRecordSet(ctx, m.Zone.FQDN(), m.Name.FQDN(), m.Type.ValueString())
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		diags.AddError("Failed to read record comments", fmt.Errorf("couldn't fetch PowerDNS Record: %w", err).Error())
+		return diags
+	}
+
+	comments := []RecordCommentModel{}
+	if rrSet.Comments != nil {
+		for _, comment := range *rrSet.Comments {
+			comments = append(comments, RecordCommentModel{
+				Content:    types.StringValue(comment.Content),
+				Account:    types.StringValue(comment.Account),
+				ModifiedAt: types.Int64Value(comment.ModifiedAt),
+			})
+		}
+	}
+	m.Comments, diags = types.ListValueFrom(ctx, recordCommentType, comments)
+	return diags
+}
+
+// commentsUpdate returns the comments to send with the rrset, or nil to keep
+// those of the server, when they aren't managed or didn't change. Removing all
+// the comments blocks clears the comments they managed.
+func commentsUpdate(ctx context.Context, plan types.List, state types.List) (*[]Comment, diag.Diagnostics) {
+	planned, diags := recordComments(ctx, plan)
+	if diags.HasError() {
+		return nil, diags
+	}
+	current, diags := recordComments(ctx, state)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if len(planned) == len(current) {
+		changed := false
+		for i := range planned {
+			changed = changed || planned[i] != current[i]
+		}
+		if !changed {
+			return nil, nil
+		}
+	}
+	return &planned, nil
+}
+
+// recordComments converts comments blocks to the comments of the API, leaving
+// their dates for the server to set.
+func recordComments(ctx context.Context, list types.List) ([]Comment, diag.Diagnostics) {
+	var models []RecordCommentModel
+	diags := list.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	comments := make([]Comment, 0, len(models))
+	for _, model := range models {
+		comments = append(comments, Comment{
+			Content: model.Content.ValueString(),
+			Account: model.Account.ValueString(),
+		})
+	}
+	return comments, nil
+}
+
 func NewRecordResource() resource.Resource {
 	return &RecordResource{}
 }
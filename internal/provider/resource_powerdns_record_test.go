@@ -1,11 +1,19 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccRecordResource(t *testing.T) {
@@ -80,9 +88,28 @@ func TestAccRecordResource_MultipleValues(t *testing.T) {
 	})
 }
 
+func TestAccRecordResource_Canonicalized(t *testing.T) {
+	// PowerDNS stores the name with a trailing dot and the address
+	// compressed, which must not show up as a diff after apply
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZoneAndRecordConfig("unique-canonical.test-zone-008.com.", "Test.unique-canonical.test-zone-008.com", "AAAA", 300, []string{"2001:0db8:0000:0000:0000:0000:0000:0001"}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("powerdns_record.test", "name", "Test.unique-canonical.test-zone-008.com"),
+					resource.TestCheckTypeSetElemAttr("powerdns_record.test", "records.*", "2001:0db8:0000:0000:0000:0000:0000:0001"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRecordResource_Update(t *testing.T) {
-	// Records are immutable in PowerDNS, so this test verifies that Update
-	// properly refreshes state without actually changing the resource
+	// TTL and records changes are applied in place, zone, name and type
+	// changes replace the rrset
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -109,10 +136,250 @@ func TestAccRecordResource_Update(t *testing.T) {
 					resource.TestCheckResourceAttr("powerdns_record.test", "records.#", "1"),
 				),
 			},
+			{
+				Config: testAccZoneAndRecordConfig("unique-update.test-zone-004.com.", "test.unique-update.test-zone-004.com.", "A", 600, []string{"192.168.1.1", "192.168.1.2"}),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("powerdns_record.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("powerdns_record.test", "id", "test.unique-update.test-zone-004.com.:::A"),
+					resource.TestCheckResourceAttr("powerdns_record.test", "ttl", "600"),
+					resource.TestCheckResourceAttr("powerdns_record.test", "records.#", "2"),
+				),
+			},
+			{
+				Config: testAccZoneAndRecordConfig("unique-update.test-zone-004.com.", "test.unique-update.test-zone-004.com.", "AAAA", 600, []string{"2001:db8::1"}),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("powerdns_record.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("powerdns_record.test", "id", "test.unique-update.test-zone-004.com.:::AAAA"),
+					resource.TestCheckResourceAttr("powerdns_record.test", "records.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecordResource_RecordBlocks(t *testing.T) {
+	resourceName := "powerdns_record.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZoneAndRecordBlocksConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr(resourceName, "records.#"),
+					resource.TestCheckResourceAttr(resourceName, "record.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "record.*", map[string]string{"content": "192.168.1.2", "disabled": "false"}),
+				),
+			},
+			// Taking a member out of rotation is done in place
+			{
+				Config: testAccZoneAndRecordBlocksConfig(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "record.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "record.*", map[string]string{"content": "192.168.1.1", "disabled": "false"}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "record.*", map[string]string{"content": "192.168.1.2", "disabled": "true"}),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           `{"zone": "unique-blocks.test-zone-005.com.", "id": "test.unique-blocks.test-zone-005.com.:::A"}`,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func testAccZoneAndRecordBlocksConfig(disabled bool) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+  server_url = "http://localhost:8081"
+  api_key    = "secret"
+}
+
+resource "powerdns_zone" "test_zone" {
+  name        = "unique-blocks.test-zone-005.com."
+  kind        = "Master"
+  nameservers = ["ns1.test.example.com.", "ns2.test.example.com."]
+}
+
+resource "powerdns_record" "test" {
+  zone = powerdns_zone.test_zone.name
+  name = "test.unique-blocks.test-zone-005.com."
+  type = "A"
+  ttl  = 300
+
+  record {
+    content = "192.168.1.1"
+  }
+
+  record {
+    content  = "192.168.1.2"
+    disabled = %t
+  }
+}
+`, disabled)
+}
+
+func TestAccRecordResource_TypedBlocks(t *testing.T) {
+	resourceName := "powerdns_record.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZoneAndRecordTypedConfig(20),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr(resourceName, "records.#"),
+					resource.TestCheckResourceAttr(resourceName, "mx.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "mx.*", map[string]string{"preference": "20", "exchange": "mail2.unique-typed.test-zone-007.com."}),
+				),
+			},
+			{
+				Config: testAccZoneAndRecordTypedConfig(30),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "mx.*", map[string]string{"preference": "30", "exchange": "mail2.unique-typed.test-zone-007.com."}),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           `{"zone": "unique-typed.test-zone-007.com.", "id": "unique-typed.test-zone-007.com.:::MX"}`,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func testAccZoneAndRecordTypedConfig(preference int) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+  server_url = "http://localhost:8081"
+  api_key    = "secret"
+}
+
+resource "powerdns_zone" "test_zone" {
+  name        = "unique-typed.test-zone-007.com."
+  kind        = "Master"
+  nameservers = ["ns1.test.example.com.", "ns2.test.example.com."]
+}
+
+resource "powerdns_record" "test" {
+  zone = powerdns_zone.test_zone.name
+  name = "unique-typed.test-zone-007.com."
+  type = "MX"
+  ttl  = 300
+
+  mx {
+    preference = 10
+    exchange   = "mail1.unique-typed.test-zone-007.com."
+  }
+
+  mx {
+    preference = %d
+    exchange   = "mail2.unique-typed.test-zone-007.com."
+  }
+}
+`, preference)
+}
+
+func TestAccRecordResource_Comments(t *testing.T) {
+	resourceName := "powerdns_record.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZoneAndRecordCommentsConfig("web server"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "comments.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "comments.0.content", "web server"),
+					resource.TestCheckResourceAttr(resourceName, "comments.0.account", "ops"),
+					resource.TestCheckResourceAttrSet(resourceName, "comments.0.modified_at"),
+				),
+			},
+			// Changing only the comment is done in place
+			{
+				Config: testAccZoneAndRecordCommentsConfig("load balancer"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "comments.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "comments.0.content", "load balancer"),
+				),
+			},
+			{
+				Config: testAccZoneAndRecordCommentsConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "comments.#", "0"),
+				),
+			},
 		},
 	})
 }
 
+func testAccZoneAndRecordCommentsConfig(comment string) string {
+	comments := ""
+	if comment != "" {
+		comments = fmt.Sprintf(`
+  comments {
+    content = %q
+    account = "ops"
+  }
+`, comment)
+	}
+
+	return fmt.Sprintf(`
+provider "powerdns" {
+  server_url = "http://localhost:8081"
+  api_key    = "secret"
+}
+
+resource "powerdns_zone" "test_zone" {
+  name        = "unique-comments.test-zone-006.com."
+  kind        = "Master"
+  nameservers = ["ns1.test.example.com.", "ns2.test.example.com."]
+}
+
+resource "powerdns_record" "test" {
+  zone    = powerdns_zone.test_zone.name
+  name    = "test.unique-comments.test-zone-006.com."
+  type    = "A"
+  ttl     = 300
+  records = ["192.168.1.1"]
+%s}
+`, comments)
+}
+
 func testAccZoneAndRecordConfig(zoneName, recordName, recordType string, ttl int64, records []string) string {
 	recordsStr := ""
 	for _, record := range records {
@@ -155,3 +422,438 @@ func testAccCheckRecordDestroy(s *terraform.State) error {
 	// handles the deletion properly through the Delete method
 	return nil
 }
+
+func TestRecord_RecordSet(t *testing.T) {
+	ctx := context.Background()
+	records, diags := types.SetValueFrom(ctx, RecordContentType{}, []string{"192.0.2.1", "192.0.2.2"})
+	require.False(t, diags.HasError())
+	blocks, diags := types.SetValueFrom(ctx, recordBlockType, []RecordBlockModel{
+		{Content: NewRecordContentValue("192.0.2.1"), Disabled: types.BoolValue(false)},
+		{Content: NewRecordContentValue("192.0.2.2"), Disabled: types.BoolValue(true)},
+	})
+	require.False(t, diags.HasError())
+
+	tests := []struct {
+		name     string
+		records  types.Set
+		blocks   types.Set
+		expected []Record
+	}{
+		{
+			name:    "records",
+			records: records,
+			blocks:  types.SetValueMust(recordBlockType, nil),
+			expected: []Record{
+				{Name: "www.example.com.", Type: "A", TTL: 600, Content: "192.0.2.1", SetPtr: true},
+				{Name: "www.example.com.", Type: "A", TTL: 600, Content: "192.0.2.2", SetPtr: true},
+			},
+		},
+		{
+			name:    "record blocks",
+			records: types.SetNull(RecordContentType{}),
+			blocks:  blocks,
+			expected: []Record{
+				{Name: "www.example.com.", Type: "A", TTL: 600, Content: "192.0.2.1", SetPtr: true},
+				{Name: "www.example.com.", Type: "A", TTL: 600, Content: "192.0.2.2", Disabled: true, SetPtr: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := RecordResourceModel{
+				Zone:    NewDNSNameValue("example.com."),
+				Name:    NewDNSNameValue("www.example.com."),
+				Type:    types.StringValue("A"),
+				TTL:     types.Int64Value(600),
+				Records: tt.records,
+				Record:  tt.blocks,
+				SetPtr:  types.BoolValue(true),
+			}
+
+			rrSet, diags := data.recordSet(ctx)
+			require.False(t, diags.HasError())
+			assert.Equal(t, "www.example.com.:::A", rrSet.ID())
+			assert.Equal(t, 600, rrSet.TTL)
+			assert.ElementsMatch(t, tt.expected, rrSet.Records)
+		})
+	}
+}
+
+func TestRecord_SetRecords(t *testing.T) {
+	ctx := context.Background()
+	enabled := []Record{{Content: "192.0.2.1"}, {Content: "192.0.2.2"}}
+	withDisabled := []Record{{Content: "192.0.2.1"}, {Content: "192.0.2.2", Disabled: true}}
+	blocks, diags := types.SetValueFrom(ctx, recordBlockType, []RecordBlockModel{
+		{Content: NewRecordContentValue("192.0.2.1"), Disabled: types.BoolValue(false)},
+	})
+	require.False(t, diags.HasError())
+
+	tests := []struct {
+		name             string
+		blocks           types.Set
+		records          []Record
+		expectBlocks     bool
+		expectedDisabled []string
+	}{
+		{name: "flat records", blocks: types.SetNull(recordBlockType), records: enabled},
+		{name: "disabled record", blocks: types.SetNull(recordBlockType), records: withDisabled, expectBlocks: true, expectedDisabled: []string{"192.0.2.2"}},
+		{name: "record blocks", blocks: blocks, records: enabled, expectBlocks: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := RecordResourceModel{Records: types.SetNull(RecordContentType{}), Record: tt.blocks}
+			require.False(t, data.setRecords(ctx, tt.records).HasError())
+
+			if !tt.expectBlocks {
+				assert.Len(t, data.Records.Elements(), len(tt.records))
+				assert.Empty(t, data.Record.Elements())
+				return
+			}
+			assert.True(t, data.Records.IsNull())
+			var got []RecordBlockModel
+			require.False(t, data.Record.ElementsAs(ctx, &got, false).HasError())
+			require.Len(t, got, len(tt.records))
+			var disabled []string
+			for _, block := range got {
+				if block.Disabled.ValueBool() {
+					disabled = append(disabled, block.Content.ValueString())
+				}
+			}
+			assert.Equal(t, tt.expectedDisabled, disabled)
+		})
+	}
+}
+
+func TestRecord_FakeServerCanonicalContent(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		tpe     string
+		config  string
+		stored  string
+		records bool
+	}{
+		{name: "aaaa", tpe: "AAAA", config: "2001:0DB8::0001", stored: "2001:db8::1", records: true},
+		{name: "cname", tpe: "CNAME", config: "Target.example.com", stored: "Target.example.com."},
+		{name: "mx", tpe: "MX", config: "010 mail.example.com", stored: "10 mail.example.com.", records: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The server refuses the content as configured
+			_, err := client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+				Name: tt.name + ".example.com.", Type: tt.tpe, TTL: 300, Records: []Record{{Content: tt.config}},
+			})
+			require.ErrorContains(t, err, "Not in expected format")
+
+			data := RecordResourceModel{
+				Name:    NewDNSNameValue(tt.name + ".example.com"),
+				Type:    types.StringValue(tt.tpe),
+				TTL:     types.Int64Value(300),
+				Records: types.SetNull(RecordContentType{}),
+				Record:  types.SetValueMust(recordBlockType, nil),
+			}
+			if tt.records {
+				data.Records = types.SetValueMust(RecordContentType{}, []attr.Value{NewRecordContentValue(tt.config)})
+			} else {
+				data.Record = types.SetValueMust(recordBlockType, []attr.Value{types.ObjectValueMust(recordBlockType.AttrTypes, map[string]attr.Value{
+					"content":  NewRecordContentValue(tt.config),
+					"disabled": types.BoolValue(false),
+				})})
+			}
+			prior := data
+
+			rrSet, diags := data.recordSet(ctx)
+			require.False(t, diags.HasError())
+			id, err := client.ReplaceRecordSet(ctx, "example.com.", rrSet)
+			require.NoError(t, err)
+			stored, ok := server.RRSet("example.com.", tt.name+".example.com.", tt.tpe)
+			require.True(t, ok)
+			assert.Equal(t, tt.stored, stored.Records[0].Content)
+
+			// The content read back keeps the configured form
+			records, err := client.ListRecordsByID(ctx, "example.com.", id)
+			require.NoError(t, err)
+			require.Equal(t, tt.stored, records[0].Content)
+			require.False(t, data.setRecords(ctx, records).HasError())
+			assert.True(t, prior.Records.Equal(data.Records), "records: %s", data.Records)
+			assert.True(t, prior.Record.Equal(data.Record), "record blocks: %s", data.Record)
+		})
+	}
+
+	// Contents of the types PowerDNS keeps as is must be identical to be kept
+	data := RecordResourceModel{
+		Type:    types.StringValue("TLSA"),
+		Records: types.SetValueMust(RecordContentType{}, []attr.Value{NewRecordContentValue("3 1 1 0123ABCD")}),
+		Record:  types.SetNull(recordBlockType),
+	}
+	require.False(t, data.setRecords(ctx, []Record{{Content: "3 1 1 0123abcd"}}).HasError())
+	assert.Equal(t, types.SetValueMust(RecordContentType{}, []attr.Value{NewRecordContentValue("3 1 1 0123abcd")}), data.Records)
+}
+
+func TestRecord_ResourceCanonicalContent(t *testing.T) {
+	server := pdnstest.New(t)
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccRecordCanonicalContentConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("powerdns_record.aaaa", "records.*", "2001:0DB8::0001"),
+					resource.TestCheckTypeSetElemNestedAttrs("powerdns_record.cname", "record.*", map[string]string{"content": "Target.example.com"}),
+					resource.TestCheckTypeSetElemAttr("powerdns_record.mx", "records.*", "010 mail.example.com"),
+					func(*terraform.State) error {
+						for name, expected := range map[string]string{"aaaa": "2001:db8::1", "cname": "Target.example.com.", "mx": "10 mail.example.com."} {
+							tpe := strings.ToUpper(name)
+							rrSet, ok := server.RRSet("canonical.example.", name+".canonical.example.", tpe)
+							if !ok || len(rrSet.Records) != 1 || rrSet.Records[0].Content != expected {
+								return fmt.Errorf("%s records are %v, expected %q", tpe, rrSet.Records, expected)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			// Reading back the canonical contents doesn't show up as a change
+			{
+				Config:   testFakeServerConfig(server, testAccRecordCanonicalContentConfig),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccRecordCanonicalContentConfig = `
+provider "powerdns" {
+  server_url = "http://localhost:8081"
+  api_key    = "secret"
+}
+
+resource "powerdns_zone" "test" {
+  name        = "canonical.example."
+  kind        = "Native"
+  nameservers = ["ns1.canonical.example."]
+}
+
+resource "powerdns_record" "aaaa" {
+  zone    = powerdns_zone.test.name
+  name    = "aaaa.canonical.example."
+  type    = "AAAA"
+  ttl     = 300
+  records = ["2001:0DB8::0001"]
+}
+
+resource "powerdns_record" "cname" {
+  zone = powerdns_zone.test.name
+  name = "cname.canonical.example."
+  type = "CNAME"
+  ttl  = 300
+
+  record {
+    content = "Target.example.com"
+  }
+}
+
+resource "powerdns_record" "mx" {
+  zone    = powerdns_zone.test.name
+  name    = "mx.canonical.example."
+  type    = "MX"
+  ttl     = 300
+  records = ["010 mail.example.com"]
+}
+`
+
+func TestRecord_RecordSetTypedBlocks(t *testing.T) {
+	ctx := context.Background()
+	txt, diags := types.SetValueFrom(ctx, txtRecordType, []TXTRecordModel{{Value: types.StringValue("v=spf1 mx -all")}})
+	require.False(t, diags.HasError())
+
+	data := RecordResourceModel{
+		Name:    NewDNSNameValue("example.com"),
+		Type:    types.StringValue("TXT"),
+		TTL:     types.Int64Value(300),
+		Records: types.SetNull(RecordContentType{}),
+		Record:  types.SetValueMust(recordBlockType, nil),
+		TXT:     txt,
+	}
+
+	rrSet, diags := data.recordSet(ctx)
+	require.False(t, diags.HasError())
+	assert.Equal(t, []Record{{Name: "example.com.", Type: "TXT", TTL: 300, Content: `"v=spf1 mx -all"`}}, rrSet.Records)
+}
+
+func TestRecord_SetTypedRecords(t *testing.T) {
+	ctx := context.Background()
+	mx := []Record{{Content: "10 mail1.example.com."}, {Content: "20 mail2.example.com."}}
+	mxBlocks, diags := types.SetValueFrom(ctx, mxRecordType, []MXRecordModel{
+		{Preference: types.Int64Value(10), Exchange: NewDNSNameValue("mail1.example.com.")},
+	})
+	require.False(t, diags.HasError())
+	records, diags := types.SetValueFrom(ctx, RecordContentType{}, []string{"10 mail1.example.com."})
+	require.False(t, diags.HasError())
+
+	tests := []struct {
+		name          string
+		tpe           string
+		records       types.Set
+		mx            types.Set
+		read          []Record
+		expectTyped   bool
+		expectRecords bool
+	}{
+		{name: "imported", tpe: "MX", records: types.SetNull(RecordContentType{}), read: mx, expectTyped: true},
+		{name: "imported unparsable", tpe: "MX", records: types.SetNull(RecordContentType{}), read: []Record{{Content: "mail.example.com."}}, expectRecords: true},
+		{name: "imported disabled", tpe: "MX", records: types.SetNull(RecordContentType{}), read: []Record{{Content: "10 mail.example.com.", Disabled: true}}},
+		{name: "typed blocks", tpe: "MX", records: types.SetNull(RecordContentType{}), mx: mxBlocks, read: mx, expectTyped: true},
+		{name: "records", tpe: "MX", records: records, read: mx, expectRecords: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := RecordResourceModel{Type: types.StringValue(tt.tpe), Records: tt.records, Record: types.SetNull(recordBlockType), MX: tt.mx}
+			require.False(t, data.setRecords(ctx, tt.read).HasError())
+
+			assert.Equal(t, tt.expectRecords, !data.Records.IsNull())
+			assert.Equal(t, !tt.expectTyped && !tt.expectRecords, len(data.Record.Elements()) > 0)
+			if !tt.expectTyped {
+				assert.Empty(t, data.MX.Elements())
+				return
+			}
+			var got []MXRecordModel
+			require.False(t, data.MX.ElementsAs(ctx, &got, false).HasError())
+			assert.ElementsMatch(t, []MXRecordModel{
+				{Preference: types.Int64Value(10), Exchange: NewDNSNameValue("mail1.example.com.")},
+				{Preference: types.Int64Value(20), Exchange: NewDNSNameValue("mail2.example.com.")},
+			}, got)
+			assert.Empty(t, data.TXT.Elements())
+		})
+	}
+}
+
+func TestRecord_FakeServerDisabledRecords(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+	_, err = client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}, {Content: "192.0.2.2", Disabled: true}},
+	})
+	require.NoError(t, err)
+
+	records, err := client.ListRecordsByID(ctx, "example.com.", "www.example.com.:::A")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	disabled := map[string]bool{}
+	for _, record := range records {
+		disabled[record.Content] = record.Disabled
+	}
+	assert.Equal(t, map[string]bool{"192.0.2.1": false, "192.0.2.2": true}, disabled)
+}
+
+func TestRecord_CommentsUpdate(t *testing.T) {
+	ctx := context.Background()
+	commentList := func(comments ...string) types.List {
+		var models []RecordCommentModel
+		for _, comment := range comments {
+			models = append(models, RecordCommentModel{
+				Content:    types.StringValue(comment),
+				Account:    types.StringValue("ops"),
+				ModifiedAt: types.Int64Value(1000),
+			})
+		}
+		list, diags := types.ListValueFrom(ctx, recordCommentType, models)
+		require.False(t, diags.HasError())
+		return list
+	}
+
+	tests := []struct {
+		name     string
+		plan     types.List
+		state    types.List
+		expected *[]Comment
+	}{
+		{
+			name:  "not managed",
+			plan:  commentList(),
+			state: types.ListNull(recordCommentType),
+		},
+		{
+			name:  "unchanged",
+			plan:  commentList("web server"),
+			state: commentList("web server"),
+		},
+		{
+			name:     "added",
+			plan:     commentList("web server"),
+			state:    commentList(),
+			expected: &[]Comment{{Content: "web server", Account: "ops"}},
+		},
+		{
+			name:     "changed",
+			plan:     commentList("load balancer"),
+			state:    commentList("web server"),
+			expected: &[]Comment{{Content: "load balancer", Account: "ops"}},
+		},
+		{
+			name:     "removed",
+			plan:     commentList(),
+			state:    commentList("web server"),
+			expected: &[]Comment{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments, diags := commentsUpdate(ctx, tt.plan, tt.state)
+			require.False(t, diags.HasError())
+			assert.Equal(t, tt.expected, comments)
+		})
+	}
+}
+
+func TestRecord_FakeServerComments(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+	_, err = client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:     "www.example.com.",
+		Type:     "A",
+		TTL:      300,
+		Records:  []Record{{Content: "192.0.2.1"}},
+		Comments: &[]Comment{{Content: "web server", Account: "ops"}},
+	})
+	require.NoError(t, err)
+
+	// Replacing the records alone keeps the comments
+	_, err = client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.2"}},
+	})
+	require.NoError(t, err)
+
+	rrSet, err := client.GetRecordSet(ctx, "example.com.", "WWW.example.com.", "A")
+	require.NoError(t, err)
+	require.NotNil(t, rrSet.Comments)
+	require.Len(t, *rrSet.Comments, 1)
+	comment := (*rrSet.Comments)[0]
+	assert.Equal(t, "web server", comment.Content)
+	assert.Equal(t, "ops", comment.Account)
+	assert.NotZero(t, comment.ModifiedAt)
+
+	_, err = client.GetRecordSet(ctx, "example.com.", "mail.example.com.", "A")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
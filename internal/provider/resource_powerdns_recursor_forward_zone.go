@@ -16,6 +16,7 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var _ resource.Resource = &RecursorForwardZoneResource{}
+var _ resource.ResourceWithModifyPlan = &RecursorForwardZoneResource{}
 
 // RecursorForwardZoneResource defines the resource implementation.
 type RecursorForwardZoneResource struct {
@@ -28,6 +29,7 @@ type RecursorForwardZoneResourceModel struct {
 	Servers          types.List   `tfsdk:"servers"`
 	RecursionDesired types.Bool   `tfsdk:"recursion_desired"`
 	ID               types.String `tfsdk:"id"`
+	Timeouts         types.Object `tfsdk:"timeouts"`
 }
 
 func (r *RecursorForwardZoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -63,6 +65,9 @@ func (r *RecursorForwardZoneResource) Schema(ctx context.Context, req resource.S
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}
 }
 
@@ -76,6 +81,17 @@ func (r *RecursorForwardZoneResource) Configure(ctx context.Context, req resourc
 		return
 	}
 	r.client = client
+
+	requireRecursorServer(&resp.Diagnostics, client, "powerdns_recursor_forward_zone")
+}
+
+func (r *RecursorForwardZoneResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	r.client.requireCapability(&resp.Diagnostics, capabilityRecursorZones, path.Root("zone"))
 }
 
 func (r *RecursorForwardZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -86,6 +102,13 @@ func (r *RecursorForwardZoneResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.Zone.ValueString()
 
 	// Ensure zone name ends with a dot for DNS standards
@@ -121,7 +144,9 @@ func (r *RecursorForwardZoneResource) Create(ctx context.Context, req resource.C
 
 	createdZone, err := r.client.CreateRecursorZone(ctx, recursorZone)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create recursor forward zone", err.Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create recursor forward zone", err, path.Root("zone"), map[string]path.Path{
+			"server": path.Root("servers"),
+		})
 		return
 	}
 
@@ -148,6 +173,13 @@ func (r *RecursorForwardZoneResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.ID.ValueString()
 
 	tflog.SetField(ctx, "zone", zoneName)
@@ -193,6 +225,13 @@ func (r *RecursorForwardZoneResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.Zone.ValueString()
 
 	// Ensure zone name ends with a dot
@@ -253,6 +292,13 @@ func (r *RecursorForwardZoneResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.Zone.ValueString()
 
 	tflog.SetField(ctx, "zone", zoneName)
@@ -262,7 +308,7 @@ func (r *RecursorForwardZoneResource) Delete(ctx context.Context, req resource.D
 	if err != nil {
 		// If the zone doesn't exist, that's actually what we want
 		// Return success to allow Terraform to clean up the state
-		if strings.Contains(err.Error(), "Could not find domain") {
+		if errors.Is(err, ErrNotFound) {
 			tflog.Info(ctx, "Recursor forward zone already deleted", map[string]any{"zone": zoneName})
 			return
 		}
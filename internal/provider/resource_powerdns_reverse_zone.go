@@ -2,8 +2,11 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -44,6 +47,15 @@ func (v CIDRValidator) ValidateString(ctx context.Context, req validator.StringR
 
 // Ensure the implementation satisfies the expected interfaces.
 var _ resource.Resource = &ReverseZoneResource{}
+var _ resource.ResourceWithModifyPlan = &ReverseZoneResource{}
+
+// reverseZoneValidationHints maps keywords of PowerDNS validation errors to
+// the reverse zone attribute they are about.
+var reverseZoneValidationHints = map[string]path.Path{
+	"nameserver": path.Root("nameservers"),
+	"kind":       path.Root("kind"),
+	"tsig":       path.Root("master_tsig_key_ids"),
+}
 
 // ReverseZoneResource defines the resource implementation.
 type ReverseZoneResource struct {
@@ -52,11 +64,14 @@ type ReverseZoneResource struct {
 
 // ReverseZoneResourceModel describes the resource data model.
 type ReverseZoneResourceModel struct {
-	CIDR        types.String `tfsdk:"cidr"`
-	Kind        types.String `tfsdk:"kind"`
-	Nameservers types.List   `tfsdk:"nameservers"`
-	Name        types.String `tfsdk:"name"`
-	ID          types.String `tfsdk:"id"`
+	CIDR             types.String `tfsdk:"cidr"`
+	Kind             types.String `tfsdk:"kind"`
+	Nameservers      types.List   `tfsdk:"nameservers"`
+	Name             types.String `tfsdk:"name"`
+	MasterTSIGKeyIDs types.Set    `tfsdk:"master_tsig_key_ids"`
+	SlaveTSIGKeyIDs  types.Set    `tfsdk:"slave_tsig_key_ids"`
+	ID               types.String `tfsdk:"id"`
+	Timeouts         types.Object `tfsdk:"timeouts"`
 }
 
 func (r *ReverseZoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -88,6 +103,8 @@ func (r *ReverseZoneResource) Schema(ctx context.Context, req resource.SchemaReq
 				MarkdownDescription: "List of nameservers for this zone",
 				Required:            true,
 			},
+			"master_tsig_key_ids": zoneTSIGKeyIDsAttribute("The IDs of the TSIG keys allowed to transfer the zone from this server."),
+			"slave_tsig_key_ids":  zoneTSIGKeyIDsAttribute("The IDs of the TSIG keys used to transfer the zone from its masters."),
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The computed zone name",
 				Computed:            true,
@@ -103,6 +120,9 @@ func (r *ReverseZoneResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}
 }
 
@@ -116,6 +136,17 @@ func (r *ReverseZoneResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_reverse_zone")
+}
+
+func (r *ReverseZoneResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	r.client.checkZoneTSIGKeyIDs(ctx, req, &resp.Diagnostics)
 }
 
 func (r *ReverseZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -126,6 +157,13 @@ func (r *ReverseZoneResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	cidr := data.CIDR.ValueString()
 	tflog.SetField(ctx, "cidr", cidr)
 	tflog.Debug(ctx, "Creating reverse zone")
@@ -152,15 +190,25 @@ func (r *ReverseZoneResource) Create(ctx context.Context, req resource.CreateReq
 		Kind:        data.Kind.ValueString(),
 		Nameservers: nameservers,
 	}
+	if !data.MasterTSIGKeyIDs.IsUnknown() {
+		resp.Diagnostics.Append(data.MasterTSIGKeyIDs.ElementsAs(ctx, &zone.MasterTSIGKeyIDs, false)...)
+	}
+	if !data.SlaveTSIGKeyIDs.IsUnknown() {
+		resp.Diagnostics.Append(data.SlaveTSIGKeyIDs.ElementsAs(ctx, &zone.SlaveTSIGKeyIDs, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	createdZone, err := r.client.CreateZone(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create reverse zone", fmt.Errorf("failed to create reverse zone: %w", err).Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create reverse zone", fmt.Errorf("failed to create reverse zone: %w", err), path.Root("cidr"), reverseZoneValidationHints)
 		return
 	}
 
 	data.ID = types.StringValue(createdZone.Name)
 	data.Name = types.StringValue(createdZone.Name)
+	resp.Diagnostics.Append(setReverseZoneTSIGKeyIDs(ctx, &data, createdZone)...)
 	tflog.Info(ctx, "Created reverse zone", map[string]any{"id": createdZone.Name})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -174,6 +222,13 @@ func (r *ReverseZoneResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.ID.ValueString()
 
 	tflog.SetField(ctx, "zone", zoneName)
@@ -181,25 +236,30 @@ func (r *ReverseZoneResource) Read(ctx context.Context, req resource.ReadRequest
 
 	zone, err := r.client.GetZone(ctx, zoneName)
 	if err != nil {
+		// If zone doesn't exist, clear state
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "Zone not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Failed to read zone", fmt.Errorf("couldn't fetch zone: %w", err).Error())
 		return
 	}
 
-	// If zone doesn't exist, clear state
-	if zone.Name == "" {
-		tflog.Warn(ctx, "Zone not found; removing from state")
-		resp.State.RemoveResource(ctx)
-		return
-	}
-
 	tflog.Info(ctx, "Found reverse zone", map[string]any{"zone": zone.Name, "kind": zone.Kind})
 
 	data.Name = types.StringValue(zone.Name)
 	data.Kind = types.StringValue(zone.Kind)
+	resp.Diagnostics.Append(setReverseZoneTSIGKeyIDs(ctx, &data, zone)...)
 
 	// Read nameservers from NS records
 	nameservers, err := r.client.ListRecordsInRRSet(ctx, zoneName, zoneName, "NS")
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "Zone not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Failed to read nameservers", fmt.Errorf("couldn't fetch zone %s nameservers from PowerDNS: %w", zoneName, err).Error())
 		return
 	}
@@ -215,13 +275,21 @@ func (r *ReverseZoneResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *ReverseZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data ReverseZoneResourceModel
+	var data, state ReverseZoneResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.ID.ValueString()
 
 	tflog.SetField(ctx, "zone", zoneName)
@@ -255,8 +323,17 @@ func (r *ReverseZoneResource) Update(ctx context.Context, req resource.UpdateReq
 		SoaEditAPI: zone.SoaEditAPI,
 	}
 
+	var updateDiags diag.Diagnostics
+	zoneInfo.MasterTSIGKeyIDs, updateDiags = tsigKeyIDsUpdate(ctx, data.MasterTSIGKeyIDs, state.MasterTSIGKeyIDs)
+	resp.Diagnostics.Append(updateDiags...)
+	zoneInfo.SlaveTSIGKeyIDs, updateDiags = tsigKeyIDsUpdate(ctx, data.SlaveTSIGKeyIDs, state.SlaveTSIGKeyIDs)
+	resp.Diagnostics.Append(updateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if err := r.client.UpdateZone(ctx, zoneName, zoneInfo); err != nil {
-		resp.Diagnostics.AddError("Failed to update zone", fmt.Errorf("error updating zone: %w", err).Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to update zone", fmt.Errorf("error updating zone: %w", err), path.Root("cidr"), reverseZoneValidationHints)
 		return
 	}
 
@@ -277,7 +354,7 @@ func (r *ReverseZoneResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	if _, err := r.client.ReplaceRecordSet(ctx, zoneName, rrSet); err != nil {
-		resp.Diagnostics.AddError("Failed to update nameserver records", fmt.Errorf("error updating nameserver records: %w", err).Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to update nameserver records", fmt.Errorf("error updating nameserver records: %w", err), path.Root("nameservers"), nil)
 		return
 	}
 
@@ -292,6 +369,7 @@ func (r *ReverseZoneResource) Update(ctx context.Context, req resource.UpdateReq
 
 	data.Name = types.StringValue(updatedZone.Name)
 	data.Kind = types.StringValue(updatedZone.Kind)
+	resp.Diagnostics.Append(setReverseZoneTSIGKeyIDs(ctx, &data, updatedZone)...)
 
 	// Read updated nameservers
 	nameserversRecords, err := r.client.ListRecordsInRRSet(ctx, zoneName, zoneName, "NS")
@@ -318,12 +396,23 @@ func (r *ReverseZoneResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	zoneName := data.ID.ValueString()
 
 	tflog.SetField(ctx, "zone", zoneName)
 	tflog.Debug(ctx, "Deleting reverse zone")
 
 	if err := r.client.DeleteZone(ctx, zoneName); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "Reverse zone already deleted")
+			return
+		}
 		resp.Diagnostics.AddError("Failed to delete zone", fmt.Errorf("error deleting zone: %w", err).Error())
 		return
 	}
@@ -358,12 +447,24 @@ func (r *ReverseZoneResource) ImportState(ctx context.Context, req resource.Impo
 	dataModel.Name = types.StringValue(zoneName)
 	dataModel.Kind = types.StringValue(zone.Kind)
 	dataModel.ID = types.StringValue(zoneName)
+	dataModel.Timeouts = timeoutsNull()
+	resp.Diagnostics.Append(setReverseZoneTSIGKeyIDs(ctx, &dataModel, zone)...)
 
 	dataModel.Nameservers, _ = types.ListValueFrom(ctx, types.StringType, nameservers)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &dataModel)...)
 }
 
+// setReverseZoneTSIGKeyIDs sets the TSIG keys of data from zone.
+func setReverseZoneTSIGKeyIDs(ctx context.Context, data *ReverseZoneResourceModel, zone ZoneInfo) diag.Diagnostics {
+	var diags, setDiags diag.Diagnostics
+	data.MasterTSIGKeyIDs, setDiags = tsigKeyIDsValue(ctx, zone.MasterTSIGKeyIDs)
+	diags.Append(setDiags...)
+	data.SlaveTSIGKeyIDs, setDiags = tsigKeyIDsValue(ctx, zone.SlaveTSIGKeyIDs)
+	diags.Append(setDiags...)
+	return diags
+}
+
 func NewReverseZoneResource() resource.Resource {
 	return &ReverseZoneResource{}
 }
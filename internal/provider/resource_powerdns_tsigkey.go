@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.Resource = &TSIGKeyResource{}
+var _ resource.ResourceWithImportState = &TSIGKeyResource{}
+var _ resource.ResourceWithModifyPlan = &TSIGKeyResource{}
+
+// TSIGKeyResource defines the resource implementation.
+type TSIGKeyResource struct {
+	client *Client
+}
+
+// tsigAlgorithms are the TSIG algorithms supported by PowerDNS.
+var tsigAlgorithms = []string{
+	"hmac-md5",
+	"hmac-sha1",
+	"hmac-sha224",
+	"hmac-sha256",
+	"hmac-sha384",
+	"hmac-sha512",
+}
+
+// tsigKeyValidationHints maps keywords of PowerDNS validation errors to the
+// TSIG key attribute they are about.
+var tsigKeyValidationHints = map[string]path.Path{
+	"algorithm": path.Root("algorithm"),
+	"secret":    path.Root("key"),
+	"name":      path.Root("name"),
+}
+
+// TSIGKeyResourceModel describes the resource data model.
+type TSIGKeyResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Algorithm    types.String `tfsdk:"algorithm"`
+	Key          types.String `tfsdk:"key"`
+	KeyWO        types.String `tfsdk:"key_wo"`
+	KeyWOVersion types.Int64  `tfsdk:"key_wo_version"`
+	Timeouts     types.Object `tfsdk:"timeouts"`
+}
+
+func (r *TSIGKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tsigkey"
+}
+
+func (r *TSIGKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a TSIG key of the authoritative server.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID PowerDNS assigned to the key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the key",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "The TSIG algorithm of the key, e.g. `hmac-sha256`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(tsigAlgorithms...),
+				},
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded secret of the key. Generated by PowerDNS when not set. Null when `key_wo_version` is set.",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("key_wo")),
+				},
+			},
+			"key_wo": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded secret of the key, never stored in state. Requires `key_wo_version`.",
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("key_wo_version")),
+				},
+			},
+			"key_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Keeps the secret out of state when set. Changing it sends `key_wo` to PowerDNS again, or keeps the current secret when `key_wo` is not set.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}
+}
+
+func (r *TSIGKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type", "Expected *Client")
+		return
+	}
+	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_tsigkey")
+}
+
+func (r *TSIGKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Secrets managed through key_wo are never stored
+	var keyWOVersion types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("key_wo_version"), &keyWOVersion)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !keyWOVersion.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("key"), types.StringNull())...)
+	}
+
+	if r.client != nil && req.State.Raw.IsNull() {
+		r.client.requireCapability(&resp.Diagnostics, capabilityTSIGKeys, path.Root("name"))
+	}
+}
+
+func (r *TSIGKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data TSIGKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	key := TSIGKey{
+		Name:      data.Name.ValueString(),
+		Algorithm: data.Algorithm.ValueString(),
+	}
+	if data.KeyWOVersion.IsNull() {
+		key.Key = data.Key.ValueString()
+	} else {
+		var keyWO types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("key_wo"), &keyWO)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		key.Key = keyWO.ValueString()
+	}
+
+	ctx = tflog.SetField(ctx, "name", key.Name)
+	tflog.Debug(ctx, "Creating PowerDNS TSIG key", map[string]any{"algorithm": key.Algorithm})
+
+	createdKey, err := r.client.CreateTSIGKey(ctx, key)
+	if err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create TSIG key", fmt.Errorf("error creating PowerDNS TSIG key: %w", err), path.Root("name"), tsigKeyValidationHints)
+		return
+	}
+
+	data.setTSIGKey(createdKey)
+	tflog.Info(ctx, "Created PowerDNS TSIG key", map[string]any{"id": createdKey.ID})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TSIGKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data TSIGKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	ctx = tflog.SetField(ctx, "tsigkey_id", data.ID.ValueString())
+	tflog.Debug(ctx, "Reading PowerDNS TSIG key")
+
+	key, err := r.client.GetTSIGKey(ctx, data.ID.ValueString())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "PowerDNS TSIG key not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read TSIG key", fmt.Errorf("couldn't fetch PowerDNS TSIG key: %w", err).Error())
+		return
+	}
+
+	data.setTSIGKey(key)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TSIGKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state TSIGKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	// An empty secret keeps the current one
+	key := TSIGKey{
+		Name:      state.Name.ValueString(),
+		Algorithm: data.Algorithm.ValueString(),
+	}
+	switch {
+	case !data.KeyWOVersion.IsNull():
+		if !data.KeyWOVersion.Equal(state.KeyWOVersion) {
+			var keyWO types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("key_wo"), &keyWO)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			key.Key = keyWO.ValueString()
+		}
+	case !data.Key.IsUnknown() && !data.Key.Equal(state.Key):
+		key.Key = data.Key.ValueString()
+	}
+
+	id := state.ID.ValueString()
+	ctx = tflog.SetField(ctx, "tsigkey_id", id)
+	tflog.Debug(ctx, "Updating PowerDNS TSIG key", map[string]any{"algorithm": key.Algorithm, "new_secret": key.Key != ""})
+
+	updatedKey, err := r.client.UpdateTSIGKey(ctx, id, key)
+	if err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to update TSIG key", fmt.Errorf("error updating PowerDNS TSIG key: %w", err), path.Root("algorithm"), tsigKeyValidationHints)
+		return
+	}
+
+	data.setTSIGKey(updatedKey)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TSIGKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data TSIGKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	ctx = tflog.SetField(ctx, "tsigkey_id", data.ID.ValueString())
+	tflog.Debug(ctx, "Deleting PowerDNS TSIG key")
+
+	if err := r.client.DeleteTSIGKey(ctx, data.ID.ValueString()); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "PowerDNS TSIG key already deleted")
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete TSIG key", fmt.Errorf("error deleting PowerDNS TSIG key: %w", err).Error())
+		return
+	}
+
+	tflog.Info(ctx, "Deleted PowerDNS TSIG key")
+}
+
+func (r *TSIGKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setTSIGKey updates the model from key, as returned by PowerDNS. The secret
+// is left out when managed through key_wo.
+func (m *TSIGKeyResourceModel) setTSIGKey(key TSIGKey) {
+	m.ID = types.StringValue(key.ID)
+	m.Name = types.StringValue(key.Name)
+	m.Algorithm = types.StringValue(key.Algorithm)
+	m.Key = types.StringNull()
+	if m.KeyWOVersion.IsNull() {
+		m.Key = types.StringValue(key.Key)
+	}
+}
+
+func NewTSIGKeyResource() resource.Resource {
+	return &TSIGKeyResource{}
+}
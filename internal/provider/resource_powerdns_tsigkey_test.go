@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccPDNSTSIGKey_basic(t *testing.T) {
+	resource.Test(t, testAccPDNSTSIGKeyCase(t))
+}
+
+func TestTSIGKey_Resource(t *testing.T) {
+	server := pdnstest.New(t)
+	c := testAccPDNSTSIGKeyCase(t)
+	for i := range c.Steps {
+		c.Steps[i].Config = testFakeServerConfig(server, c.Steps[i].Config)
+	}
+	c.CheckDestroy = func(*terraform.State) error {
+		if keys := server.TSIGKeys(); len(keys) != 0 {
+			return fmt.Errorf("expected no TSIG keys, got %d", len(keys))
+		}
+		return nil
+	}
+	testFakeServerUnitTest(t, c)
+}
+
+func testAccPDNSTSIGKeyCase(t *testing.T) resource.TestCase {
+	resourceName := "powerdns_tsigkey.test"
+
+	return resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPDNSTSIGKeyConfig("hmac-sha256", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "tsig-test."),
+					resource.TestCheckResourceAttr(resourceName, "algorithm", "hmac-sha256"),
+					resource.TestCheckResourceAttrSet(resourceName, "key"),
+				),
+			},
+			{
+				Config: testAccPDNSTSIGKeyConfig("hmac-sha512", `key = "c2VjcmV0"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "algorithm", "hmac-sha512"),
+					resource.TestCheckResourceAttr(resourceName, "key", "c2VjcmV0"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	}
+}
+
+func TestAccPDNSTSIGKey_writeOnly(t *testing.T) {
+	resourceName := "powerdns_tsigkey.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// key_wo is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPDNSTSIGKeyConfig("hmac-sha512", `key_wo = "b3RoZXI="
+	key_wo_version = 1`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr(resourceName, "key"),
+					resource.TestCheckNoResourceAttr(resourceName, "key_wo"),
+					resource.TestCheckResourceAttr(resourceName, "key_wo_version", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPDNSTSIGKeyConfig(algorithm string, key string) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_tsigkey" "test" {
+	name      = "tsig-test"
+	algorithm = %q
+	%s
+}`, algorithm, key)
+}
+
+func TestAccPDNSTSIGKeyEphemeral_basic(t *testing.T) {
+	resource.Test(t, testAccPDNSTSIGKeyEphemeralCase(t, testAccPDNSTSIGKeyEphemeralConfig))
+}
+
+func TestTSIGKey_EphemeralResource(t *testing.T) {
+	server := pdnstest.New(t)
+	testFakeServerUnitTest(t, testAccPDNSTSIGKeyEphemeralCase(t, testFakeServerConfig(server, testAccPDNSTSIGKeyEphemeralConfig)))
+}
+
+// testAccPDNSTSIGKeyEphemeralCase hands the ephemeral key to the echo
+// provider, the only way to look at ephemeral values in a test.
+func testAccPDNSTSIGKeyEphemeralCase(t *testing.T, config string) resource.TestCase {
+	return resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"powerdns": testAccProtoV6ProviderFactories["powerdns"],
+			"echo":     echoprovider.NewProviderServer(),
+		},
+		// Ephemeral resources were introduced in Terraform 1.10
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("echo.test", "data.id", "tsig-ephemeral."),
+					resource.TestCheckResourceAttr("echo.test", "data.algorithm", "hmac-sha256"),
+					resource.TestCheckResourceAttr("echo.test", "data.key", "c2VjcmV0"),
+				),
+			},
+		},
+	}
+}
+
+const testAccPDNSTSIGKeyEphemeralConfig = `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_tsigkey" "test" {
+	name      = "tsig-ephemeral"
+	algorithm = "hmac-sha256"
+	key       = "c2VjcmV0"
+}
+
+# The ID is only known once the key exists, which defers opening the
+# ephemeral resource until then
+ephemeral "powerdns_tsigkey" "test" {
+	name = powerdns_tsigkey.test.id
+}
+
+provider "echo" {
+	data = ephemeral.powerdns_tsigkey.test
+}
+
+resource "echo" "test" {}`
+
+func TestTSIGKey_EphemeralOpen(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeServerClient(t)
+	created, err := client.CreateTSIGKey(ctx, TSIGKey{Name: "axfr", Algorithm: "hmac-sha256"})
+	require.NoError(t, err)
+
+	e := &TSIGKeyEphemeralResource{client: client}
+	schemaResp := &ephemeral.SchemaResponse{}
+	e.Schema(ctx, ephemeral.SchemaRequest{}, schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	open := func(name string) *ephemeral.OpenResponse {
+		config := tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, name),
+			"id":        tftypes.NewValue(tftypes.String, nil),
+			"algorithm": tftypes.NewValue(tftypes.String, nil),
+			"key":       tftypes.NewValue(tftypes.String, nil),
+		})
+		resp := &ephemeral.OpenResponse{
+			Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+		}
+		e.Open(ctx, ephemeral.OpenRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}, resp)
+		return resp
+	}
+
+	// The key is found whatever the case and trailing dot of its name
+	resp := open("AXFR.")
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	var data TSIGKeyEphemeralResourceModel
+	require.False(t, resp.Result.Get(ctx, &data).HasError())
+	assert.Equal(t, "axfr.", data.ID.ValueString())
+	assert.Equal(t, "hmac-sha256", data.Algorithm.ValueString())
+	assert.Equal(t, created.Key, data.Key.ValueString())
+
+	resp = open("notify")
+	require.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "TSIG key not found", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func TestTSIGKey_SameTSIGKeyName(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{name: "identical", a: "axfr", b: "axfr", expected: true},
+		{name: "trailing dot", a: "axfr.", b: "axfr", expected: true},
+		{name: "case", a: "AXFR", b: "axfr.", expected: true},
+		{name: "different", a: "axfr", b: "notify", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sameDNSName(tt.a, tt.b))
+		})
+	}
+}
+
+func TestTSIGKey_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	// PowerDNS generates the secret when none is given
+	created, err := client.CreateTSIGKey(ctx, TSIGKey{Name: "axfr", Algorithm: "hmac-sha256"})
+	require.NoError(t, err)
+	assert.Equal(t, "axfr.", created.ID)
+	assert.NotEmpty(t, created.Key)
+
+	_, err = client.CreateTSIGKey(ctx, TSIGKey{Name: "bad", Algorithm: "hmac-sha3"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+
+	// An empty secret keeps the current one
+	updated, err := client.UpdateTSIGKey(ctx, created.ID, TSIGKey{Name: "axfr", Algorithm: "hmac-sha512"})
+	require.NoError(t, err)
+	assert.Equal(t, "hmac-sha512", updated.Algorithm)
+	assert.Equal(t, created.Key, updated.Key)
+
+	updated, err = client.UpdateTSIGKey(ctx, created.ID, TSIGKey{Name: "axfr", Algorithm: "hmac-sha512", Key: "c2VjcmV0"})
+	require.NoError(t, err)
+	assert.Equal(t, "c2VjcmV0", updated.Key)
+
+	keys, err := client.ListTSIGKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Empty(t, keys[0].Key)
+
+	found, err := client.FindTSIGKey(ctx, "AXFR.")
+	require.NoError(t, err)
+	assert.Equal(t, "c2VjcmV0", found.Key)
+
+	_, err = client.FindTSIGKey(ctx, "notify")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, client.DeleteTSIGKey(ctx, created.ID))
+	assert.Empty(t, server.TSIGKeys())
+
+	_, err = client.GetTSIGKey(ctx, created.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
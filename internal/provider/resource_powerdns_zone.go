@@ -2,15 +2,18 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -21,21 +24,47 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var _ resource.Resource = &ZoneResource{}
+var _ resource.ResourceWithModifyPlan = &ZoneResource{}
 
 // ZoneResource defines the resource implementation.
 type ZoneResource struct {
 	client *Client
 }
 
+// zoneValidationHints maps keywords of PowerDNS validation errors to the zone
+// attribute they are about.
+var zoneValidationHints = map[string]path.Path{
+	"nameserver":   path.Root("nameservers"),
+	"master":       path.Root("masters"),
+	"kind":         path.Root("kind"),
+	"account":      path.Root("account"),
+	"soa_edit_api": path.Root("soa_edit_api"),
+	"soa-edit-api": path.Root("soa_edit_api"),
+	"dnssec":       path.Root("dnssec"),
+	"nsec3":        path.Root("nsec3param"),
+	"narrow":       path.Root("nsec3narrow"),
+	"presigned":    path.Root("presigned"),
+	"rectify":      path.Root("api_rectify"),
+	"tsig":         path.Root("master_tsig_key_ids"),
+}
+
 // ZoneResourceModel describes the resource data model.
 type ZoneResourceModel struct {
-	Name        types.String `tfsdk:"name"`
-	Kind        types.String `tfsdk:"kind"`
-	Account     types.String `tfsdk:"account"`
-	Nameservers types.Set    `tfsdk:"nameservers"`
-	Masters     types.Set    `tfsdk:"masters"`
-	SoaEditAPI  types.String `tfsdk:"soa_edit_api"`
-	ID          types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Kind             types.String `tfsdk:"kind"`
+	Account          types.String `tfsdk:"account"`
+	Nameservers      types.Set    `tfsdk:"nameservers"`
+	Masters          types.Set    `tfsdk:"masters"`
+	SoaEditAPI       types.String `tfsdk:"soa_edit_api"`
+	DNSSec           types.Bool   `tfsdk:"dnssec"`
+	Nsec3Param       types.String `tfsdk:"nsec3param"`
+	Nsec3Narrow      types.Bool   `tfsdk:"nsec3narrow"`
+	Presigned        types.Bool   `tfsdk:"presigned"`
+	APIRectify       types.Bool   `tfsdk:"api_rectify"`
+	MasterTSIGKeyIDs types.Set    `tfsdk:"master_tsig_key_ids"`
+	SlaveTSIGKeyIDs  types.Set    `tfsdk:"slave_tsig_key_ids"`
+	ID               types.String `tfsdk:"id"`
+	Timeouts         types.Object `tfsdk:"timeouts"`
 }
 
 func (r *ZoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -53,13 +82,13 @@ func (r *ZoneResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 			"kind": schema.StringAttribute{
-				MarkdownDescription: "The kind of the zone",
+				MarkdownDescription: "The kind of the zone. `Producer` and `Consumer` require PowerDNS >= 4.7.",
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf("Native", "Master", "Slave"),
+					stringvalidator.OneOf("Native", "Master", "Slave", "Producer", "Consumer"),
 				},
 			},
 			"account": schema.StringAttribute{
@@ -90,6 +119,48 @@ func (r *ZoneResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				MarkdownDescription: "SOA edit API setting",
 				Optional:            true,
 			},
+			"dnssec": schema.BoolAttribute{
+				MarkdownDescription: "Whether the zone is signed with DNSSEC. Enabling it generates a default key, disabling it removes all the keys of the zone.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"nsec3param": schema.StringAttribute{
+				MarkdownDescription: "The NSEC3PARAM of the zone, e.g. `1 0 0 -`. An empty string selects NSEC. Requires `dnssec`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"nsec3narrow": schema.BoolAttribute{
+				MarkdownDescription: "Whether NSEC3 records are served in narrow mode. Only used along with `nsec3param`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"presigned": schema.BoolAttribute{
+				MarkdownDescription: "Whether the zone is served presigned, with signatures transferred from its master.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"api_rectify": schema.BoolAttribute{
+				MarkdownDescription: "Whether the zone is rectified after every change made through the API. Defaults to the `default-api-rectify` setting of the server.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"master_tsig_key_ids": zoneTSIGKeyIDsAttribute("The IDs of the TSIG keys allowed to transfer the zone from this server."),
+			"slave_tsig_key_ids":  zoneTSIGKeyIDsAttribute("The IDs of the TSIG keys used to transfer the zone from its masters."),
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Zone identifier",
@@ -98,6 +169,9 @@ func (r *ZoneResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}
 }
 
@@ -111,6 +185,28 @@ func (r *ZoneResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone")
+}
+
+func (r *ZoneResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	r.client.checkZoneTSIGKeyIDs(ctx, req, &resp.Diagnostics)
+
+	var kind types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("kind"), &kind)...)
+	if resp.Diagnostics.HasError() || kind.IsNull() || kind.IsUnknown() {
+		return
+	}
+
+	switch normalizeKind(kind.ValueString()) {
+	case "Producer", "Consumer":
+		r.client.requireCapability(&resp.Diagnostics, capabilityProducerConsumer, path.Root("kind"))
+	}
 }
 
 func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -121,6 +217,13 @@ func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	// Normalize kind to match API response format
 	normalizedKind := normalizeKind(data.Kind.ValueString())
 	if normalizedKind != data.Kind.ValueString() {
@@ -181,6 +284,22 @@ func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, r
 		Account:     data.Account.ValueString(),
 		Nameservers: nameservers,
 		SoaEditAPI:  data.SoaEditAPI.ValueString(),
+		DNSSec:      data.DNSSec.ValueBool(),
+		Nsec3Param:  data.Nsec3Param.ValueString(),
+		Nsec3Narrow: data.Nsec3Narrow.ValueBool(),
+		Presigned:   data.Presigned.ValueBool(),
+	}
+	if !data.APIRectify.IsUnknown() {
+		zoneInfo.APIRectify = data.APIRectify.ValueBoolPointer()
+	}
+	if !data.MasterTSIGKeyIDs.IsUnknown() {
+		resp.Diagnostics.Append(data.MasterTSIGKeyIDs.ElementsAs(ctx, &zoneInfo.MasterTSIGKeyIDs, false)...)
+	}
+	if !data.SlaveTSIGKeyIDs.IsUnknown() {
+		resp.Diagnostics.Append(data.SlaveTSIGKeyIDs.ElementsAs(ctx, &zoneInfo.SlaveTSIGKeyIDs, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	if len(masters) > 0 {
@@ -198,7 +317,7 @@ func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	createdZoneInfo, err := r.client.CreateZone(ctx, zoneInfo)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create zone", err.Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create zone", err, path.Root("name"), zoneValidationHints)
 		return
 	}
 
@@ -207,6 +326,8 @@ func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, r
 	data.Kind = types.StringValue(createdZoneInfo.Kind)
 	data.Account = types.StringValue(createdZoneInfo.Account)
 	data.SoaEditAPI = types.StringValue(createdZoneInfo.SoaEditAPI)
+	setZoneDNSSEC(&data, createdZoneInfo)
+	resp.Diagnostics.Append(setZoneTSIGKeyIDs(ctx, &data, createdZoneInfo)...)
 
 	// Set nameservers and masters from the response if available
 	if !strings.EqualFold(createdZoneInfo.Kind, "Slave") {
@@ -269,24 +390,32 @@ func (r *ZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	tflog.SetField(ctx, "zone_id", data.ID.ValueString())
 	tflog.Debug(ctx, "Reading PowerDNS Zone")
 
 	zoneInfo, err := r.client.GetZone(ctx, data.ID.ValueString())
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "Zone not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Failed to read zone", fmt.Errorf("couldn't fetch PowerDNS Zone: %w", err).Error())
 		return
 	}
 
-	if zoneInfo.Name == "" {
-		tflog.Warn(ctx, "Zone not found; removing from state")
-		resp.State.RemoveResource(ctx)
-		return
-	}
-
 	data.Name = types.StringValue(zoneInfo.Name)
 	data.Kind = types.StringValue(zoneInfo.Kind)
 	data.SoaEditAPI = types.StringValue(zoneInfo.SoaEditAPI)
+	setZoneDNSSEC(&data, zoneInfo)
+	resp.Diagnostics.Append(setZoneTSIGKeyIDs(ctx, &data, zoneInfo)...)
 
 	// Handle computed fields that might be empty
 	if zoneInfo.Account == "" {
@@ -321,6 +450,11 @@ func (r *ZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if normalizeKind(zoneInfo.Kind) != "Slave" {
 		nameservers, err := r.client.ListRecordsInRRSet(ctx, zoneInfo.Name, zoneInfo.Name, "NS")
 		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				tflog.Warn(ctx, "Zone not found; removing from state")
+				resp.State.RemoveResource(ctx)
+				return
+			}
 			resp.Diagnostics.AddError("Failed to read nameservers", fmt.Errorf("couldn't fetch zone %s nameservers from PowerDNS: %w", zoneInfo.Name, err).Error())
 			return
 		}
@@ -337,12 +471,20 @@ func (r *ZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 func (r *ZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data ZoneResourceModel
+	var data, state ZoneResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	defer cancel()
 
 	// Normalize kind to match API response format
 	normalizedKind := normalizeKind(data.Kind.ValueString())
@@ -359,9 +501,19 @@ func (r *ZoneResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		Account:    data.Account.ValueString(),
 		SoaEditAPI: data.SoaEditAPI.ValueString(),
 	}
+	setZoneDNSSECUpdate(&zoneInfo, data, state)
+
+	var updateDiags diag.Diagnostics
+	zoneInfo.MasterTSIGKeyIDs, updateDiags = tsigKeyIDsUpdate(ctx, data.MasterTSIGKeyIDs, state.MasterTSIGKeyIDs)
+	resp.Diagnostics.Append(updateDiags...)
+	zoneInfo.SlaveTSIGKeyIDs, updateDiags = tsigKeyIDsUpdate(ctx, data.SlaveTSIGKeyIDs, state.SlaveTSIGKeyIDs)
+	resp.Diagnostics.Append(updateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	if err := r.client.UpdateZone(ctx, data.ID.ValueString(), zoneInfo); err != nil {
-		resp.Diagnostics.AddError("Failed to update zone", fmt.Errorf("error updating PowerDNS Zone: %w", err).Error())
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to update zone", fmt.Errorf("error updating PowerDNS Zone: %w", err), path.Root("name"), zoneValidationHints)
 		return
 	}
 
@@ -376,6 +528,8 @@ func (r *ZoneResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	data.Kind = types.StringValue(updatedZoneInfo.Kind)
 	data.Account = types.StringValue(updatedZoneInfo.Account)
 	data.SoaEditAPI = types.StringValue(updatedZoneInfo.SoaEditAPI)
+	setZoneDNSSEC(&data, updatedZoneInfo)
+	resp.Diagnostics.Append(setZoneTSIGKeyIDs(ctx, &data, updatedZoneInfo)...)
 
 	// Handle computed fields that might be empty
 	if updatedZoneInfo.Account == "" {
@@ -417,10 +571,21 @@ func (r *ZoneResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	tflog.SetField(ctx, "zone_id", data.ID.ValueString())
 	tflog.Debug(ctx, "Deleting PowerDNS Zone")
 
 	if err := r.client.DeleteZone(ctx, data.ID.ValueString()); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "PowerDNS Zone already deleted")
+			return
+		}
 		resp.Diagnostics.AddError("Failed to delete zone", fmt.Errorf("error deleting PowerDNS Zone: %w", err).Error())
 		return
 	}
@@ -436,6 +601,47 @@ func NewZoneResource() resource.Resource {
 	return &ZoneResource{}
 }
 
+// setZoneDNSSEC sets the DNSSEC settings of data from zoneInfo.
+func setZoneDNSSEC(data *ZoneResourceModel, zoneInfo ZoneInfo) {
+	data.DNSSec = types.BoolValue(zoneInfo.DNSSec)
+	data.Nsec3Param = types.StringValue(zoneInfo.Nsec3Param)
+	data.Nsec3Narrow = types.BoolValue(zoneInfo.Nsec3Narrow)
+	data.Presigned = types.BoolValue(zoneInfo.Presigned)
+	data.APIRectify = types.BoolValue(zoneInfo.APIRectify != nil && *zoneInfo.APIRectify)
+}
+
+// setZoneTSIGKeyIDs sets the TSIG keys of data from zoneInfo.
+func setZoneTSIGKeyIDs(ctx context.Context, data *ZoneResourceModel, zoneInfo ZoneInfo) diag.Diagnostics {
+	var diags, setDiags diag.Diagnostics
+	data.MasterTSIGKeyIDs, setDiags = tsigKeyIDsValue(ctx, zoneInfo.MasterTSIGKeyIDs)
+	diags.Append(setDiags...)
+	data.SlaveTSIGKeyIDs, setDiags = tsigKeyIDsValue(ctx, zoneInfo.SlaveTSIGKeyIDs)
+	diags.Append(setDiags...)
+	return diags
+}
+
+// setZoneDNSSECUpdate adds the DNSSEC settings changed from state to plan to
+// zoneInfo. Unchanged settings are left out, so that keys managed outside of
+// the zone are not removed. PowerDNS only reads nsec3narrow along with
+// nsec3param, so both are sent when either changes.
+func setZoneDNSSECUpdate(zoneInfo *ZoneInfoUpd, plan ZoneResourceModel, state ZoneResourceModel) {
+	if !plan.DNSSec.IsUnknown() && !plan.DNSSec.Equal(state.DNSSec) {
+		zoneInfo.DNSSec = plan.DNSSec.ValueBoolPointer()
+	}
+	if !plan.Nsec3Param.IsUnknown() && !plan.Nsec3Narrow.IsUnknown() &&
+		(!plan.Nsec3Param.Equal(state.Nsec3Param) || !plan.Nsec3Narrow.Equal(state.Nsec3Narrow)) {
+		nsec3Param := plan.Nsec3Param.ValueString()
+		zoneInfo.Nsec3Param = &nsec3Param
+		zoneInfo.Nsec3Narrow = plan.Nsec3Narrow.ValueBoolPointer()
+	}
+	if !plan.Presigned.IsUnknown() && !plan.Presigned.Equal(state.Presigned) {
+		zoneInfo.Presigned = plan.Presigned.ValueBoolPointer()
+	}
+	if !plan.APIRectify.IsUnknown() && !plan.APIRectify.Equal(state.APIRectify) {
+		zoneInfo.APIRectify = plan.APIRectify.ValueBoolPointer()
+	}
+}
+
 // normalizeKind normalizes the kind value to title case.
 func normalizeKind(kind string) string {
 	switch strings.ToLower(kind) {
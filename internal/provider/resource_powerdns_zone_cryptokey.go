@@ -0,0 +1,428 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.Resource = &ZoneCryptoKeyResource{}
+var _ resource.ResourceWithImportState = &ZoneCryptoKeyResource{}
+
+// ZoneCryptoKeyResource defines the resource implementation.
+type ZoneCryptoKeyResource struct {
+	client *Client
+}
+
+// cryptoKeyValidationHints maps keywords of PowerDNS validation errors to the
+// cryptokey attribute they are about.
+var cryptoKeyValidationHints = map[string]path.Path{
+	"algorithm":   path.Root("algorithm"),
+	"bit":         path.Root("bits"),
+	"keytype":     path.Root("keytype"),
+	"private key": path.Root("privatekey"),
+}
+
+// dnssecAlgorithms maps the names and shorthands of DNSSEC algorithms accepted
+// by PowerDNS to their number.
+var dnssecAlgorithms = map[string]int{
+	"RSASHA1":            5,
+	"RSASHA1-NSEC3-SHA1": 7,
+	"RSASHA256":          8,
+	"RSASHA512":          10,
+	"ECDSA256":           13,
+	"ECDSAP256SHA256":    13,
+	"ECDSA384":           14,
+	"ECDSAP384SHA384":    14,
+	"ED25519":            15,
+	"ED448":              16,
+}
+
+// ZoneCryptoKeyResourceModel describes the resource data model.
+type ZoneCryptoKeyResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Zone       types.String `tfsdk:"zone"`
+	KeyID      types.Int64  `tfsdk:"key_id"`
+	KeyType    types.String `tfsdk:"keytype"`
+	Algorithm  types.String `tfsdk:"algorithm"`
+	Bits       types.Int64  `tfsdk:"bits"`
+	Active     types.Bool   `tfsdk:"active"`
+	Published  types.Bool   `tfsdk:"published"`
+	PrivateKey types.String `tfsdk:"privatekey"`
+	Flags      types.Int64  `tfsdk:"flags"`
+	DNSKey     types.String `tfsdk:"dnskey"`
+	DS         types.List   `tfsdk:"ds"`
+	Timeouts   types.Object `tfsdk:"timeouts"`
+}
+
+func (r *ZoneCryptoKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_cryptokey"
+}
+
+func (r *ZoneCryptoKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a DNSSEC key of a zone.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Key identifier, made of the zone name and the key ID separated by `/`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "The name of the zone the key belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The ID PowerDNS assigned to the key",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"keytype": schema.StringAttribute{
+				MarkdownDescription: "The type of the key: `ksk`, `zsk` or `csk`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("ksk", "zsk", "csk"),
+				},
+			},
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "The DNSSEC algorithm of the key, by name (e.g. `ECDSAP256SHA256` or `ed25519`) or number. Defaults to the server's default algorithm.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bits": schema.Int64Attribute{
+				MarkdownDescription: "The size of the key in bits, for algorithms with a variable key size such as RSA",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"active": schema.BoolAttribute{
+				MarkdownDescription: "Whether the key is used to sign the zone. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"published": schema.BoolAttribute{
+				MarkdownDescription: "Whether the DNSKEY record of the key is published in the zone. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"privatekey": schema.StringAttribute{
+				MarkdownDescription: "A private key in the BIND format to import, instead of generating one",
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"flags": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The DNSKEY flags of the key",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"dnskey": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The DNSKEY record of the key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ds": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "The DS records of the key, to publish in the parent zone. Empty for ZSKs.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}
+}
+
+func (r *ZoneCryptoKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type", "Expected *Client")
+		return
+	}
+	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone_cryptokey")
+}
+
+func (r *ZoneCryptoKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ZoneCryptoKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	key := CryptoKey{
+		KeyType:    data.KeyType.ValueString(),
+		Active:     data.Active.ValueBool(),
+		Algorithm:  data.Algorithm.ValueString(),
+		Bits:       data.Bits.ValueInt64(),
+		PrivateKey: data.PrivateKey.ValueString(),
+	}
+	if !data.Published.IsNull() && !data.Published.IsUnknown() {
+		published := data.Published.ValueBool()
+		key.Published = &published
+	}
+
+	zoneName := data.Zone.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	tflog.Debug(ctx, "Creating PowerDNS cryptokey", map[string]any{"keytype": key.KeyType})
+
+	createdKey, err := r.client.CreateCryptoKey(ctx, zoneName, key)
+	if err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to create cryptokey", fmt.Errorf("error creating PowerDNS cryptokey: %w", err), path.Root("zone"), cryptoKeyValidationHints)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setCryptoKey(ctx, zoneName, createdKey)...)
+	tflog.Info(ctx, "Created PowerDNS cryptokey", map[string]any{"id": data.ID.ValueString()})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneCryptoKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ZoneCryptoKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	zoneName := data.Zone.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	ctx = tflog.SetField(ctx, "key_id", data.KeyID.ValueInt64())
+	tflog.Debug(ctx, "Reading PowerDNS cryptokey")
+
+	key, err := r.client.GetCryptoKey(ctx, zoneName, data.KeyID.ValueInt64())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "PowerDNS cryptokey not found; removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to read cryptokey", fmt.Errorf("couldn't fetch PowerDNS cryptokey: %w", err), path.Root("key_id"), cryptoKeyValidationHints)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setCryptoKey(ctx, zoneName, key)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneCryptoKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state ZoneCryptoKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	// Only active and published change in place, anything else replaces the key
+	update := CryptoKeyUpd{
+		Active:    state.Active.ValueBool(),
+		Published: state.Published.ValueBool(),
+	}
+	if !data.Active.IsUnknown() {
+		update.Active = data.Active.ValueBool()
+	}
+	if !data.Published.IsUnknown() {
+		update.Published = data.Published.ValueBool()
+	}
+
+	zoneName := data.Zone.ValueString()
+	keyID := state.KeyID.ValueInt64()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	ctx = tflog.SetField(ctx, "key_id", keyID)
+	tflog.Debug(ctx, "Updating PowerDNS cryptokey", map[string]any{"active": update.Active, "published": update.Published})
+
+	if err := r.client.UpdateCryptoKey(ctx, zoneName, keyID, update); err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to update cryptokey", fmt.Errorf("error updating PowerDNS cryptokey: %w", err), path.Root("active"), cryptoKeyValidationHints)
+		return
+	}
+
+	key, err := r.client.GetCryptoKey(ctx, zoneName, keyID)
+	if err != nil {
+		addAPIErrorDiagnostic(&resp.Diagnostics, "Failed to read cryptokey", fmt.Errorf("couldn't fetch PowerDNS cryptokey: %w", err), path.Root("key_id"), cryptoKeyValidationHints)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setCryptoKey(ctx, zoneName, key)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneCryptoKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ZoneCryptoKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	ctx = tflog.SetField(ctx, "zone", data.Zone.ValueString())
+	ctx = tflog.SetField(ctx, "key_id", data.KeyID.ValueInt64())
+	tflog.Debug(ctx, "Deleting PowerDNS cryptokey")
+
+	if err := r.client.DeleteCryptoKey(ctx, data.Zone.ValueString(), data.KeyID.ValueInt64()); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "PowerDNS cryptokey already deleted")
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete cryptokey", fmt.Errorf("error deleting PowerDNS cryptokey: %w", err).Error())
+		return
+	}
+
+	tflog.Info(ctx, "Deleted PowerDNS cryptokey")
+}
+
+func (r *ZoneCryptoKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tflog.Info(ctx, "Importing PowerDNS cryptokey", map[string]any{"id": req.ID})
+
+	zoneName, keyID, err := parseCryptoKeyID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("zone"), zoneName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key_id"), keyID)...)
+}
+
+// setCryptoKey updates the model from key, as returned by PowerDNS. The
+// configured algorithm is kept when it names the same algorithm differently.
+func (m *ZoneCryptoKeyResourceModel) setCryptoKey(ctx context.Context, zoneName string, key CryptoKey) diag.Diagnostics {
+	m.ID = types.StringValue(cryptoKeyID(zoneName, key.ID))
+	m.KeyID = types.Int64Value(key.ID)
+	m.KeyType = types.StringValue(strings.ToLower(key.KeyType))
+	if m.Algorithm.IsNull() || m.Algorithm.IsUnknown() || !sameDNSSECAlgorithm(m.Algorithm.ValueString(), key.Algorithm) {
+		m.Algorithm = types.StringValue(key.Algorithm)
+	}
+	m.Bits = types.Int64Value(key.Bits)
+	m.Active = types.BoolValue(key.Active)
+	m.Published = types.BoolValue(key.Published == nil || *key.Published)
+	m.Flags = types.Int64Value(key.Flags)
+	m.DNSKey = types.StringValue(key.DNSKey)
+
+	ds := key.DS
+	if ds == nil {
+		ds = []string{}
+	}
+	var diags diag.Diagnostics
+	m.DS, diags = types.ListValueFrom(ctx, types.StringType, ds)
+	return diags
+}
+
+// cryptoKeyID returns the resource ID of a key, "<zone>/<key id>".
+func cryptoKeyID(zoneName string, keyID int64) string {
+	return fmt.Sprintf("%s/%d", zoneName, keyID)
+}
+
+// parseCryptoKeyID splits a resource ID built by cryptoKeyID. The zone is
+// split at the last '/', since classless reverse zones may contain one.
+func parseCryptoKeyID(id string) (string, int64, error) {
+	sep := strings.LastIndex(id, "/")
+	if sep <= 0 {
+		return "", 0, fmt.Errorf("expected an ID of the form <zone>/<key id>, got %q", id)
+	}
+	keyID, err := strconv.ParseInt(id[sep+1:], 10, 64)
+	if err != nil || keyID <= 0 {
+		return "", 0, fmt.Errorf("invalid key id in %q, expected a positive number", id)
+	}
+	return id[:sep], keyID, nil
+}
+
+// sameDNSSECAlgorithm reports whether a and b name the same DNSSEC algorithm,
+// each by name, shorthand or number.
+func sameDNSSECAlgorithm(a string, b string) bool {
+	number := func(algorithm string) int {
+		if n, err := strconv.Atoi(algorithm); err == nil {
+			return n
+		}
+		return dnssecAlgorithms[strings.ToUpper(algorithm)]
+	}
+	na, nb := number(a), number(b)
+	if na == 0 || nb == 0 {
+		return strings.EqualFold(a, b)
+	}
+	return na == nb
+}
+
+func NewZoneCryptoKeyResource() resource.Resource {
+	return &ZoneCryptoKeyResource{}
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccPDNSZoneCryptoKey_basic(t *testing.T) {
+	resourceName := "powerdns_zone_cryptokey.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPDNSZoneCryptoKeyConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "keytype", "ksk"),
+					resource.TestCheckResourceAttr(resourceName, "algorithm", "ECDSAP256SHA256"),
+					resource.TestCheckResourceAttr(resourceName, "active", "false"),
+					resource.TestCheckResourceAttr(resourceName, "published", "true"),
+					resource.TestCheckResourceAttr(resourceName, "flags", "257"),
+					resource.TestCheckResourceAttrSet(resourceName, "key_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "dnskey"),
+					resource.TestCheckResourceAttrSet(resourceName, "ds.0"),
+				),
+			},
+			{
+				Config: testAccPDNSZoneCryptoKeyConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "active", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"timeouts",
+				},
+			},
+		},
+	})
+}
+
+func testAccPDNSZoneCryptoKeyConfig(active bool) string {
+	activeValue := "false"
+	if active {
+		activeValue = "true"
+	}
+	return `
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_zone" "test" {
+	name        = "cryptokey.sysa.abc."
+	kind        = "Native"
+	nameservers = ["ns1.sysa.abc."]
+}
+
+resource "powerdns_zone_cryptokey" "test" {
+	zone      = powerdns_zone.test.name
+	keytype   = "ksk"
+	algorithm = "ECDSAP256SHA256"
+	active    = ` + activeValue + `
+}`
+}
+
+func TestZoneCryptoKey_Resource(t *testing.T) {
+	resourceName := "powerdns_zone_cryptokey.test"
+	server := pdnstest.New(t)
+	checkKey := func(active bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			keys := server.CryptoKeys("cryptokey.sysa.abc.")
+			if len(keys) != 1 {
+				return fmt.Errorf("expected 1 cryptokey, got %d", len(keys))
+			}
+			if keys[0].KeyType != "ksk" || keys[0].Active != active || !keys[0].Published {
+				return fmt.Errorf("unexpected cryptokey %+v", keys[0])
+			}
+			return nil
+		}
+	}
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneCryptoKeyConfig(false)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkKey(false),
+					resource.TestCheckResourceAttr(resourceName, "flags", "257"),
+					resource.TestCheckResourceAttrSet(resourceName, "ds.0"),
+				),
+			},
+			// Activating the key is done in place
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneCryptoKeyConfig(true)),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkKey(true),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestZoneCryptoKey_ParseCryptoKeyID(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		expectedZone  string
+		expectedKeyID int64
+		expectError   bool
+	}{
+		{name: "zone and key", id: "example.com./12", expectedZone: "example.com.", expectedKeyID: 12},
+		{name: "classless reverse zone", id: "0/26.2.0.192.in-addr.arpa./3", expectedZone: "0/26.2.0.192.in-addr.arpa.", expectedKeyID: 3},
+		{name: "missing key", id: "example.com.", expectError: true},
+		{name: "missing zone", id: "/1", expectError: true},
+		{name: "invalid key", id: "example.com./ksk", expectError: true},
+		{name: "zero key", id: "example.com./0", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, keyID, err := parseCryptoKeyID(tt.id)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedZone, zone)
+			assert.Equal(t, tt.expectedKeyID, keyID)
+			assert.Equal(t, tt.id, cryptoKeyID(zone, keyID))
+		})
+	}
+}
+
+func TestZoneCryptoKey_SameDNSSECAlgorithm(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "ECDSAP256SHA256", b: "ECDSAP256SHA256", expected: true},
+		{a: "ecdsa256", b: "ECDSAP256SHA256", expected: true},
+		{a: "13", b: "ECDSAP256SHA256", expected: true},
+		{a: "ed25519", b: "ED25519", expected: true},
+		{a: "rsasha256", b: "RSASHA512", expected: false},
+		{a: "custom", b: "CUSTOM", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sameDNSSECAlgorithm(tt.a, tt.b))
+		})
+	}
+}
+
+func TestZoneCryptoKey_Client(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Native", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	created, err := client.CreateCryptoKey(ctx, "example.com.", CryptoKey{KeyType: "csk", Algorithm: "ed25519", Active: true})
+	require.NoError(t, err)
+	assert.Equal(t, "ED25519", created.Algorithm)
+	assert.Equal(t, int64(257), created.Flags)
+	assert.NotEmpty(t, created.DS)
+	require.NotNil(t, created.Published)
+	assert.True(t, *created.Published)
+
+	require.NoError(t, client.UpdateCryptoKey(ctx, "example.com.", created.ID, CryptoKeyUpd{Active: false, Published: true}))
+	key, err := client.GetCryptoKey(ctx, "example.com.", created.ID)
+	require.NoError(t, err)
+	assert.False(t, key.Active)
+	assert.Equal(t, created.DNSKey, key.DNSKey)
+
+	keys := server.CryptoKeys("example.com.")
+	require.Len(t, keys, 1)
+	assert.False(t, keys[0].Active)
+	assert.True(t, keys[0].Published)
+
+	require.NoError(t, client.DeleteCryptoKey(ctx, "example.com.", created.ID))
+	_, err = client.GetCryptoKey(ctx, "example.com.", created.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
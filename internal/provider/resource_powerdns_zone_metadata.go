@@ -0,0 +1,390 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.Resource = &ZoneMetadataResource{}
+var _ resource.ResourceWithImportState = &ZoneMetadataResource{}
+var _ resource.ResourceWithModifyPlan = &ZoneMetadataResource{}
+
+// ZoneMetadataResource defines the resource implementation.
+type ZoneMetadataResource struct {
+	client *Client
+}
+
+// zoneMetadataValidationHints maps keywords of PowerDNS validation errors to
+// the metadata attribute they are about.
+var zoneMetadataValidationHints = map[string]path.Path{
+	"kind":  path.Root("kind"),
+	"value": path.Root("values"),
+}
+
+// metadataKind describes a metadata kind known to PowerDNS.
+type metadataKind struct {
+	capability *capability // Servers providing the kind, nil for all supported versions
+	managedBy  string      // What manages a kind the API refuses to change, empty when writable
+}
+
+// zoneMetadataKinds are the metadata kinds known to PowerDNS. Custom kinds
+// prefixed with "X-" are always allowed.
+var zoneMetadataKinds = map[string]metadataKind{
+	"ALLOW-AXFR-FROM":          {},
+	"ALLOW-DNSUPDATE-FROM":     {},
+	"ALSO-NOTIFY":              {},
+	"AXFR-SOURCE":              {},
+	"ENABLE-LUA-RECORDS":       {capability: &capabilityLuaRecords},
+	"FORWARD-DNSUPDATE":        {},
+	"GSS-ACCEPTOR-PRINCIPAL":   {},
+	"GSS-ALLOW-AXFR-PRINCIPAL": {},
+	"IXFR":                     {},
+	"NOTIFY-DNSUPDATE":         {},
+	"PUBLISH-CDNSKEY":          {},
+	"PUBLISH-CDS":              {},
+	"SIGNALING-ZONE":           {capability: &capabilitySignalingZone},
+	"SLAVE-RENOTIFY":           {capability: &capabilitySlaveRenotify},
+	"SOA-EDIT":                 {},
+	"SOA-EDIT-DNSUPDATE":       {},
+	"TSIG-ALLOW-DNSUPDATE":     {},
+
+	"API-RECTIFY":      {managedBy: "the api_rectify attribute of powerdns_zone"},
+	"AXFR-MASTER-TSIG": {managedBy: "the slave_tsig_key_ids attribute of powerdns_zone"},
+	"LUA-AXFR-SCRIPT":  {managedBy: "PowerDNS"},
+	"NSEC3NARROW":      {managedBy: "the nsec3narrow attribute of powerdns_zone"},
+	"NSEC3PARAM":       {managedBy: "the nsec3param attribute of powerdns_zone"},
+	"PRESIGNED":        {managedBy: "the presigned attribute of powerdns_zone"},
+	"SOA-EDIT-API":     {managedBy: "the soa_edit_api attribute of powerdns_zone"},
+	"TSIG-ALLOW-AXFR":  {managedBy: "the master_tsig_key_ids attribute of powerdns_zone"},
+}
+
+// ZoneMetadataResourceModel describes the resource data model.
+type ZoneMetadataResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Zone     types.String `tfsdk:"zone"`
+	Kind     types.String `tfsdk:"kind"`
+	Values   types.List   `tfsdk:"values"`
+	Timeouts types.Object `tfsdk:"timeouts"`
+}
+
+func (r *ZoneMetadataResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_metadata"
+}
+
+func (r *ZoneMetadataResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the values of one metadata kind of a zone.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Metadata identifier, made of the zone name and the kind separated by `/`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "The name of the zone the metadata belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "The metadata kind, such as `ALSO-NOTIFY` or `SOA-EDIT`. Custom kinds must start with `X-`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					MetadataKindValidator{},
+				},
+			},
+			"values": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The values of the metadata kind. At least one value is required, remove the resource to clear a metadata kind.",
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}
+}
+
+func (r *ZoneMetadataResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type", "Expected *Client")
+		return
+	}
+	r.client = client
+
+	requireServer(&resp.Diagnostics, client, "powerdns_zone_metadata")
+}
+
+func (r *ZoneMetadataResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var kind types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("kind"), &kind)...)
+	if resp.Diagnostics.HasError() || kind.IsNull() || kind.IsUnknown() {
+		return
+	}
+
+	if known, ok := zoneMetadataKinds[kind.ValueString()]; ok && known.capability != nil {
+		r.client.requireCapability(&resp.Diagnostics, *known.capability, path.Root("kind"))
+	}
+}
+
+func (r *ZoneMetadataResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ZoneMetadataResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	resp.Diagnostics.Append(r.setMetadata(ctx, &data, "create")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneMetadataResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ZoneMetadataResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	zoneName, kind := data.Zone.ValueString(), data.Kind.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	ctx = tflog.SetField(ctx, "kind", kind)
+	tflog.Debug(ctx, "Reading PowerDNS zone metadata")
+
+	metadata, err := r.client.GetZoneMetadata(ctx, zoneName, kind)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Warn(ctx, "Zone not found; removing metadata from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read zone metadata", fmt.Errorf("couldn't fetch PowerDNS zone metadata: %w", err).Error())
+		return
+	}
+
+	// PowerDNS returns kinds without values rather than not found
+	if len(metadata.Metadata) == 0 {
+		tflog.Warn(ctx, "Zone metadata not found; removing from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setZoneMetadata(ctx, zoneName, metadata)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneMetadataResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ZoneMetadataResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	resp.Diagnostics.Append(r.setMetadata(ctx, &data, "update")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneMetadataResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ZoneMetadataResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, timeoutDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	ctx = tflog.SetField(ctx, "zone", data.Zone.ValueString())
+	ctx = tflog.SetField(ctx, "kind", data.Kind.ValueString())
+	tflog.Debug(ctx, "Deleting PowerDNS zone metadata")
+
+	if err := r.client.DeleteZoneMetadata(ctx, data.Zone.ValueString(), data.Kind.ValueString()); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tflog.Info(ctx, "PowerDNS zone already deleted")
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete zone metadata", fmt.Errorf("error deleting PowerDNS zone metadata: %w", err).Error())
+		return
+	}
+
+	tflog.Info(ctx, "Deleted PowerDNS zone metadata")
+}
+
+func (r *ZoneMetadataResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tflog.Info(ctx, "Importing PowerDNS zone metadata", map[string]any{"id": req.ID})
+
+	zoneName, kind, err := parseZoneMetadataID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("zone"), zoneName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("kind"), kind)...)
+}
+
+// setMetadata replaces the values of the planned metadata kind, on create and
+// update alike, and updates data from the result.
+func (r *ZoneMetadataResource) setMetadata(ctx context.Context, data *ZoneMetadataResourceModel, operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var values []string
+	diags.Append(data.Values.ElementsAs(ctx, &values, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	zoneName, kind := data.Zone.ValueString(), data.Kind.ValueString()
+	ctx = tflog.SetField(ctx, "zone", zoneName)
+	ctx = tflog.SetField(ctx, "kind", kind)
+	tflog.Debug(ctx, "Setting PowerDNS zone metadata", map[string]any{"operation": operation, "values": values})
+
+	metadata, err := r.client.SetZoneMetadata(ctx, zoneName, ZoneMetadata{Kind: kind, Metadata: values})
+	if err != nil {
+		addAPIErrorDiagnostic(&diags, fmt.Sprintf("Failed to %s zone metadata", operation), fmt.Errorf("error setting PowerDNS zone metadata: %w", err), path.Root("zone"), zoneMetadataValidationHints)
+		return diags
+	}
+
+	diags.Append(data.setZoneMetadata(ctx, zoneName, metadata)...)
+	tflog.Info(ctx, "Set PowerDNS zone metadata", map[string]any{"id": data.ID.ValueString()})
+	return diags
+}
+
+// setZoneMetadata updates the model from metadata, as returned by PowerDNS.
+func (m *ZoneMetadataResourceModel) setZoneMetadata(ctx context.Context, zoneName string, metadata ZoneMetadata) diag.Diagnostics {
+	m.ID = types.StringValue(zoneMetadataID(zoneName, metadata.Kind))
+	m.Kind = types.StringValue(metadata.Kind)
+
+	var diags diag.Diagnostics
+	m.Values, diags = types.ListValueFrom(ctx, types.StringType, metadata.Metadata)
+	return diags
+}
+
+// zoneMetadataID returns the resource ID of a metadata kind, "<zone>/<kind>".
+func zoneMetadataID(zoneName string, kind string) string {
+	return zoneName + "/" + kind
+}
+
+// parseZoneMetadataID splits a resource ID built by zoneMetadataID. The zone
+// is split at the last '/', since classless reverse zones may contain one.
+func parseZoneMetadataID(id string) (string, string, error) {
+	sep := strings.LastIndex(id, "/")
+	if sep <= 0 || sep == len(id)-1 {
+		return "", "", fmt.Errorf("expected an ID of the form <zone>/<kind>, got %q", id)
+	}
+	return id[:sep], id[sep+1:], nil
+}
+
+// checkMetadataKind returns why kind can't be managed through the API, or nil
+// when it can on servers providing it.
+func checkMetadataKind(kind string) error {
+	if strings.HasPrefix(kind, "X-") {
+		return nil
+	}
+	known, ok := zoneMetadataKinds[kind]
+	if !ok {
+		if _, ok := zoneMetadataKinds[strings.ToUpper(kind)]; ok {
+			return fmt.Errorf("metadata kinds are upper case, use %q", strings.ToUpper(kind))
+		}
+		return fmt.Errorf("unknown metadata kind %q, custom kinds must start with \"X-\"", kind)
+	}
+	if known.managedBy != "" {
+		return fmt.Errorf("metadata kind %q can't be changed through the API, it is managed by %s", kind, known.managedBy)
+	}
+	return nil
+}
+
+func NewZoneMetadataResource() resource.Resource {
+	return &ZoneMetadataResource{}
+}
+
+// MetadataKindValidator implements a custom validator for metadata kinds that
+// can be managed through the API.
+type MetadataKindValidator struct{}
+
+func (v MetadataKindValidator) Description(ctx context.Context) string {
+	return "Validates that the value is a metadata kind PowerDNS allows to change, or a custom X- kind"
+}
+
+func (v MetadataKindValidator) MarkdownDescription(ctx context.Context) string {
+	return "Validates that the value is a metadata kind PowerDNS allows to change, or a custom `X-` kind"
+}
+
+func (v MetadataKindValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := checkMetadataKind(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid metadata kind", err.Error())
+	}
+}
@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccPDNSZoneMetadata_basic(t *testing.T) {
+	resourceName := "powerdns_zone_metadata.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPDNSZoneMetadataConfig(`["192.0.2.1"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "metadata.sysa.abc./ALSO-NOTIFY"),
+					resource.TestCheckResourceAttr(resourceName, "kind", "ALSO-NOTIFY"),
+					resource.TestCheckResourceAttr(resourceName, "values.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "values.0", "192.0.2.1"),
+				),
+			},
+			{
+				Config: testAccPDNSZoneMetadataConfig(`["192.0.2.1", "192.0.2.2:5300"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "values.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "values.1", "192.0.2.2:5300"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func testAccPDNSZoneMetadataConfig(values string) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_zone" "test" {
+	name        = "metadata.sysa.abc."
+	kind        = "Master"
+	nameservers = ["ns1.sysa.abc."]
+}
+
+resource "powerdns_zone_metadata" "test" {
+	zone   = powerdns_zone.test.name
+	kind   = "ALSO-NOTIFY"
+	values = %s
+}`, values)
+}
+
+func TestZoneMetadata_Resource(t *testing.T) {
+	resourceName := "powerdns_zone_metadata.test"
+	server := pdnstest.New(t)
+	checkValues := func(values ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			actual := server.ZoneMetadata("metadata.sysa.abc.", "ALSO-NOTIFY")
+			if !slices.Equal(actual, values) {
+				return fmt.Errorf("expected ALSO-NOTIFY %q, got %q", values, actual)
+			}
+			return nil
+		}
+	}
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config:      testFakeServerConfig(server, testAccPDNSZoneMetadataConfig(`[]`)),
+				ExpectError: regexp.MustCompile(`Attribute values list must contain at least 1 elements`),
+			},
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneMetadataConfig(`["192.0.2.1"]`)),
+				Check:  checkValues("192.0.2.1"),
+			},
+			{
+				Config: testFakeServerConfig(server, testAccPDNSZoneMetadataConfig(`["192.0.2.1", "192.0.2.2:5300"]`)),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkValues("192.0.2.1", "192.0.2.2:5300"),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestZoneMetadata_ParseZoneMetadataID(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		expectedZone string
+		expectedKind string
+		expectError  bool
+	}{
+		{name: "zone and kind", id: "example.com./ALSO-NOTIFY", expectedZone: "example.com.", expectedKind: "ALSO-NOTIFY"},
+		{name: "classless reverse zone", id: "0/26.2.0.192.in-addr.arpa./SOA-EDIT", expectedZone: "0/26.2.0.192.in-addr.arpa.", expectedKind: "SOA-EDIT"},
+		{name: "missing kind", id: "example.com./", expectError: true},
+		{name: "missing zone", id: "/ALSO-NOTIFY", expectError: true},
+		{name: "no separator", id: "example.com.", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, kind, err := parseZoneMetadataID(tt.id)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedZone, zone)
+			assert.Equal(t, tt.expectedKind, kind)
+			assert.Equal(t, tt.id, zoneMetadataID(zone, kind))
+		})
+	}
+}
+
+func TestZoneMetadata_MetadataKindValidator(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         types.String
+		expectedError string
+	}{
+		{name: "known kind", value: types.StringValue("ALLOW-AXFR-FROM")},
+		{name: "versioned kind", value: types.StringValue("SLAVE-RENOTIFY")},
+		{name: "custom kind", value: types.StringValue("X-OWNER")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "lower case", value: types.StringValue("also-notify"), expectedError: `use "ALSO-NOTIFY"`},
+		{name: "unknown kind", value: types.StringValue("OWNER"), expectedError: "custom kinds must start with"},
+		{name: "managed by the zone", value: types.StringValue("NSEC3PARAM"), expectedError: "nsec3param attribute of powerdns_zone"},
+		{name: "managed by PowerDNS", value: types.StringValue("LUA-AXFR-SCRIPT"), expectedError: "managed by PowerDNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("kind"), ConfigValue: tt.value}
+			resp := &validator.StringResponse{}
+			MetadataKindValidator{}.ValidateString(context.Background(), req, resp)
+			if tt.expectedError == "" {
+				assert.False(t, resp.Diagnostics.HasError())
+				return
+			}
+			require.True(t, resp.Diagnostics.HasError())
+			assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), tt.expectedError)
+		})
+	}
+}
+
+func TestZoneMetadata_ValuesValidator(t *testing.T) {
+	resp := &fwresource.SchemaResponse{}
+	(&ZoneMetadataResource{}).Schema(context.Background(), fwresource.SchemaRequest{}, resp)
+	attribute, ok := resp.Schema.Attributes["values"].(schema.ListAttribute)
+	require.True(t, ok)
+
+	validate := func(value types.List) diag.Diagnostics {
+		listResp := &validator.ListResponse{}
+		for _, v := range attribute.Validators {
+			v.ValidateList(context.Background(), validator.ListRequest{Path: path.Root("values"), ConfigValue: value}, listResp)
+		}
+		return listResp.Diagnostics
+	}
+
+	assert.True(t, validate(types.ListValueMust(types.StringType, nil)).HasError())
+	assert.False(t, validate(types.ListValueMust(types.StringType, []attr.Value{types.StringValue("192.0.2.1")})).HasError())
+	assert.False(t, validate(types.ListUnknown(types.StringType)).HasError())
+}
+
+func TestZoneMetadata_KindCapabilities(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverVersion string
+		kind          string
+		expectError   bool
+	}{
+		{name: "unversioned kind", serverVersion: "4.0.0", kind: "ALSO-NOTIFY"},
+		{name: "recent enough", serverVersion: "4.3.0", kind: "SLAVE-RENOTIFY"},
+		{name: "too old", serverVersion: "4.2.3", kind: "SLAVE-RENOTIFY", expectError: true},
+		{name: "signaling zone", serverVersion: "4.9.0", kind: "SIGNALING-ZONE", expectError: true},
+		{name: "unknown version", serverVersion: "0.0.0", kind: "SIGNALING-ZONE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{ServerVersion: tt.serverVersion}
+			known, ok := zoneMetadataKinds[tt.kind]
+			require.True(t, ok)
+
+			var diags diag.Diagnostics
+			if known.capability != nil {
+				client.requireCapability(&diags, *known.capability, path.Root("kind"))
+			}
+			assert.Equal(t, tt.expectError, diags.HasError())
+		})
+	}
+}
+
+func TestZoneMetadata_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeServerClient(t)
+
+	_, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Master", Nameservers: []string{"ns1.example.com."}})
+	require.NoError(t, err)
+
+	metadata, err := client.SetZoneMetadata(ctx, "example.com.", ZoneMetadata{Kind: "ALSO-NOTIFY", Metadata: []string{"192.0.2.1", "192.0.2.2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, metadata.Metadata)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, server.ZoneMetadata("example.com.", "ALSO-NOTIFY"))
+
+	// Setting replaces the previous values
+	_, err = client.SetZoneMetadata(ctx, "example.com.", ZoneMetadata{Kind: "ALSO-NOTIFY", Metadata: []string{"192.0.2.3"}})
+	require.NoError(t, err)
+	metadata, err = client.GetZoneMetadata(ctx, "example.com.", "ALSO-NOTIFY")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.3"}, metadata.Metadata)
+
+	_, err = client.SetZoneMetadata(ctx, "example.com.", ZoneMetadata{Kind: "PRESIGNED", Metadata: []string{"1"}})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+
+	require.NoError(t, client.DeleteZoneMetadata(ctx, "example.com.", "ALSO-NOTIFY"))
+	metadata, err = client.GetZoneMetadata(ctx, "example.com.", "ALSO-NOTIFY")
+	require.NoError(t, err)
+	assert.Empty(t, metadata.Metadata)
+
+	_, err = client.GetZoneMetadata(ctx, "missing.example.", "ALSO-NOTIFY")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -5,8 +5,12 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccPDNSZoneNative(t *testing.T) {
@@ -344,6 +348,240 @@ func TestAccPDNSZone_Update(t *testing.T) {
 	})
 }
 
+func TestZone_Resource(t *testing.T) {
+	server := pdnstest.New(t)
+	checkAccount := func(account string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			zone, ok := server.Zone("update.sysa.abc.")
+			if !ok {
+				return fmt.Errorf("zone update.sysa.abc. not found")
+			}
+			if zone.Account != account {
+				return fmt.Errorf("expected account %q, got %q", account, zone.Account)
+			}
+			return nil
+		}
+	}
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.Zone("update.sysa.abc."); ok {
+				return fmt.Errorf("zone update.sysa.abc. still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testPDNSZoneConfigUpdate),
+				Check:  checkAccount("initial-account"),
+			},
+			{
+				Config: testFakeServerConfig(server, testPDNSZoneConfigUpdateModified),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("powerdns_zone.test-update", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkAccount("updated-account"),
+			},
+			{
+				ResourceName:            "powerdns_zone.test-update",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestAccPDNSZone_DNSSEC(t *testing.T) {
+	resourceName := "powerdns_zone.test-dnssec"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckPDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPDNSZoneConfigDNSSEC("1 0 0 -", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckPDNSZoneExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "dnssec", "true"),
+					resource.TestCheckResourceAttr(resourceName, "nsec3param", "1 0 0 -"),
+					resource.TestCheckResourceAttr(resourceName, "nsec3narrow", "false"),
+					resource.TestCheckResourceAttr(resourceName, "presigned", "false"),
+					resource.TestCheckResourceAttr(resourceName, "api_rectify", "true"),
+				),
+			},
+			// Switching back to NSEC and disabling rectify is done in place
+			{
+				Config: testPDNSZoneConfigDNSSEC("", false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "dnssec", "true"),
+					resource.TestCheckResourceAttr(resourceName, "nsec3param", ""),
+					resource.TestCheckResourceAttr(resourceName, "api_rectify", "false"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestZone_ResourceDNSSEC(t *testing.T) {
+	resourceName := "powerdns_zone.test-dnssec"
+	server := pdnstest.New(t)
+	checkZone := func(nsec3Param string, apiRectify bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			zone, ok := server.Zone("dnssec.sysa.abc.")
+			if !ok {
+				return fmt.Errorf("zone dnssec.sysa.abc. not found")
+			}
+			if !zone.DNSSEC || zone.NSEC3Param != nsec3Param || zone.APIRectify != apiRectify {
+				return fmt.Errorf("unexpected DNSSEC settings: dnssec=%t nsec3param=%q api_rectify=%t", zone.DNSSEC, zone.NSEC3Param, zone.APIRectify)
+			}
+			return nil
+		}
+	}
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testPDNSZoneConfigDNSSEC("1 0 0 -", true)),
+				Check:  checkZone("1 0 0 -", true),
+			},
+			{
+				Config: testFakeServerConfig(server, testPDNSZoneConfigDNSSEC("", false)),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkZone("", false),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestAccPDNSZone_TSIGKeys(t *testing.T) {
+	resourceName := "powerdns_zone.test-tsig"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckPDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPDNSZoneConfigTSIGKeys("[powerdns_tsigkey.axfr.id]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckPDNSZoneExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "slave_tsig_key_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "slave_tsig_key_ids.*", "tsig-zone."),
+					resource.TestCheckResourceAttr(resourceName, "master_tsig_key_ids.#", "0"),
+				),
+			},
+			// Removing the keys is done in place
+			{
+				Config: testPDNSZoneConfigTSIGKeys("[]"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "slave_tsig_key_ids.#", "0"),
+				),
+			},
+			{
+				Config:      testPDNSZoneConfigTSIGKeys(`["missing."]`),
+				ExpectError: regexp.MustCompile("Unknown TSIG key"),
+			},
+		},
+	})
+}
+
+func TestZone_SetZoneDNSSECUpdate(t *testing.T) {
+	state := ZoneResourceModel{
+		DNSSec:      types.BoolValue(true),
+		Nsec3Param:  types.StringValue("1 0 0 -"),
+		Nsec3Narrow: types.BoolValue(false),
+		Presigned:   types.BoolValue(false),
+		APIRectify:  types.BoolValue(true),
+	}
+	boolPtr := func(b bool) *bool { return &b }
+	stringPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		update   func(plan *ZoneResourceModel)
+		expected ZoneInfoUpd
+	}{
+		{
+			name:     "unchanged",
+			update:   func(plan *ZoneResourceModel) {},
+			expected: ZoneInfoUpd{},
+		},
+		{
+			name:     "disable dnssec",
+			update:   func(plan *ZoneResourceModel) { plan.DNSSec = types.BoolValue(false) },
+			expected: ZoneInfoUpd{DNSSec: boolPtr(false)},
+		},
+		{
+			name:     "narrow is sent with the nsec3 parameters",
+			update:   func(plan *ZoneResourceModel) { plan.Nsec3Narrow = types.BoolValue(true) },
+			expected: ZoneInfoUpd{Nsec3Param: stringPtr("1 0 0 -"), Nsec3Narrow: boolPtr(true)},
+		},
+		{
+			name:     "switch to nsec",
+			update:   func(plan *ZoneResourceModel) { plan.Nsec3Param = types.StringValue("") },
+			expected: ZoneInfoUpd{Nsec3Param: stringPtr(""), Nsec3Narrow: boolPtr(false)},
+		},
+		{
+			name: "presigned and rectify",
+			update: func(plan *ZoneResourceModel) {
+				plan.Presigned = types.BoolValue(true)
+				plan.APIRectify = types.BoolValue(false)
+			},
+			expected: ZoneInfoUpd{Presigned: boolPtr(true), APIRectify: boolPtr(false)},
+		},
+		{
+			name: "unknown values are left out",
+			update: func(plan *ZoneResourceModel) {
+				plan.DNSSec = types.BoolUnknown()
+				plan.Nsec3Param = types.StringUnknown()
+				plan.APIRectify = types.BoolUnknown()
+			},
+			expected: ZoneInfoUpd{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := state
+			tt.update(&plan)
+
+			var zoneInfo ZoneInfoUpd
+			setZoneDNSSECUpdate(&zoneInfo, plan, state)
+			assert.Equal(t, tt.expected, zoneInfo)
+		})
+	}
+}
+
 func testAccCheckPDNSZoneDestroy(s *terraform.State) error {
 	// Since we're in acceptance testing mode, we don't have direct access to the client
 	// In a real implementation, this would use the provider client to verify
@@ -568,3 +806,41 @@ resource "powerdns_zone" "test-update" {
 	nameservers = ["ns1.sysa.abc.", "ns2.sysa.abc."]
 	account = "updated-account"
 }`
+
+func testPDNSZoneConfigDNSSEC(nsec3Param string, apiRectify bool) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+	server_url         = "http://localhost:8081"
+	recursor_server_url = "http://localhost:8082"
+	api_key            = "secret"
+}
+
+resource "powerdns_zone" "test-dnssec" {
+	name = "dnssec.sysa.abc."
+	kind = "Native"
+	nameservers = ["ns1.sysa.abc.", "ns2.sysa.abc."]
+	dnssec = true
+	nsec3param = %q
+	api_rectify = %t
+}`, nsec3Param, apiRectify)
+}
+
+func testPDNSZoneConfigTSIGKeys(slaveTSIGKeyIDs string) string {
+	return fmt.Sprintf(`
+provider "powerdns" {
+	server_url = "http://localhost:8081"
+	api_key    = "secret"
+}
+
+resource "powerdns_tsigkey" "axfr" {
+	name      = "tsig-zone"
+	algorithm = "hmac-sha256"
+}
+
+resource "powerdns_zone" "test-tsig" {
+	name               = "tsig.sysa.abc."
+	kind               = "Slave"
+	masters            = ["10.10.10.10"]
+	slave_tsig_key_ids = %s
+}`, slaveTSIGKeyIDs)
+}
@@ -0,0 +1,320 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Default retry policy used when the provider configuration does not set one.
+const (
+	defaultMaxRetries   = 3
+	defaultRetryMinWait = 1 * time.Second
+	defaultRetryMaxWait = 30 * time.Second
+)
+
+// defaultRequestTimeout bounds a single HTTP request when the provider
+// configuration does not set request_timeout.
+const defaultRequestTimeout = 60 * time.Second
+
+// maxBufferedBody bounds the part of a response body kept in memory while
+// checking whether a request was applied. Error bodies are much smaller.
+const maxBufferedBody = 1 << 20
+
+// appliedCheck reports whether a non-idempotent request that may have
+// reached the server was already applied, so it is not sent a second time.
+type appliedCheck func(ctx context.Context) (bool, error)
+
+// requestBuilder builds a fresh request for every attempt, since a request
+// body cannot be replayed once it has been consumed.
+type requestBuilder func() (*http.Request, error)
+
+// ClientOption configures optional Client behaviour.
+type ClientOption func(*Client)
+
+// WithRetryPolicy sets how many times, and with which backoff bounds,
+// transient API failures are retried.
+func WithRetryPolicy(maxRetries int, minWait, maxWait time.Duration) ClientOption {
+	return func(client *Client) {
+		client.MaxRetries = maxRetries
+		client.RetryMinWait = minWait
+		client.RetryMaxWait = maxWait
+	}
+}
+
+// WithRequestTimeout bounds every single HTTP request, so that a hung server
+// can't block an operation until the OS gives up on the connection.
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.RequestTimeout = timeout
+	}
+}
+
+// do sends the request produced by build, retrying transient failures with
+// exponential backoff. Idempotent methods are retried freely. POST and PATCH
+// are only re-sent when the server cannot have processed them, or when check
+// confirms that the change has not been applied yet.
+//
+// The returned response is the last one received; callers own its body.
+func (client *Client) do(ctx context.Context, build requestBuilder, check appliedCheck) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := build()
+		if err != nil {
+			return nil, err
+		}
+
+		release, err := client.acquire(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancelAttempt := req.Context(), context.CancelFunc(func() {})
+		if client.RequestTimeout > 0 {
+			attemptCtx, cancelAttempt = context.WithTimeout(attemptCtx, client.RequestTimeout)
+		}
+		// cancel ends the attempt and frees its slot for other requests
+		cancel := func() {
+			cancelAttempt()
+			release()
+		}
+
+		var wrote atomic.Bool
+		trace := &httptrace.ClientTrace{
+			WroteRequest: func(httptrace.WroteRequestInfo) { wrote.Store(true) },
+		}
+		req = req.WithContext(httptrace.WithClientTrace(attemptCtx, trace))
+
+		resp, err := client.HTTP.Do(req)
+
+		// finish hands the outcome back to the caller. The attempt context
+		// must outlive the response body, which the caller reads and closes.
+		finish := func() (*http.Response, error) {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		if !shouldRetry(ctx, resp, err) || attempt >= client.MaxRetries {
+			return finish()
+		}
+
+		if !isIdempotent(req.Method) && mayHaveBeenProcessed(resp, err, wrote.Load()) {
+			if check == nil {
+				return finish()
+			}
+			// The check sends requests of its own, which must not wait for
+			// the slot of this attempt: keep what the caller needs of the
+			// response and end the attempt first
+			bufferResponse(resp)
+			cancel()
+			applied, checkErr := check(ctx)
+			if checkErr != nil {
+				tflog.Warn(ctx, "Unable to verify whether the request was applied, not retrying", map[string]interface{}{
+					"method": req.Method,
+					"url":    req.URL.String(),
+					"error":  checkErr.Error(),
+				})
+				return finish()
+			}
+			if applied {
+				tflog.Debug(ctx, "Request was already applied by the server", map[string]interface{}{
+					"method": req.Method,
+					"url":    req.URL.String(),
+				})
+				return &http.Response{
+					StatusCode: http.StatusNoContent,
+					Header:     make(http.Header),
+					Body:       http.NoBody,
+					Request:    req,
+				}, nil
+			}
+		}
+
+		wait := client.backoff(attempt, resp)
+
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+		}
+		tflog.Warn(ctx, "Retrying PowerDNS API request after transient failure", fields)
+
+		discardResponse(resp)
+		cancel()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry reports whether the outcome of a request is a transient failure.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		// With the caller's context still alive, a deadline can only come
+		// from the per-request timeout, which is worth another attempt
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+
+		// Certificate problems and malformed URLs won't go away by retrying
+		var unknownAuthority x509.UnknownAuthorityError
+		var certInvalid x509.CertificateInvalidError
+		var hostname x509.HostnameError
+		var verification *tls.CertificateVerificationError
+		if errors.As(err, &unknownAuthority) || errors.As(err, &certInvalid) ||
+			errors.As(err, &hostname) || errors.As(err, &verification) {
+			return false
+		}
+		if strings.Contains(err.Error(), "unsupported protocol scheme") {
+			return false
+		}
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// mayHaveBeenProcessed reports whether a failed request could have been
+// applied by PowerDNS. 429 and 503 are rejections that happen before the
+// request is processed, and a request that was never written can't have
+// been processed either.
+func mayHaveBeenProcessed(resp *http.Response, err error, wrote bool) bool {
+	if err != nil {
+		return wrote
+	}
+	return resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable
+}
+
+// isIdempotent reports whether sending the method twice has the same effect
+// as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case methodGet, methodPut, methodDelete, methodOptions, http.MethodHead:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After
+// header sent with a 429 or 503 takes precedence over the computed backoff,
+// but is still capped by the maximum wait.
+func (client *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	minWait, maxWait := client.RetryMinWait, client.RetryMaxWait
+	if minWait <= 0 {
+		minWait = defaultRetryMinWait
+	}
+	if maxWait < minWait {
+		maxWait = minWait
+	}
+
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(wait, maxWait)
+		}
+	}
+
+	wait := maxWait
+	if attempt < 62 {
+		if exp := minWait << uint(attempt); exp > 0 && exp < maxWait {
+			wait = exp
+		}
+	}
+
+	// Equal jitter: keep at least half of the backoff, randomize the rest
+	half := wait / 2
+	return half + time.Duration(rand.Int64N(int64(wait-half)+1))
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// cancelOnClose releases the context of a request once its response body has
+// been closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// bufferResponse reads the body of resp into memory, so that it stays readable
+// once the request that produced it has ended.
+func bufferResponse(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBufferedBody))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+}
+
+// discardResponse drains and closes a response that won't be handed back to
+// the caller, so the underlying connection can be reused.
+func discardResponse(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestClient(serverURL string, maxRetries int) *Client {
+	return &Client{
+		ServerURL:    serverURL,
+		APIKey:       "secret",
+		APIVersion:   1,
+		HTTP:         &http.Client{},
+		MaxRetries:   maxRetries,
+		RetryMinWait: time.Millisecond,
+		RetryMaxWait: 5 * time.Millisecond,
+	}
+}
+
+func TestRetry_RetriesTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	zones, err := client.ListZones(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, zones)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 2)
+	_, err := client.ListZones(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetry_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error": "invalid"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	_, err := client.ListZones(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetry_PatchVerifiedBeforeResend(t *testing.T) {
+	var patches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			patches.Add(1)
+			// The change lands but the load balancer times out
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"name": "example.com.", "rrsets": [{"name": "www.example.com.", "type": "A", "ttl": 300, "records": [{"content": "192.0.2.1", "disabled": false}]}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	id, err := client.ReplaceRecordSet(context.Background(), "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "www.example.com.:::A", id)
+	assert.Equal(t, int32(1), patches.Load())
+}
+
+func TestRetry_PatchResentWhenNotApplied(t *testing.T) {
+	var patches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			if patches.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"name": "example.com.", "rrsets": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	_, err := client.ReplaceRecordSet(context.Background(), "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), patches.Load())
+}
+
+func TestRetry_DeleteVerifiedBeforeResend(t *testing.T) {
+	var patches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			patches.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"name": "example.com.", "rrsets": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	err := client.DeleteRecordSet(context.Background(), "example.com.", "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), patches.Load())
+}
+
+func TestRetry_PatchRetriedOnTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("unexpected %s request, 429 must be retried without verification", r.Method)
+		}
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	err := client.DeleteRecordSet(context.Background(), "example.com.", "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetry_ContextCancelledDuringBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 5)
+	client.RetryMinWait = time.Minute
+	client.RetryMaxWait = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ListZones(ctx)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetry_ParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "seconds", value: "5", expected: 5 * time.Second, ok: true},
+		{name: "http date", value: "Mon, 01 Jan 2024 12:00:10 GMT", expected: 10 * time.Second, ok: true},
+		{name: "date in the past", value: "Mon, 01 Jan 2024 11:00:00 GMT", expected: 0, ok: true},
+		{name: "empty", value: "", ok: false},
+		{name: "negative", value: "-1", ok: false},
+		{name: "garbage", value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, wait)
+			}
+		})
+	}
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	client := &Client{RetryMinWait: 100 * time.Millisecond, RetryMaxWait: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := client.backoff(attempt, nil)
+		assert.GreaterOrEqual(t, wait, 50*time.Millisecond)
+		assert.LessOrEqual(t, wait, time.Second)
+	}
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, 7*time.Second, (&Client{RetryMinWait: time.Second, RetryMaxWait: 30 * time.Second}).backoff(0, resp))
+
+	// Retry-After is capped by the maximum wait
+	resp = &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"3600"}}}
+	assert.Equal(t, time.Second, client.backoff(0, resp))
+}
+
+func TestRetry_ShouldRetry(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		status   int
+		err      error
+		expected bool
+	}{
+		{name: "ok", status: http.StatusOK, expected: false},
+		{name: "not found", status: http.StatusNotFound, expected: false},
+		{name: "too many requests", status: http.StatusTooManyRequests, expected: true},
+		{name: "bad gateway", status: http.StatusBadGateway, expected: true},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, expected: true},
+		{name: "gateway timeout", status: http.StatusGatewayTimeout, expected: true},
+		{name: "internal server error", status: http.StatusInternalServerError, expected: false},
+		{name: "connection reset", err: errors.New("read: connection reset by peer"), expected: true},
+		{name: "context canceled", err: context.Canceled, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			assert.Equal(t, tt.expected, shouldRetry(ctx, resp, tt.err))
+		})
+	}
+}
+
+func TestRetry_RequestTimeoutRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Hang until the client gives up on this attempt
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 1)
+	client.RequestTimeout = 50 * time.Millisecond
+
+	_, err := client.ListZones(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetry_PatchVerifiedWithConcurrencyLimit(t *testing.T) {
+	// With a single slot, verifying the PATCH can only proceed once the
+	// timed out attempt has given its slot back
+	client, server := newFakeServerClient(t, WithConcurrencyLimit(1, 0), WithRequestTimeout(100*time.Millisecond))
+	_, err := server.CreateZone(pdnstest.Zone{Name: "example.com.", Kind: "Native"})
+	require.NoError(t, err)
+	server.Inject(pdnstest.Fault{Method: http.MethodPatch, Delay: time.Minute, Times: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.1"}},
+	})
+	require.NoError(t, err)
+
+	rrset, ok := server.RRSet("example.com.", "www.example.com.", "A")
+	require.True(t, ok)
+	assert.Len(t, rrset.Records, 1)
+}
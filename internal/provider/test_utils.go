@@ -1,10 +1,15 @@
 package provider
 
 import (
+	"os"
+	"os/exec"
+	"strings"
 	"testing"
 
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 func testAccPreCheck(t *testing.T) {
@@ -17,3 +22,31 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 		return providerserver.NewProtocol6(New("test")())(), nil
 	},
 }
+
+// testFakeServerUnitTest runs c with resource.UnitTest, against the fake
+// PowerDNS API its configurations point to. It is skipped when no Terraform
+// CLI is at hand, rather than downloading one.
+func testFakeServerUnitTest(t *testing.T, c resource.TestCase) {
+	t.Helper()
+
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") == "" && os.Getenv("TF_ACC_TERRAFORM_VERSION") == "" {
+		if _, err := exec.LookPath("terraform"); err != nil {
+			t.Skip("Terraform CLI not found in PATH")
+		}
+	}
+
+	if c.ProtoV6ProviderFactories == nil {
+		c.ProtoV6ProviderFactories = testAccProtoV6ProviderFactories
+	}
+	resource.UnitTest(t, c)
+}
+
+// testFakeServerConfig points config, written for the docker-compose stack,
+// to server instead.
+func testFakeServerConfig(server *pdnstest.Server, config string) string {
+	return strings.NewReplacer(
+		"http://localhost:8081", server.URL,
+		"http://localhost:8082", server.RecursorURL,
+		`"secret"`, `"`+server.APIKey()+`"`,
+	).Replace(config)
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultOperationTimeout applies to resource operations without a
+// configured timeout.
+const defaultOperationTimeout = 10 * time.Minute
+
+// Resource operations that can be given a timeout.
+const (
+	timeoutCreate = "create"
+	timeoutRead   = "read"
+	timeoutUpdate = "update"
+	timeoutDelete = "delete"
+)
+
+// timeoutsAttrTypes describes the object held by a resource's timeouts block.
+var timeoutsAttrTypes = map[string]attr.Type{
+	timeoutCreate: types.StringType,
+	timeoutRead:   types.StringType,
+	timeoutUpdate: types.StringType,
+	timeoutDelete: types.StringType,
+}
+
+// timeoutsNull returns an unset timeouts block, for states built from scratch
+// such as on import.
+func timeoutsNull() types.Object {
+	return types.ObjectNull(timeoutsAttrTypes)
+}
+
+// timeoutsBlock returns the `timeouts { create read update delete }` block
+// shared by resources.
+func timeoutsBlock() schema.SingleNestedBlock {
+	attributes := make(map[string]schema.Attribute, len(timeoutsAttrTypes))
+	for operation := range timeoutsAttrTypes {
+		attributes[operation] = schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Timeout for the %s operation as a duration, e.g. `30s` or `5m`. Defaults to `%s`.", operation, defaultOperationTimeout),
+			Optional:            true,
+			Validators: []validator.String{
+				DurationValidator{},
+			},
+		}
+	}
+
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Per-operation timeouts.",
+		Attributes:          attributes,
+	}
+}
+
+// withOperationTimeout derives a context bounded by the timeout configured for
+// operation in the timeouts block. The returned cancel func must be called.
+func withOperationTimeout(ctx context.Context, timeouts types.Object, operation string) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	timeout := defaultOperationTimeout
+	if !timeouts.IsNull() && !timeouts.IsUnknown() {
+		if value, ok := timeouts.Attributes()[operation].(types.String); ok && !value.IsNull() && !value.IsUnknown() {
+			parsed, err := time.ParseDuration(value.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("timeouts").AtName(operation),
+					"Invalid timeout",
+					fmt.Sprintf("Unable to parse %s timeout %q: %s", operation, value.ValueString(), err),
+				)
+				return ctx, func() {}, diags
+			}
+			timeout = parsed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, diags
+}
+
+// DurationValidator implements a custom validator for Go duration strings.
+type DurationValidator struct{}
+
+func (v DurationValidator) Description(ctx context.Context) string {
+	return "Validates that the value is a positive duration such as 30s or 5m"
+}
+
+func (v DurationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Validates that the value is a positive duration such as `30s` or `5m`"
+}
+
+func (v DurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || duration <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf("Expected a positive duration such as 30s or 5m, got %q", req.ConfigValue.ValueString()),
+		)
+	}
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeouts_WithOperationTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	timeouts := func(create string) types.Object {
+		createValue := types.StringNull()
+		if create != "" {
+			createValue = types.StringValue(create)
+		}
+		return types.ObjectValueMust(timeoutsAttrTypes, map[string]attr.Value{
+			timeoutCreate: createValue,
+			timeoutRead:   types.StringNull(),
+			timeoutUpdate: types.StringNull(),
+			timeoutDelete: types.StringNull(),
+		})
+	}
+
+	tests := []struct {
+		name        string
+		timeouts    types.Object
+		operation   string
+		expected    time.Duration
+		expectError bool
+	}{
+		{
+			name:      "no timeouts block",
+			timeouts:  timeoutsNull(),
+			operation: timeoutCreate,
+			expected:  defaultOperationTimeout,
+		},
+		{
+			name:      "configured timeout",
+			timeouts:  timeouts("90s"),
+			operation: timeoutCreate,
+			expected:  90 * time.Second,
+		},
+		{
+			name:      "other operation unset",
+			timeouts:  timeouts("90s"),
+			operation: timeoutDelete,
+			expected:  defaultOperationTimeout,
+		},
+		{
+			name:        "invalid timeout",
+			timeouts:    timeouts("soon"),
+			operation:   timeoutCreate,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opCtx, cancel, diags := withOperationTimeout(ctx, tt.timeouts, tt.operation)
+			defer cancel()
+
+			if tt.expectError {
+				require.True(t, diags.HasError())
+				return
+			}
+			require.False(t, diags.HasError())
+
+			deadline, ok := opCtx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(tt.expected), deadline, time.Second)
+		})
+	}
+}
+
+func TestTimeouts_DurationValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{name: "minutes", value: types.StringValue("5m")},
+		{name: "compound", value: types.StringValue("1h30m")},
+		{name: "null", value: types.StringNull()},
+		{name: "no unit", value: types.StringValue("30"), expectError: true},
+		{name: "zero", value: types.StringValue("0s"), expectError: true},
+		{name: "negative", value: types.StringValue("-5m"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("timeouts").AtName("create"), ConfigValue: tt.value}
+			resp := &validator.StringResponse{}
+			DurationValidator{}.ValidateString(context.Background(), req, resp)
+			assert.Equal(t, tt.expectError, resp.Diagnostics.HasError())
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	freecache "github.com/coocood/freecache"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// zoneCacheKey normalizes a zone name, so that "Example.com" and
+// "example.com." share one cache entry and one in-flight fetch.
+func zoneCacheKey(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, ".")) + "."
+}
+
+// zoneFetch is a zone retrieval in flight, shared by every caller asking for
+// the same zone meanwhile.
+type zoneFetch struct {
+	done      chan struct{}
+	zoneInfo  *ZoneInfo
+	err       error
+	forgotten bool // set when the zone changed while fetching; the result must not be cached
+}
+
+// zoneFetchGroup collapses concurrent retrievals of the same zone into a
+// single HTTP request. The zero value is ready to use.
+type zoneFetchGroup struct {
+	mu      sync.Mutex
+	fetches map[string]*zoneFetch
+}
+
+// do returns the result of fetch for key, joining a retrieval already in
+// flight when there is one. store is called with a successful result, under
+// the group lock, unless the zone was invalidated in the meantime.
+func (g *zoneFetchGroup) do(ctx context.Context, key string, fetch func(context.Context) (*ZoneInfo, error), store func(*ZoneInfo)) (*ZoneInfo, error) {
+	for {
+		g.mu.Lock()
+		if f, ok := g.fetches[key]; ok {
+			g.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// The fetch was cancelled by its own caller, not by ours: try again
+			if f.err != nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) && ctx.Err() == nil {
+				continue
+			}
+			return f.zoneInfo, f.err
+		}
+
+		f := &zoneFetch{done: make(chan struct{})}
+		if g.fetches == nil {
+			g.fetches = make(map[string]*zoneFetch)
+		}
+		g.fetches[key] = f
+		g.mu.Unlock()
+
+		f.zoneInfo, f.err = fetch(ctx)
+
+		g.mu.Lock()
+		if !f.forgotten {
+			delete(g.fetches, key)
+			if f.err == nil && store != nil {
+				store(f.zoneInfo)
+			}
+		}
+		g.mu.Unlock()
+		close(f.done)
+
+		return f.zoneInfo, f.err
+	}
+}
+
+// forget detaches the retrieval in flight for key, if any, and runs drop under
+// the group lock. Later callers start a new retrieval instead of joining it.
+func (g *zoneFetchGroup) forget(key string, drop func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.fetches[key]; ok {
+		f.forgotten = true
+		delete(g.fetches, key)
+	}
+	drop()
+}
+
+// GetZoneInfoFromCache return ZoneInfo struct, or nil when the cache is
+// disabled or doesn't hold the zone.
+func (client *Client) GetZoneInfoFromCache(ctx context.Context, zone string) (*ZoneInfo, error) {
+	if client.CacheEnable {
+		cacheZoneInfo, err := client.Cache.Get([]byte(zoneCacheKey(zone)))
+		if errors.Is(err, freecache.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		zoneInfo := new(ZoneInfo)
+		if err := json.Unmarshal(cacheZoneInfo, &zoneInfo); err != nil {
+			return nil, err
+		}
+
+		return zoneInfo, nil
+	}
+
+	return nil, nil
+}
+
+// loadZoneInfo returns a zone with its rrsets, from the cache when enabled.
+// Concurrent calls for the same zone share a single request.
+func (client *Client) loadZoneInfo(ctx context.Context, zone string) (*ZoneInfo, error) {
+	zoneInfo, err := client.GetZoneInfoFromCache(ctx, zone)
+	if err != nil {
+		tflog.Warn(ctx, "Cache get failed", map[string]interface{}{
+			"zone":  zone,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	if zoneInfo != nil {
+		return zoneInfo, nil
+	}
+
+	var storeErr error
+	zoneInfo, err = client.zoneFetches.do(ctx, zoneCacheKey(zone), func(ctx context.Context) (*ZoneInfo, error) {
+		return client.fetchZoneInfo(ctx, zone)
+	}, func(zoneInfo *ZoneInfo) {
+		if !client.CacheEnable {
+			return
+		}
+		cacheValue, err := json.Marshal(zoneInfo)
+		if err != nil {
+			storeErr = err
+			return
+		}
+		if err := client.Cache.Set([]byte(zoneCacheKey(zone)), cacheValue, client.CacheTTL); err != nil {
+			storeErr = fmt.Errorf("the cache for REST API requests is enabled but the size isn't enough: cacheSize: %db \n %s",
+				DefaultCacheSize, err)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if storeErr != nil {
+		return nil, storeErr
+	}
+	return zoneInfo, nil
+}
+
+// invalidateZone drops the cached copy of a zone after a change to it. A
+// retrieval of the zone in flight is detached, so its possibly stale result
+// is neither cached nor handed to later callers.
+func (client *Client) invalidateZone(ctx context.Context, zone string) {
+	key := zoneCacheKey(zone)
+	client.zoneFetches.forget(key, func() {
+		if client.CacheEnable && client.Cache != nil {
+			client.Cache.Del([]byte(key))
+		}
+	})
+	tflog.Trace(ctx, "Invalidated cached zone", map[string]interface{}{
+		"zone": zone,
+	})
+}
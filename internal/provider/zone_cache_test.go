@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	freecache "github.com/coocood/freecache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const zoneCacheTestZone = `{"name": "example.com.", "rrsets": [{"name": "www.example.com.", "type": "A", "ttl": 300, "records": [{"content": "192.0.2.1", "disabled": false}]}]}`
+
+func TestZoneCache_ConcurrentListRecordsCoalesced(t *testing.T) {
+	var gets atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets.Add(1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(zoneCacheTestZone))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 0)
+
+	var wg sync.WaitGroup
+	results := make([][]Record, 10)
+	errs := make([]error, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.ListRecords(context.Background(), "example.com.")
+		}()
+	}
+
+	// Give every reader the time to join the request in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), gets.Load())
+	for i := range results {
+		require.NoError(t, errs[i])
+		require.Len(t, results[i], 1)
+		assert.Equal(t, "192.0.2.1", results[i][0].Content)
+	}
+}
+
+func TestZoneCache_InvalidatedByWrites(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets.Add(1)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(zoneCacheTestZone))
+		case http.MethodPatch:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 0)
+	client.CacheEnable = true
+	client.Cache = freecache.NewCache(1024 * 1024)
+	client.CacheTTL = 60
+
+	ctx := context.Background()
+
+	_, err := client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	_, err = client.ListRecords(ctx, "Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), gets.Load(), "second read must be served from the cache")
+
+	_, err = client.ReplaceRecordSet(ctx, "example.com.", ResourceRecordSet{
+		Name:    "www.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []Record{{Content: "192.0.2.2"}},
+	})
+	require.NoError(t, err)
+
+	_, err = client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), gets.Load(), "read after a write must not be served from the cache")
+
+	require.NoError(t, client.DeleteRecordSet(ctx, "example.com.", "www.example.com.", "A"))
+
+	_, err = client.ListRecords(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), gets.Load())
+}
+
+func TestZoneCache_ForgottenFetchNotStored(t *testing.T) {
+	var group zoneFetchGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var stored atomic.Bool
+	done := make(chan error)
+	go func() {
+		_, err := group.do(context.Background(), "example.com.", func(context.Context) (*ZoneInfo, error) {
+			close(started)
+			<-release
+			return &ZoneInfo{Name: "example.com."}, nil
+		}, func(*ZoneInfo) {
+			stored.Store(true)
+		})
+		done <- err
+	}()
+
+	<-started
+	dropped := false
+	group.forget("example.com.", func() { dropped = true })
+	close(release)
+
+	require.NoError(t, <-done)
+	assert.True(t, dropped)
+	assert.False(t, stored.Load(), "a fetch overlapping a write must not be cached")
+}
+
+func TestZoneCache_WaiterContextCancelled(t *testing.T) {
+	var group zoneFetchGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		_, _ = group.do(context.Background(), "example.com.", func(context.Context) (*ZoneInfo, error) {
+			close(started)
+			<-release
+			return &ZoneInfo{}, nil
+		}, nil)
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := group.do(ctx, "example.com.", func(context.Context) (*ZoneInfo, error) {
+		t.Error("waiter must not start its own fetch")
+		return nil, nil
+	}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
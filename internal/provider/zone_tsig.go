@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// zoneTSIGKeyAttributes are the attributes binding TSIG keys to zones, by the
+// ID of the keys.
+var zoneTSIGKeyAttributes = []string{"master_tsig_key_ids", "slave_tsig_key_ids"}
+
+// zoneTSIGKeyIDsAttribute returns the schema of an attribute binding TSIG keys
+// to a zone.
+func zoneTSIGKeyIDsAttribute(description string) schema.SetAttribute {
+	return schema.SetAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: description + " Set to an empty set to remove all the keys.",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Set{
+			setplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// tsigKeyIDsValue returns the TSIG key IDs of a zone as an attribute value.
+func tsigKeyIDsValue(ctx context.Context, ids []string) (types.Set, diag.Diagnostics) {
+	if ids == nil {
+		ids = []string{}
+	}
+	return types.SetValueFrom(ctx, types.StringType, ids)
+}
+
+// tsigKeyIDsUpdate returns the TSIG key IDs to send when updating a zone, nil
+// when they didn't change from state to plan.
+func tsigKeyIDsUpdate(ctx context.Context, plan types.Set, state types.Set) (*[]string, diag.Diagnostics) {
+	if plan.IsNull() || plan.IsUnknown() || plan.Equal(state) {
+		return nil, nil
+	}
+	ids := []string{}
+	diags := plan.ElementsAs(ctx, &ids, false)
+	return &ids, diags
+}
+
+// checkZoneTSIGKeyIDs adds an error on the TSIG key attributes of a zone
+// referring to keys the server doesn't have. Only the attributes changed by
+// the plan are checked, so that plans don't list the keys every time.
+func (client *Client) checkZoneTSIGKeyIDs(ctx context.Context, req resource.ModifyPlanRequest, diags *diag.Diagnostics) {
+	var keys []TSIGKey
+	listed := false
+
+	for _, attribute := range zoneTSIGKeyAttributes {
+		var plan, state types.Set
+		diags.Append(req.Plan.GetAttribute(ctx, path.Root(attribute), &plan)...)
+		if !req.State.Raw.IsNull() {
+			diags.Append(req.State.GetAttribute(ctx, path.Root(attribute), &state)...)
+		}
+		if diags.HasError() || plan.IsNull() || plan.IsUnknown() || plan.Equal(state) {
+			continue
+		}
+
+		p := path.Root(attribute)
+		if len(plan.Elements()) > 0 {
+			client.requireCapability(diags, capabilityTSIGKeys, p)
+		}
+		for _, element := range plan.Elements() {
+			id, ok := element.(types.String)
+			if !ok || id.IsUnknown() || id.IsNull() {
+				continue
+			}
+
+			if !listed {
+				var err error
+				if keys, err = client.ListTSIGKeys(ctx); err != nil {
+					diags.AddAttributeError(p, "Failed to list TSIG keys", fmt.Errorf("couldn't fetch PowerDNS TSIG keys: %w", err).Error())
+					return
+				}
+				listed = true
+			}
+			if detail := checkTSIGKeyID(keys, id.ValueString()); detail != "" {
+				diags.AddAttributeError(p, "Unknown TSIG key", detail)
+			}
+		}
+	}
+}
+
+// checkTSIGKeyID returns why id doesn't refer to one of keys, or an empty
+// string when it does.
+func checkTSIGKeyID(keys []TSIGKey, id string) string {
+	for _, key := range keys {
+		if key.ID == id {
+			return ""
+		}
+		if sameDNSName(key.Name, id) || strings.EqualFold(key.ID, id) {
+			return fmt.Sprintf("TSIG key %q is referred to by its ID, use %q", id, key.ID)
+		}
+	}
+	return fmt.Sprintf("PowerDNS has no TSIG key %q; create it first, e.g. with a powerdns_tsigkey resource", id)
+}
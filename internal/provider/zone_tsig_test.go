@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/MrKeiKun/terraform-provider-powerdns/internal/pdnstest"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneTSIG_Resource(t *testing.T) {
+	server := pdnstest.New(t)
+	checkKeyIDs := func(ids ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			zone, ok := server.Zone("tsig.sysa.abc.")
+			if !ok {
+				return fmt.Errorf("zone tsig.sysa.abc. not found")
+			}
+			if !slices.Equal(zone.SlaveTSIGKeyIDs, ids) {
+				return fmt.Errorf("expected slave TSIG keys %q, got %q", ids, zone.SlaveTSIGKeyIDs)
+			}
+			return nil
+		}
+	}
+
+	testFakeServerUnitTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeServerConfig(server, testPDNSZoneConfigTSIGKeys("[powerdns_tsigkey.axfr.id]")),
+				Check:  checkKeyIDs("tsig-zone."),
+			},
+			{
+				Config: testFakeServerConfig(server, testPDNSZoneConfigTSIGKeys("[]")),
+				Check:  checkKeyIDs(),
+			},
+			{
+				Config:      testFakeServerConfig(server, testPDNSZoneConfigTSIGKeys(`["missing."]`)),
+				ExpectError: regexp.MustCompile("Unknown TSIG key"),
+			},
+		},
+	})
+}
+
+func TestZoneTSIG_CheckTSIGKeyID(t *testing.T) {
+	keys := []TSIGKey{{ID: "axfr.", Name: "axfr"}, {ID: "notify.", Name: "notify"}}
+
+	tests := []struct {
+		name          string
+		id            string
+		expectedError string
+	}{
+		{name: "known ID", id: "notify."},
+		{name: "name instead of ID", id: "axfr", expectedError: `use "axfr."`},
+		{name: "unknown key", id: "missing.", expectedError: "create it first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail := checkTSIGKeyID(keys, tt.id)
+			if tt.expectedError == "" {
+				assert.Empty(t, detail)
+				return
+			}
+			assert.Contains(t, detail, tt.expectedError)
+		})
+	}
+}
+
+func TestZoneTSIG_TSIGKeyIDsUpdate(t *testing.T) {
+	ctx := context.Background()
+	axfr, diags := tsigKeyIDsValue(ctx, []string{"axfr."})
+	require.False(t, diags.HasError())
+	empty, diags := tsigKeyIDsValue(ctx, nil)
+	require.False(t, diags.HasError())
+
+	tests := []struct {
+		name     string
+		plan     types.Set
+		state    types.Set
+		expected *[]string
+	}{
+		{name: "unchanged", plan: axfr, state: axfr},
+		{name: "unknown", plan: types.SetUnknown(types.StringType), state: axfr},
+		{name: "added", plan: axfr, state: empty, expected: &[]string{"axfr."}},
+		{name: "removed", plan: empty, state: axfr, expected: &[]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, diags := tsigKeyIDsUpdate(ctx, tt.plan, tt.state)
+			require.False(t, diags.HasError())
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestZoneTSIG_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeServerClient(t)
+
+	_, err := client.CreateTSIGKey(ctx, TSIGKey{Name: "axfr", Algorithm: "hmac-sha256"})
+	require.NoError(t, err)
+
+	created, err := client.CreateZone(ctx, ZoneInfo{Name: "example.com.", Kind: "Slave", Masters: []string{"192.0.2.1"}, SlaveTSIGKeyIDs: []string{"axfr."}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"axfr."}, created.SlaveTSIGKeyIDs)
+
+	// Key IDs left out of the update are kept
+	masterTSIGKeyIDs := []string{"axfr."}
+	require.NoError(t, client.UpdateZone(ctx, "example.com.", ZoneInfoUpd{Name: "example.com.", Kind: "Slave", MasterTSIGKeyIDs: &masterTSIGKeyIDs}))
+	zone, err := client.GetZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"axfr."}, zone.MasterTSIGKeyIDs)
+	assert.Equal(t, []string{"axfr."}, zone.SlaveTSIGKeyIDs)
+
+	none := []string{}
+	require.NoError(t, client.UpdateZone(ctx, "example.com.", ZoneInfoUpd{Name: "example.com.", Kind: "Slave", SlaveTSIGKeyIDs: &none}))
+	zone, err = client.GetZone(ctx, "example.com.")
+	require.NoError(t, err)
+	assert.Empty(t, zone.SlaveTSIGKeyIDs)
+
+	missing := []string{"missing."}
+	err = client.UpdateZone(ctx, "example.com.", ZoneInfoUpd{Name: "example.com.", Kind: "Slave", SlaveTSIGKeyIDs: &missing})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsValidation())
+}